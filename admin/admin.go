@@ -0,0 +1,42 @@
+// Package admin serves the administration RPCs of the servers.
+package admin
+
+import (
+  "bytes"
+  "context"
+
+  "github.com/_dev/grpc-go-example/admin/adminpb"
+  "github.com/_dev/grpc-go-example/config"
+  "github.com/_dev/grpc-go-example/logging"
+
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// Server implements the AdminService over the configuration of a server.
+type Server struct {
+  adminpb.UnimplementedAdminServiceServer
+  reloader *config.Reloader
+}
+
+// NewServer creates the AdminService reloading through reloader.
+func NewServer(reloader *config.Reloader) *Server {
+  return &Server{reloader: reloader}
+}
+
+func (s *Server) Reload(ctx context.Context, req *adminpb.ReloadRequest) (*adminpb.ReloadResponse, error) {
+  logging.Infof("Received Reload RPC.")
+  cfg, pending, err := s.reloader.Reload()
+  if err != nil {
+    return nil, status.Errorf(codes.FailedPrecondition, "Invalid configuration, keeping the running one: %v", err)
+  }
+
+  var b bytes.Buffer
+  if err := config.Print(&b, cfg); err != nil {
+    return nil, status.Errorf(codes.Internal, "Failed to print the configuration: %v", err)
+  }
+  return &adminpb.ReloadResponse{
+    Config:  b.String(),
+    Pending: pending,
+  }, nil
+}
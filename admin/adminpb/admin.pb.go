@@ -0,0 +1,175 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: admin/adminpb/admin.proto
+
+package adminpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ReloadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadRequest) Reset() {
+	*x = ReloadRequest{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadRequest) ProtoMessage() {}
+
+func (x *ReloadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadRequest.ProtoReflect.Descriptor instead.
+func (*ReloadRequest) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{0}
+}
+
+type ReloadResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// config is the configuration now running, in YAML.
+	Config string `protobuf:"bytes,1,opt,name=config,proto3" json:"config,omitempty"`
+	// pending are the changed settings which only apply after a restart.
+	Pending       []string `protobuf:"bytes,2,rep,name=pending,proto3" json:"pending,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReloadResponse) Reset() {
+	*x = ReloadResponse{}
+	mi := &file_admin_adminpb_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReloadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReloadResponse) ProtoMessage() {}
+
+func (x *ReloadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_adminpb_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReloadResponse.ProtoReflect.Descriptor instead.
+func (*ReloadResponse) Descriptor() ([]byte, []int) {
+	return file_admin_adminpb_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ReloadResponse) GetConfig() string {
+	if x != nil {
+		return x.Config
+	}
+	return ""
+}
+
+func (x *ReloadResponse) GetPending() []string {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+var File_admin_adminpb_admin_proto protoreflect.FileDescriptor
+
+const file_admin_adminpb_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x19admin/adminpb/admin.proto\x12\x05admin\"\x0f\n" +
+	"\rReloadRequest\"B\n" +
+	"\x0eReloadResponse\x12\x16\n" +
+	"\x06config\x18\x01 \x01(\tR\x06config\x12\x18\n" +
+	"\apending\x18\x02 \x03(\tR\apending2G\n" +
+	"\fAdminService\x127\n" +
+	"\x06Reload\x12\x14.admin.ReloadRequest\x1a\x15.admin.ReloadResponse\"\x00B7Z5github.com/_dev/grpc-go-example/admin/adminpb;adminpbb\x06proto3"
+
+var (
+	file_admin_adminpb_admin_proto_rawDescOnce sync.Once
+	file_admin_adminpb_admin_proto_rawDescData []byte
+)
+
+func file_admin_adminpb_admin_proto_rawDescGZIP() []byte {
+	file_admin_adminpb_admin_proto_rawDescOnce.Do(func() {
+		file_admin_adminpb_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_admin_adminpb_admin_proto_rawDesc), len(file_admin_adminpb_admin_proto_rawDesc)))
+	})
+	return file_admin_adminpb_admin_proto_rawDescData
+}
+
+var file_admin_adminpb_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_admin_adminpb_admin_proto_goTypes = []any{
+	(*ReloadRequest)(nil),  // 0: admin.ReloadRequest
+	(*ReloadResponse)(nil), // 1: admin.ReloadResponse
+}
+var file_admin_adminpb_admin_proto_depIdxs = []int32{
+	0, // 0: admin.AdminService.Reload:input_type -> admin.ReloadRequest
+	1, // 1: admin.AdminService.Reload:output_type -> admin.ReloadResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_admin_adminpb_admin_proto_init() }
+func file_admin_adminpb_admin_proto_init() {
+	if File_admin_adminpb_admin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_admin_adminpb_admin_proto_rawDesc), len(file_admin_adminpb_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_admin_adminpb_admin_proto_goTypes,
+		DependencyIndexes: file_admin_adminpb_admin_proto_depIdxs,
+		MessageInfos:      file_admin_adminpb_admin_proto_msgTypes,
+	}.Build()
+	File_admin_adminpb_admin_proto = out.File
+	file_admin_adminpb_admin_proto_goTypes = nil
+	file_admin_adminpb_admin_proto_depIdxs = nil
+}
@@ -0,0 +1,246 @@
+// Package admission caps the concurrent streams of a server, per method and per client,
+// and can shed new streams when the latency of the running ones degrades.
+package admission
+
+import (
+  "context"
+  "fmt"
+  "strconv"
+  "strings"
+  "sync"
+  "time"
+
+  "github.com/_dev/grpc-go-example/clock"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+const (
+  // DefaultMethod is the limit name applying to the methods without a limit of their own.
+  DefaultMethod = "*"
+
+  // defaultMaxStreams is the MaxStreams of the load shedding when none is set.
+  defaultMaxStreams = 1000
+)
+
+// Config tells which streams are admitted, zero values mean no limit.
+type Config struct {
+  // MaxStreamsPerMethod caps the concurrent streams by full method name.
+  MaxStreamsPerMethod map[string]int
+
+  // MaxStreamsPerClient caps the concurrent streams of a client, over all methods.
+  MaxStreamsPerClient int
+
+  // ClientKey identifies the client of a stream, required with MaxStreamsPerClient.
+  ClientKey func(ctx context.Context) string
+
+  // TargetLatency enables the load shedding: while the average time the server takes
+  // to answer a received message is over it, the number of concurrent streams admitted
+  // shrinks, down to MinStreams; while under it, it grows back up to MaxStreams,
+  // which defaults to 1000.
+  TargetLatency time.Duration
+  MinStreams    int
+  MaxStreams    int
+
+  // Clock times the latency, the real clock when nil.
+  Clock clock.Clock
+}
+
+// Stats describes the current state of a Controller.
+type Stats struct {
+  InFlight       int
+  Admitted       int64
+  Rejected       int64
+  AdaptiveLimit  int     // the number of streams admitted by the load shedding.
+  AverageLatency float64 // in milliseconds.
+}
+
+// Controller admits or rejects the streams, with Unavailable, according to its Config.
+type Controller struct {
+  cfg Config
+
+  mu        sync.Mutex
+  perMethod map[string]int
+  perClient map[string]int
+  inFlight  int
+  limit     float64
+  latency   float64 // moving average, in nanoseconds.
+  admitted  int64
+  rejected  int64
+}
+
+// New creates a controller, see Config.
+func New(cfg Config) *Controller {
+  cfg.Clock = clock.Or(cfg.Clock)
+  if cfg.MaxStreams == 0 {
+    cfg.MaxStreams = defaultMaxStreams
+  }
+  if cfg.MinStreams < 1 {
+    cfg.MinStreams = 1
+  }
+  if cfg.MaxStreams < cfg.MinStreams {
+    cfg.MaxStreams = cfg.MinStreams
+  }
+  return &Controller{
+    cfg:       cfg,
+    perMethod: make(map[string]int),
+    perClient: make(map[string]int),
+    limit:     float64(cfg.MaxStreams),
+  }
+}
+
+// ParseLimits reads limits written as "method=streams", separated by commas, as in
+// "/greet.GreetService/GreetEveryone=100,*=500".
+func ParseLimits(s string) (map[string]int, error) {
+  limits := make(map[string]int)
+  for _, part := range strings.Split(s, ",") {
+    part = strings.TrimSpace(part)
+    if part == "" {
+      continue
+    }
+    i := strings.LastIndex(part, "=")
+    if i <= 0 {
+      return nil, fmt.Errorf("invalid stream limit %q, expected method=streams", part)
+    }
+    n, err := strconv.Atoi(part[i+1:])
+    if err != nil || n < 1 {
+      return nil, fmt.Errorf("invalid number of streams in limit %q", part)
+    }
+    limits[part[:i]] = n
+  }
+  return limits, nil
+}
+
+// ServerOption installs the controller on a server.
+func (c *Controller) ServerOption() grpc.ServerOption {
+  return grpc.ChainStreamInterceptor(c.StreamInterceptor)
+}
+
+// StreamInterceptor admits the streams while there is capacity for them.
+func (c *Controller) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  client := ""
+  if c.cfg.ClientKey != nil {
+    client = c.cfg.ClientKey(ss.Context())
+  }
+  if err := c.admit(info.FullMethod, client); err != nil {
+    return err
+  }
+  defer c.release(info.FullMethod, client)
+
+  if c.cfg.TargetLatency <= 0 {
+    return handler(srv, ss)
+  }
+  return handler(srv, &timedStream{ServerStream: ss, observe: c.observe, clock: c.cfg.Clock})
+}
+
+// Stats returns a snapshot of the controller state.
+func (c *Controller) Stats() Stats {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+  return Stats{
+    InFlight:       c.inFlight,
+    Admitted:       c.admitted,
+    Rejected:       c.rejected,
+    AdaptiveLimit:  int(c.limit),
+    AverageLatency: c.latency / float64(time.Millisecond),
+  }
+}
+
+func (c *Controller) admit(method, client string) error {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  max, ok := c.cfg.MaxStreamsPerMethod[method]
+  if !ok {
+    max = c.cfg.MaxStreamsPerMethod[DefaultMethod]
+  }
+  switch {
+  case max > 0 && c.perMethod[method] >= max:
+    c.rejected++
+    return status.Errorf(codes.Unavailable, "Too many concurrent %v streams, at most %v!", method, max)
+  case c.cfg.MaxStreamsPerClient > 0 && c.perClient[client] >= c.cfg.MaxStreamsPerClient:
+    c.rejected++
+    return status.Errorf(codes.Unavailable, "Too many concurrent streams for this client, at most %v!", c.cfg.MaxStreamsPerClient)
+  case c.cfg.TargetLatency > 0 && c.inFlight >= int(c.limit):
+    c.rejected++
+    return status.Error(codes.Unavailable, "Server overloaded, shedding new streams!")
+  }
+
+  c.perMethod[method]++
+  c.perClient[client]++
+  c.inFlight++
+  c.admitted++
+  return nil
+}
+
+func (c *Controller) release(method, client string) {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+  c.perMethod[method]--
+  if c.perMethod[method] == 0 {
+    delete(c.perMethod, method)
+  }
+  c.perClient[client]--
+  if c.perClient[client] == 0 {
+    delete(c.perClient, client)
+  }
+  c.inFlight--
+}
+
+// observe adapts the limit to a latency sample: additive increase while under the
+// target, multiplicative decrease while over it.
+func (c *Controller) observe(latency time.Duration) {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+  if c.latency == 0 {
+    c.latency = float64(latency)
+  } else {
+    c.latency = 0.8*c.latency + 0.2*float64(latency)
+  }
+
+  if c.latency > float64(c.cfg.TargetLatency) {
+    c.limit *= 0.9
+  } else {
+    c.limit += 1 / c.limit
+  }
+  if c.limit < float64(c.cfg.MinStreams) {
+    c.limit = float64(c.cfg.MinStreams)
+  }
+  if c.limit > float64(c.cfg.MaxStreams) {
+    c.limit = float64(c.cfg.MaxStreams)
+  }
+}
+
+// timedStream measures the time between receiving a message and sending the next one.
+type timedStream struct {
+  grpc.ServerStream
+  observe func(time.Duration)
+  clock   clock.Clock
+
+  mu       sync.Mutex
+  received time.Time
+}
+
+func (s *timedStream) RecvMsg(m interface{}) error {
+  err := s.ServerStream.RecvMsg(m)
+  if err == nil {
+    s.mu.Lock()
+    s.received = s.clock.Now()
+    s.mu.Unlock()
+  }
+  return err
+}
+
+func (s *timedStream) SendMsg(m interface{}) error {
+  err := s.ServerStream.SendMsg(m)
+  s.mu.Lock()
+  received := s.received
+  s.received = time.Time{}
+  s.mu.Unlock()
+  if err == nil && !received.IsZero() {
+    s.observe(s.clock.Since(received))
+  }
+  return err
+}
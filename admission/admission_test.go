@@ -0,0 +1,90 @@
+package admission
+
+import (
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/clock"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// nopStream receives and sends messages at once.
+type nopStream struct {
+  grpc.ServerStream
+}
+
+func (nopStream) RecvMsg(m interface{}) error {
+  return nil
+}
+
+func (nopStream) SendMsg(m interface{}) error {
+  return nil
+}
+
+func TestShedWindow(t *testing.T) {
+  clk := clock.NewFake(time.Now())
+  c := New(Config{TargetLatency: 10 * time.Millisecond, MinStreams: 2, MaxStreams: 10, Clock: clk})
+  stream := &timedStream{ServerStream: nopStream{}, observe: c.observe, clock: clk}
+  // answer receives a message and answers it after latency.
+  answer := func(latency time.Duration) int {
+    stream.RecvMsg(nil)
+    clk.Advance(latency)
+    stream.SendMsg(nil)
+    return c.Stats().AdaptiveLimit
+  }
+
+  // Over the target latency, the window shrinks by a tenth at each answer down to
+  // MinStreams.
+  for _, want := range []int{9, 8, 7, 6, 5, 5, 4, 4, 3, 3, 3, 2, 2} {
+    if got := answer(50 * time.Millisecond); got != want {
+      t.Fatalf("AdaptiveLimit = %v after a slow answer, want %v", got, want)
+    }
+  }
+  if got := c.Stats().AverageLatency; got != 50 {
+    t.Fatalf("AverageLatency = %v, want 50", got)
+  }
+
+  // The streams over the window are shed.
+  for i := 0; i < 2; i++ {
+    if err := c.admit("/greet.GreetService/GreetEveryone", ""); err != nil {
+      t.Fatalf("admit() %v within the window = %v", i, err)
+    }
+  }
+  if err := c.admit("/greet.GreetService/GreetEveryone", ""); status.Code(err) != codes.Unavailable {
+    t.Fatalf("admit() over the window = %v, want Unavailable", err)
+  }
+  c.release("/greet.GreetService/GreetEveryone", "")
+  c.release("/greet.GreetService/GreetEveryone", "")
+
+  // Under it, once the average went down, the window grows back by one stream per window
+  // of answers, up to MaxStreams.
+  answers := 0
+  for limit := 2; limit < 10; answers++ {
+    next := answer(time.Millisecond)
+    if next < limit || next > limit+1 {
+      t.Fatalf("AdaptiveLimit = %v after %v fast answers, want %v or %v", next, answers, limit, limit+1)
+    }
+    limit = next
+    if answers > 100 {
+      t.Fatalf("AdaptiveLimit = %v after %v fast answers, want 10", limit, answers)
+    }
+  }
+  if got := answer(time.Millisecond); got != 10 {
+    t.Fatalf("AdaptiveLimit = %v, want at most MaxStreams", got)
+  }
+}
+
+func TestParseLimits(t *testing.T) {
+  limits, err := ParseLimits("/greet.GreetService/GreetEveryone=100, *=500")
+  if err != nil || len(limits) != 2 || limits["/greet.GreetService/GreetEveryone"] != 100 || limits[DefaultMethod] != 500 {
+    t.Fatalf("ParseLimits() = %v, %v", limits, err)
+  }
+  for _, s := range []string{"100", "=100", "*=", "*=0", "*=x"} {
+    if _, err := ParseLimits(s); err == nil {
+      t.Errorf("ParseLimits(%q) = nil error, want one", s)
+    }
+  }
+}
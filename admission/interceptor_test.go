@@ -0,0 +1,144 @@
+package admission_test
+
+import (
+  "context"
+  "io"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/admission"
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/greet/greetpb"
+  "github.com/_dev/grpc-go-example/grpctest"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/status"
+)
+
+const clientHeader = "x-client"
+
+// clientKey identifies the clients by a header of their own.
+func clientKey(ctx context.Context) string {
+  md, _ := metadata.FromIncomingContext(ctx)
+  if values := md.Get(clientHeader); len(values) > 0 {
+    return values[0]
+  }
+  return ""
+}
+
+// waitInFlight waits for the controller to admit n streams, which it does once their
+// first message is on its way.
+func waitInFlight(t *testing.T, c *admission.Controller, n int) {
+  t.Helper()
+  deadline := time.Now().Add(5 * time.Second)
+  for c.Stats().InFlight != n {
+    if time.Now().After(deadline) {
+      t.Fatalf("InFlight = %v, want %v", c.Stats().InFlight, n)
+    }
+    time.Sleep(time.Millisecond)
+  }
+}
+
+func greetEveryone(t *testing.T, ctx context.Context, c greetpb.GreetServiceClient) (greetpb.GreetService_GreetEveryoneClient, error) {
+  t.Helper()
+  stream, err := c.GreetEveryone(ctx)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if err := stream.Send(&greetpb.GreetEveryoneRequest{Greeting: &greetpb.Greeting{FirstName: "Felipe"}}); err != nil {
+    return stream, err
+  }
+  _, err = stream.Recv()
+  return stream, err
+}
+
+func TestMaxStreamsPerMethod(t *testing.T) {
+  const max = 3
+  c := admission.New(admission.Config{
+    MaxStreamsPerMethod: map[string]int{"/greet.GreetService/GreetEveryone": max},
+  })
+  g := grpctest.NewGreet(t, grpctest.GreetConfig(), grpctest.Options{
+    StreamInterceptors: []grpc.StreamServerInterceptor{c.StreamInterceptor},
+  })
+  ctx, cancel := context.WithCancel(context.Background())
+  defer cancel()
+
+  var streams []greetpb.GreetService_GreetEveryoneClient
+  for i := 0; i < max; i++ {
+    stream, err := greetEveryone(t, ctx, g.Client)
+    if err != nil {
+      t.Fatalf("GreetEveryone() %v under the limit = %v", i, err)
+    }
+    streams = append(streams, stream)
+  }
+  waitInFlight(t, c, max)
+  if _, err := greetEveryone(t, ctx, g.Client); status.Code(err) != codes.Unavailable {
+    t.Fatalf("GreetEveryone() over the limit = %v, want Unavailable", err)
+  }
+
+  // The other methods are not limited.
+  many, err := g.Client.GreetManyTimes(ctx, &greetpb.GreetManyTimesRequest{Greeting: &greetpb.Greeting{FirstName: "Felipe"}})
+  if err != nil {
+    t.Fatal(err)
+  }
+  for _, err = many.Recv(); err == nil; _, err = many.Recv() {
+  }
+  if err != io.EOF {
+    t.Fatalf("GreetManyTimes() without a limit = %v", err)
+  }
+
+  // A stream ending makes room for another.
+  streams[0].CloseSend()
+  if _, err := streams[0].Recv(); err != io.EOF {
+    t.Fatalf("GreetEveryone() closed = %v, want EOF", err)
+  }
+  waitInFlight(t, c, max-1)
+  if _, err := greetEveryone(t, ctx, g.Client); err != nil {
+    t.Fatalf("GreetEveryone() once a stream ended = %v", err)
+  }
+  if stats := c.Stats(); stats.Rejected != 1 {
+    t.Fatalf("Rejected = %v, want 1", stats.Rejected)
+  }
+}
+
+func findMaximum(t *testing.T, ctx context.Context, c calculatorpb.CalculatorServiceClient, client string) error {
+  t.Helper()
+  stream, err := c.FindMaximum(metadata.AppendToOutgoingContext(ctx, clientHeader, client))
+  if err != nil {
+    t.Fatal(err)
+  }
+  if err := stream.Send(&calculatorpb.FindMaximumRequest{Number: 1}); err != nil {
+    return err
+  }
+  _, err = stream.Recv()
+  return err
+}
+
+func TestMaxStreamsPerClient(t *testing.T) {
+  const max = 2
+  c := admission.New(admission.Config{
+    MaxStreamsPerClient: max,
+    ClientKey:           clientKey,
+  })
+  calc := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{
+    StreamInterceptors: []grpc.StreamServerInterceptor{c.StreamInterceptor},
+  })
+  ctx, cancel := context.WithCancel(context.Background())
+  defer cancel()
+
+  for i := 0; i < max; i++ {
+    if err := findMaximum(t, ctx, calc.Client, "a"); err != nil {
+      t.Fatalf("FindMaximum() %v under the limit = %v", i, err)
+    }
+  }
+  waitInFlight(t, c, max)
+  if err := findMaximum(t, ctx, calc.Client, "a"); status.Code(err) != codes.Unavailable {
+    t.Fatalf("FindMaximum() over the limit of the client = %v, want Unavailable", err)
+  }
+  // The limit is per client.
+  if err := findMaximum(t, ctx, calc.Client, "b"); err != nil {
+    t.Fatalf("FindMaximum() of another client = %v", err)
+  }
+}
@@ -0,0 +1,111 @@
+// Package cache provides an in-memory LRU cache whose entries also expire after a TTL.
+package cache
+
+import (
+  "container/list"
+  "sync"
+  "time"
+
+  "github.com/_dev/grpc-go-example/clock"
+)
+
+// Stats counts the lookups of a Cache.
+type Stats struct {
+  Hits      int64
+  Misses    int64
+  Evictions int64 // entries dropped because the cache was full.
+  Expired   int64 // entries dropped because their TTL elapsed.
+}
+
+// Cache is safe for concurrent use.
+type Cache struct {
+  mu         sync.Mutex
+  maxEntries int
+  ttl        time.Duration
+  clock      clock.Clock
+  order      *list.List // front is the most recently used.
+  items      map[string]*list.Element
+  stats      Stats
+}
+
+type entry struct {
+  key     string
+  value   interface{}
+  expires time.Time
+}
+
+// New creates a cache holding at most maxEntries entries, each for at most ttl as told
+// by clk, the real clock when nil. A zero ttl keeps entries until they are evicted.
+func New(maxEntries int, ttl time.Duration, clk clock.Clock) *Cache {
+  return &Cache{
+    maxEntries: maxEntries,
+    ttl:        ttl,
+    clock:      clock.Or(clk),
+    order:      list.New(),
+    items:      make(map[string]*list.Element),
+  }
+}
+
+// Get returns the value stored for key, if present and not expired.
+func (c *Cache) Get(key string) (interface{}, bool) {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  elem, ok := c.items[key]
+  if !ok {
+    c.stats.Misses++
+    return nil, false
+  }
+  e := elem.Value.(*entry)
+  if c.ttl > 0 && c.clock.Now().After(e.expires) {
+    c.remove(elem)
+    c.stats.Expired++
+    c.stats.Misses++
+    return nil, false
+  }
+  c.order.MoveToFront(elem)
+  c.stats.Hits++
+  return e.value, true
+}
+
+// Add stores value for key, evicting the least recently used entry when the cache is full.
+func (c *Cache) Add(key string, value interface{}) {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+
+  if c.maxEntries <= 0 {
+    return
+  }
+  expires := c.clock.Now().Add(c.ttl)
+  if elem, ok := c.items[key]; ok {
+    e := elem.Value.(*entry)
+    e.value = value
+    e.expires = expires
+    c.order.MoveToFront(elem)
+    return
+  }
+  c.items[key] = c.order.PushFront(&entry{key: key, value: value, expires: expires})
+  for c.order.Len() > c.maxEntries {
+    c.remove(c.order.Back())
+    c.stats.Evictions++
+  }
+}
+
+// Len returns the number of entries, including the expired ones not yet dropped.
+func (c *Cache) Len() int {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+  return c.order.Len()
+}
+
+// Stats returns a snapshot of the cache counters.
+func (c *Cache) Stats() Stats {
+  c.mu.Lock()
+  defer c.mu.Unlock()
+  return c.stats
+}
+
+func (c *Cache) remove(elem *list.Element) {
+  c.order.Remove(elem)
+  delete(c.items, elem.Value.(*entry).key)
+}
@@ -0,0 +1,75 @@
+package cache
+
+import (
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/clock"
+)
+
+func TestExpiry(t *testing.T) {
+  clk := clock.NewFake(time.Now())
+  c := New(10, time.Minute, clk)
+  c.Add("a", 1)
+
+  clk.Advance(time.Minute)
+  if v, ok := c.Get("a"); !ok || v != 1 {
+    t.Fatalf("Get() at the TTL = %v, %v, want 1", v, ok)
+  }
+  clk.Advance(time.Nanosecond)
+  if _, ok := c.Get("a"); ok {
+    t.Fatal("Get() past the TTL found the entry")
+  }
+
+  // Adding again renews the entry.
+  c.Add("b", 1)
+  clk.Advance(50 * time.Second)
+  c.Add("b", 2)
+  clk.Advance(50 * time.Second)
+  if v, ok := c.Get("b"); !ok || v != 2 {
+    t.Fatalf("Get() of a renewed entry = %v, %v, want 2", v, ok)
+  }
+
+  want := Stats{Hits: 2, Misses: 1, Expired: 1}
+  if got := c.Stats(); got != want {
+    t.Fatalf("Stats() = %+v, want %+v", got, want)
+  }
+}
+
+func TestNoTTL(t *testing.T) {
+  clk := clock.NewFake(time.Now())
+  c := New(10, 0, clk)
+  c.Add("a", 1)
+  clk.Advance(24 * time.Hour)
+  if _, ok := c.Get("a"); !ok {
+    t.Fatal("Get() without a TTL lost the entry")
+  }
+}
+
+func TestEviction(t *testing.T) {
+  c := New(2, 0, nil)
+  c.Add("a", 1)
+  c.Add("b", 2)
+  c.Get("a") // b is now the least recently used.
+  c.Add("c", 3)
+
+  if _, ok := c.Get("b"); ok {
+    t.Fatal("Get() found the least recently used entry, want it evicted")
+  }
+  for _, key := range []string{"a", "c"} {
+    if _, ok := c.Get(key); !ok {
+      t.Fatalf("Get(%q) lost a recent entry", key)
+    }
+  }
+  if c.Len() != 2 || c.Stats().Evictions != 1 {
+    t.Fatalf("Len() = %v with %v evictions, want 2 and 1", c.Len(), c.Stats().Evictions)
+  }
+}
+
+func TestDisabled(t *testing.T) {
+  c := New(0, time.Minute, nil)
+  c.Add("a", 1)
+  if _, ok := c.Get("a"); ok || c.Len() != 0 {
+    t.Fatal("A cache of no entries kept one")
+  }
+}
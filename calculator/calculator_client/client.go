@@ -2,21 +2,91 @@ package main
 
 import (
   "context"
+  "flag"
   "fmt"
   "io"
   "log"
+  "os"
+  "strconv"
   "time"
 
-  "github.com/grpc-go-course/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/client"
+  "github.com/_dev/grpc-go-example/compression"
 
   "google.golang.org/grpc"
   "google.golang.org/grpc/codes"
   "google.golang.org/grpc/status"
+  "google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func main() {
+  backends := flag.String("backends", "localhost:50051", "servers as addresses separated by commas, or a target such as dns:///host:port or file:///path/endpoints.json")
+  balancer := flag.String("balancer", "round-robin", "how the calls are spread over the servers: round-robin or least-request")
+  healthCheck := flag.Bool("health-check", true, "leave out the servers whose health service does not report serving")
+  retryInterceptor := flag.Bool("retry-interceptor", false, "retry through an interceptor instead of the gRPC service config")
+  hedgeDelay := flag.Duration("hedge-delay", 0, "hedge the Sum calls not answered within this delay, 0 disables hedging")
+  maxHedges := flag.Int("max-hedges", 2, "most hedges sent per call")
+  interactive := flag.Bool("interactive", false, "find the maximum of the numbers typed on stdin through FindMaximum instead of running the examples")
+  historyFile := flag.String("history-file", client.HistoryFile(".calculator_history"), "file keeping the numbers typed in interactive mode, none when empty")
+  compressionRules := flag.String("compression", "", "compressors of the calls as method=compressor separated by commas, * for the other methods: gzip, zstd or identity; none by default")
+  compressionMinSize := flag.Int("compression-min-size", 1024, "size in bytes under which the unary requests are sent uncompressed")
+  flag.Parse()
+
   fmt.Println("Client running...")
-  cc, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
+
+  // Retrying the transient failures, through the service config unless told otherwise...
+  var opts []grpc.DialOption
+  retries := &client.RetryConfig{Default: client.DefaultRetryPolicy}
+  if *retryInterceptor {
+    opts = client.WithRetryInterceptor(*retries)
+    retries = nil
+  }
+
+  // Balancing the calls over the healthy backends...
+  policy, ok := client.Policies[*balancer]
+  if !ok {
+    log.Fatalf("Unknown balancing policy: %v", *balancer)
+  }
+  serviceConfig, err := client.WithServiceConfig(retries, &client.BalanceConfig{Policy: policy, HealthCheck: *healthCheck})
+  if err != nil {
+    log.Fatalf("Invalid service config: %v", err)
+  }
+  target, targetOpts, err := client.Target(*backends)
+  if err != nil {
+    log.Fatalf("Invalid backends: %v", err)
+  }
+  opts = append(opts, serviceConfig, grpc.WithInsecure())
+  opts = append(opts, targetOpts...)
+
+  // Hedging the latency-sensitive calls, each hedge being retried on its own...
+  var hedger *client.Hedger
+  if *hedgeDelay > 0 {
+    hedger = client.NewHedger(client.HedgeConfig{
+      Methods:       []string{"/calculator.CalculatorService/Sum"},
+      Delay:         *hedgeDelay,
+      MaxHedges:     *maxHedges,
+      NonFatalCodes: []codes.Code{codes.Unavailable},
+    })
+    opts = append(client.WithHedging(hedger), opts...)
+  }
+
+  // Failing fast while the server keeps failing, the breaker comes first so that it sees
+  // the outcome of the retried calls...
+  breakerConfig := client.DefaultBreakerConfig
+  breakerConfig.OnStateChange = func(method string, from, to client.BreakerState) {
+    log.Printf("Circuit of %v went from %v to %v.", method, from, to)
+  }
+  opts = append(client.WithBreaker(client.NewBreaker(breakerConfig)), opts...)
+
+  // Compressing the requests over the size threshold, the server answers in kind...
+  compressors, err := compression.ParseMethods(*compressionRules)
+  if err != nil {
+    log.Fatalf("Invalid compression: %v", err)
+  }
+  opts = append(opts, compression.WithCompression(compression.Policy{Methods: compressors, MinSize: *compressionMinSize})...)
+
+  cc, err := grpc.Dial(target, opts...)
   if err != nil {
     log.Fatalf("Could not connect: %v", err)
   }
@@ -28,6 +98,11 @@ func main() {
   c := calculatorpb.NewCalculatorServiceClient(cc)
   // fmt.Printf("Created client: %f", c)
 
+  if *interactive {
+    doInteractiveStreaming(c, *historyFile)
+    return
+  }
+
   log.Println(">>")
   doUnary(c)
   log.Println("<<")
@@ -47,6 +122,33 @@ func main() {
   log.Println(">>")
   doSquareRoot(c)
   log.Println("<<")
+
+  log.Println(">>")
+  doSquareRootStreaming(c)
+  log.Println("<<")
+
+  log.Println(">>")
+  doComplex(c)
+  log.Println("<<")
+
+  log.Println(">>")
+  doMatrix(c)
+  log.Println("<<")
+
+  log.Println(">>")
+  doSession(c)
+  log.Println("<<")
+
+  log.Println(">>")
+  doHistory(c)
+  log.Println("<<")
+
+  if hedger != nil {
+    log.Printf("Hedging stats: %+v", hedger.Stats())
+  }
+  if len(compressors) > 0 {
+    log.Printf("Compression stats: %+v", compression.AllStats())
+  }
 }
 
 func doUnary(c calculatorpb.CalculatorServiceClient) {
@@ -159,8 +261,44 @@ func doBidirectionalStreaming(c calculatorpb.CalculatorServiceClient) {
   log.Println("BIDIRECTIONAL STREAMING - Completed.")
 }
 
+func doInteractiveStreaming(c calculatorpb.CalculatorServiceClient, historyFile string) {
+  log.Println("INTERACTIVE STREAMING - Starting, type a number per line and Ctrl-D to end...")
+
+  stream, err := c.FindMaximum(context.Background())
+  if err != nil {
+    log.Fatalf("Error while open stream: %v", err)
+  }
+
+  repl := &client.REPL{
+    Prompt:      "max> ",
+    Out:         os.Stdout,
+    HistoryFile: historyFile,
+    Send: func(line string) error {
+      number, err := strconv.ParseInt(line, 10, 32)
+      if err != nil {
+        return fmt.Errorf("%q is not a number", line)
+      }
+      return stream.Send(&calculatorpb.FindMaximumRequest{
+        Number: int32(number),
+      })
+    },
+    Recv: func() (string, error) {
+      res, err := stream.Recv()
+      return fmt.Sprintf("Maximum: %v", res.GetMaximum()), err
+    },
+    CloseSend: stream.CloseSend,
+  }
+  if err := repl.Run(os.Stdin); err != nil {
+    s := status.Convert(err)
+    log.Printf("The server ended the stream with %v: %v", s.Code(), s.Message())
+    return
+  }
+
+  log.Println("INTERACTIVE STREAMING - Completed.")
+}
+
 func doSquareRoot(c calculatorpb.CalculatorServiceClient) {
-  log.Println("SQUAREROOT STREAMING - Starting...")
+  log.Println("SQUAREROOT UNARY - Starting...")
 
   // correct call
   doErrorCall(c, 10)
@@ -168,11 +306,11 @@ func doSquareRoot(c calculatorpb.CalculatorServiceClient) {
   // error call
   doErrorCall(c, -2)
 
-  log.Println("SQUAREROOT STREAMING - Completed.")
+  log.Println("SQUAREROOT UNARY - Completed.")
 }
 
 func doErrorCall(c calculatorpb.CalculatorServiceClient, n int32) {
-  res, err := c.SquareRoot(context.Background(), &calculatorpb.SquareRootRequest{Number: n})
+  res, err := c.SquareRootUnary(context.Background(), &calculatorpb.SquareRootRequest{Number: n})
   if err != nil {
     err, ok := status.FromError(err)
     if ok {
@@ -188,3 +326,287 @@ func doErrorCall(c calculatorpb.CalculatorServiceClient, n int32) {
   }
   log.Printf("Result of square root of %v: %v\n", n, res.GetNumberRoot())
 }
+
+func doSquareRootStreaming(c calculatorpb.CalculatorServiceClient) {
+  log.Println("SQUAREROOT STREAMING - Starting...")
+
+  stream, err := c.SquareRoot(context.Background())
+  if err != nil {
+    log.Fatalf("Error while open stream: %v", err)
+  }
+
+  waitc := make(chan struct{})
+
+  // send go routine
+  go func() {
+    numbers := []int32{10, -2, 25, 2, -9, 144}
+    for _, number := range numbers {
+      log.Printf("Sending number: %v\n", number)
+      stream.Send(&calculatorpb.SquareRootRequest{
+        Number: number,
+      })
+    }
+    stream.CloseSend()
+  }()
+
+  // receive go routing
+  go func() {
+    for {
+      res, err := stream.Recv()
+      if err == io.EOF {
+        break // It has reached the end of the stream.
+      }
+      if err != nil {
+        log.Fatalf("Error while receiving stream: %v", err)
+        break // It has reached the end of the stream.
+      }
+      if e := res.GetError(); e != nil {
+        log.Printf("Error for square root of %v: %v - %v.\n", res.GetNumber(), codes.Code(e.GetCode()), e.GetMessage())
+        continue
+      }
+      log.Printf("Result of square root of %v: %v\n", res.GetNumber(), res.GetNumberRoot())
+    }
+    close(waitc)
+  }()
+
+  <-waitc
+
+  log.Println("SQUAREROOT STREAMING - Completed.")
+}
+
+func doComplex(c calculatorpb.CalculatorServiceClient) {
+  log.Println("COMPLEX - Starting...")
+
+  root, err := c.SquareRootUnary(context.Background(), &calculatorpb.SquareRootRequest{Number: -4, AllowComplex: true})
+  if err != nil {
+    log.Fatalf("Error while calling SquareRootUnary RPC: %v", err)
+  }
+  log.Printf("Complex square root of -4: %v", root.GetComplexRoot())
+
+  first := &calculatorpb.Complex{Real: 3, Imaginary: 4}
+  second := &calculatorpb.Complex{Real: 1, Imaginary: -2}
+
+  sum, err := c.ComplexAdd(context.Background(), &calculatorpb.ComplexAddRequest{First: first, Second: second})
+  if err != nil {
+    log.Fatalf("Error while calling ComplexAdd RPC: %v", err)
+  }
+  log.Printf("Response from ComplexAdd: %v", sum.GetResult())
+
+  product, err := c.ComplexMultiply(context.Background(), &calculatorpb.ComplexMultiplyRequest{First: first, Second: second})
+  if err != nil {
+    log.Fatalf("Error while calling ComplexMultiply RPC: %v", err)
+  }
+  log.Printf("Response from ComplexMultiply: %v", product.GetResult())
+
+  quotient, err := c.ComplexDivide(context.Background(), &calculatorpb.ComplexDivideRequest{Dividend: first, Divisor: second})
+  if err != nil {
+    log.Fatalf("Error while calling ComplexDivide RPC: %v", err)
+  }
+  log.Printf("Response from ComplexDivide: %v", quotient.GetResult())
+
+  modulus, err := c.ComplexModulus(context.Background(), &calculatorpb.ComplexModulusRequest{Number: first})
+  if err != nil {
+    log.Fatalf("Error while calling ComplexModulus RPC: %v", err)
+  }
+  log.Printf("Response from ComplexModulus: %v", modulus.GetModulus())
+
+  argument, err := c.ComplexArgument(context.Background(), &calculatorpb.ComplexArgumentRequest{Number: first})
+  if err != nil {
+    log.Fatalf("Error while calling ComplexArgument RPC: %v", err)
+  }
+  log.Printf("Response from ComplexArgument: %v", argument.GetArgument())
+
+  stream, err := c.ComplexRoots(context.Background(), &calculatorpb.ComplexRootsRequest{Number: first, Degree: 3})
+  if err != nil {
+    log.Fatalf("Error while calling ComplexRoots RPC: %v", err)
+  }
+  for { // Runs in a loop to consume the entire stream.
+    msg, err := stream.Recv()
+    if err == io.EOF {
+      break // It has reached the end of the stream.
+    }
+    if err != nil {
+      log.Fatalf("Error while reading stream: %v", err)
+    }
+    log.Printf("Response from ComplexRoots: %v", msg.GetRoot())
+  }
+
+  log.Println("COMPLEX - Completed.")
+}
+
+func doMatrix(c calculatorpb.CalculatorServiceClient) {
+  log.Println("MATRIX - Starting...")
+
+  first := &calculatorpb.Matrix{
+    Rows: []*calculatorpb.Vector{
+      {Values: []float64{2, 1}},
+      {Values: []float64{1, 3}},
+    },
+  }
+  second := &calculatorpb.Matrix{
+    Rows: []*calculatorpb.Vector{
+      {Values: []float64{1, 0}},
+      {Values: []float64{4, 5}},
+    },
+  }
+
+  sum, err := c.MatrixAdd(context.Background(), &calculatorpb.MatrixAddRequest{First: first, Second: second})
+  if err != nil {
+    log.Fatalf("Error while calling MatrixAdd RPC: %v", err)
+  }
+  log.Printf("Response from MatrixAdd: %v", sum.GetResult())
+
+  product, err := c.MatrixMultiply(context.Background(), &calculatorpb.MatrixMultiplyRequest{First: first, Second: second})
+  if err != nil {
+    log.Fatalf("Error while calling MatrixMultiply RPC: %v", err)
+  }
+  log.Printf("Response from MatrixMultiply: %v", product.GetResult())
+
+  transpose, err := c.MatrixTranspose(context.Background(), &calculatorpb.MatrixTransposeRequest{Matrix: second})
+  if err != nil {
+    log.Fatalf("Error while calling MatrixTranspose RPC: %v", err)
+  }
+  log.Printf("Response from MatrixTranspose: %v", transpose.GetResult())
+
+  determinant, err := c.MatrixDeterminant(context.Background(), &calculatorpb.MatrixDeterminantRequest{Matrix: first})
+  if err != nil {
+    log.Fatalf("Error while calling MatrixDeterminant RPC: %v", err)
+  }
+  log.Printf("Response from MatrixDeterminant: %v", determinant.GetDeterminant())
+
+  inverse, err := c.MatrixInverse(context.Background(), &calculatorpb.MatrixInverseRequest{Matrix: first})
+  if err != nil {
+    log.Fatalf("Error while calling MatrixInverse RPC: %v", err)
+  }
+  log.Printf("Response from MatrixInverse: %v", inverse.GetResult())
+
+  solution, err := c.SolveLinearSystem(context.Background(), &calculatorpb.SolveLinearSystemRequest{
+    Coefficients: first,
+    Constants:    &calculatorpb.Vector{Values: []float64{3, 5}},
+  })
+  if err != nil {
+    log.Fatalf("Error while calling SolveLinearSystem RPC: %v", err)
+  }
+  log.Printf("Response from SolveLinearSystem: %v", solution.GetSolution())
+
+  // Mismatched dimensions are rejected with InvalidArgument.
+  _, err = c.MatrixMultiply(context.Background(), &calculatorpb.MatrixMultiplyRequest{
+    First:  first,
+    Second: &calculatorpb.Matrix{Rows: []*calculatorpb.Vector{{Values: []float64{1, 2, 3}}}},
+  })
+  if statusErr, ok := status.FromError(err); ok && statusErr.Code() == codes.InvalidArgument {
+    log.Printf("Error message from server: %v - %v.\n", statusErr.Code(), statusErr.Message())
+  }
+
+  stream, err := c.MatrixDeterminantRows(context.Background())
+  if err != nil {
+    log.Fatalf("Error while open stream: %v", err)
+  }
+  for _, row := range [][]float64{{1, 2, 3}, {0, 1, 4}, {5, 6, 0}} {
+    log.Printf("Sending row: %v\n", row)
+    stream.Send(&calculatorpb.MatrixDeterminantRowsRequest{
+      Row: &calculatorpb.Vector{Values: row},
+    })
+  }
+  res, err := stream.CloseAndRecv()
+  if err != nil {
+    log.Fatalf("Error while receiving response: %v", err)
+  }
+  log.Printf("Response from MatrixDeterminantRows: %v", res.GetDeterminant())
+
+  log.Println("MATRIX - Completed.")
+}
+
+func doSession(c calculatorpb.CalculatorServiceClient) {
+  log.Println("SESSION - Starting...")
+
+  session, err := c.CreateSession(context.Background(), &calculatorpb.CreateSessionRequest{})
+  if err != nil {
+    log.Fatalf("Error while calling CreateSession RPC: %v", err)
+  }
+  log.Printf("Created session: %v (expires after %v seconds unused)", session.GetSessionId(), session.GetTtlSeconds())
+
+  for _, expression := range []string{"x = 3 * (2 + 1)", "ans / 2", "$1 + x"} {
+    res, err := c.Evaluate(context.Background(), &calculatorpb.EvaluateRequest{
+      SessionId:  session.GetSessionId(),
+      Expression: expression,
+    })
+    if err != nil {
+      log.Fatalf("Error while calling Evaluate RPC: %v", err)
+    }
+    log.Printf("$%v: %v = %v", res.GetIndex(), expression, res.GetResult())
+  }
+
+  _, err = c.CloseSession(context.Background(), &calculatorpb.CloseSessionRequest{SessionId: session.GetSessionId()})
+  if err != nil {
+    log.Fatalf("Error while calling CloseSession RPC: %v", err)
+  }
+
+  stream, err := c.Session(context.Background())
+  if err != nil {
+    log.Fatalf("Error while open stream: %v", err)
+  }
+
+  waitc := make(chan struct{})
+
+  // send go routine
+  go func() {
+    for _, expression := range []string{"r = 2", "pi = 3.14159", "pi * r ^ 2", "unknown + 1", "ans * 2"} {
+      log.Printf("Sending expression: %v\n", expression)
+      stream.Send(&calculatorpb.SessionRequest{
+        Expression: expression,
+      })
+    }
+    stream.CloseSend()
+  }()
+
+  // receive go routing
+  go func() {
+    for {
+      res, err := stream.Recv()
+      if err == io.EOF {
+        break // It has reached the end of the stream.
+      }
+      if err != nil {
+        log.Fatalf("Error while receiving stream: %v", err)
+        break // It has reached the end of the stream.
+      }
+      if e := res.GetError(); e != nil {
+        log.Printf("Error for %v: %v - %v.\n", res.GetExpression(), codes.Code(e.GetCode()), e.GetMessage())
+        continue
+      }
+      log.Printf("$%v: %v = %v", res.GetEvaluation().GetIndex(), res.GetExpression(), res.GetEvaluation().GetResult())
+    }
+    close(waitc)
+  }()
+
+  <-waitc
+
+  log.Println("SESSION - Completed.")
+}
+
+func doHistory(c calculatorpb.CalculatorServiceClient) {
+  log.Println("HISTORY - Starting...")
+
+  // Walking the pages of the last hour of SquareRootUnary calls.
+  req := &calculatorpb.ListHistoryRequest{
+    Method:    "SquareRootUnary",
+    StartTime: timestamppb.New(time.Now().Add(-time.Hour)),
+    PageSize:  2,
+  }
+  for {
+    res, err := c.ListHistory(context.Background(), req)
+    if err != nil {
+      log.Fatalf("Error while calling ListHistory RPC: %v", err)
+    }
+    for _, entry := range res.GetEntries() {
+      log.Printf("History entry %v: %v %v => %v (%v) from %v at %v", entry.GetId(), entry.GetMethod(), entry.GetInputs(), entry.GetResult(), codes.Code(entry.GetCode()), entry.GetCaller(), entry.GetTimestamp().AsTime())
+    }
+    if res.GetNextPageToken() == "" {
+      break
+    }
+    req.PageToken = res.GetNextPageToken()
+  }
+
+  log.Println("HISTORY - Completed.")
+}
@@ -1,129 +1,184 @@
 package main
 
 import (
-  "context"
-  "fmt"
-  "io"
+  "expvar"
   "log"
-  "math"
   "net"
-
+  "net/http"
+  "os"
+  "os/signal"
+  "syscall"
+
+  "github.com/_dev/grpc-go-example/admin"
+  "github.com/_dev/grpc-go-example/admin/adminpb"
+  "github.com/_dev/grpc-go-example/admission"
   "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/calculator/calculatorservice"
+  "github.com/_dev/grpc-go-example/clock"
+  "github.com/_dev/grpc-go-example/compression"
+  "github.com/_dev/grpc-go-example/config"
+  "github.com/_dev/grpc-go-example/deadline"
+  "github.com/_dev/grpc-go-example/idempotency"
+  "github.com/_dev/grpc-go-example/logging"
+  "github.com/_dev/grpc-go-example/ratelimit"
 
   "google.golang.org/grpc"
-  "google.golang.org/grpc/codes"
-  "google.golang.org/grpc/status"
+  "google.golang.org/grpc/health"
+  healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
-type server struct{}
+// maxIdempotencyKeys bounds the responses kept for replay.
+const maxIdempotencyKeys = 10000
 
-func (*server) Sum(ctx context.Context, req *calculatorpb.SumRequest) (*calculatorpb.SumResponse, error) {
-  fmt.Printf("Received Sum RPC: %v\n", req)
-  firstNumber := req.FirstNumber
-  secondNumber := req.SecondNumber
-  sum := firstNumber + secondNumber
-  res := &calculatorpb.SumResponse{
-    SumResult: sum,
+func main() {
+  // Loading the configuration, flags over environment over file over defaults...
+  cfg := calculatorservice.DefaultConfig()
+  src, err := config.Load(&cfg, "CALCULATOR", os.Args[1:])
+  if err != nil {
+    log.Fatalf("Invalid configuration: %v", err)
   }
-  return res, nil
-}
 
-func (*server) PrimeNumberDecomposition(req *calculatorpb.PrimeNumberDecompositionRequest, stream calculatorpb.CalculatorService_PrimeNumberDecompositionServer) error {
-  log.Printf("Received PrimeNumberDecomposition RPC: %v\n", req)
-  number := req.GetNumber()
-  divisor := int64(2)
-
-  for number > 1 {
-    if number%divisor == 0 {
-      stream.Send(&calculatorpb.PrimeNumberDecompositionResponse{
-        PrimeFactor: divisor,
-      })
-      number = number / divisor
-    } else {
-      divisor++
-      log.Printf("Divisor has increased to %v\n", divisor)
-    }
+  log.Println("SERVER - Starting...")
+
+  // Creating the port of GRPC server...
+  list, err := net.Listen("tcp", cfg.Addr)
+  if err != nil {
+    log.Fatalf("Failed to listen: %v", err)
   }
-  return nil
-}
 
-func (*server) ComputeAverage(stream calculatorpb.CalculatorService_ComputeAverageServer) error {
-  log.Println("Received ComputeAverage RPC.")
-
-  sum := int32(0)
-  count := 0
-  for {
-    req, err := stream.Recv()
-    if err == io.EOF {
-      average := float64(sum) / float64(count)
-      return stream.SendAndClose(&calculatorpb.ComputeAverageResponse{
-        Average: average,
-      })
-    }
-    if err != nil {
-      log.Fatalf("Error while reading client stream: %v", err)
-    }
-    sum += req.GetNumber()
-    count++
+  // Creating the service, with its cache and history store...
+  srv, err := calculatorservice.NewServer(cfg, clock.Real)
+  if err != nil {
+    log.Fatalf("Failed to create the service: %v", err)
+  }
+  defer srv.Close()
+  if cfg.Cache.Size > 0 {
+    expvar.Publish("calculator_cache", expvar.Func(func() interface{} {
+      return srv.CacheStats()
+    }))
   }
-}
 
-func (*server) FindMaximum(stream calculatorpb.CalculatorService_FindMaximumServer) error {
-  log.Println("Received FindMaximum RPC.")
+  // Serving the metrics...
+  if cfg.MetricsAddr != "" {
+    go func() {
+      if err := http.ListenAndServe(cfg.MetricsAddr, nil); err != nil {
+        log.Printf("Failed to serve metrics: %v", err)
+      }
+    }()
+  }
 
-  maximum := int32(0)
-  for {
-    req, err := stream.Recv()
-    if err == io.EOF {
-      return nil
-    }
-    if err != nil {
-      log.Fatalf("Error while reading client stream: %v", err)
+  // Recording the calls in the history, and answering the calls of the disabled methods...
+  unaryInterceptors := srv.UnaryInterceptors()
+  streamInterceptors := srv.StreamInterceptors()
+
+  // Limiting the rate of calls of each client, the rules may change on reload...
+  keyFunc := ratelimit.KeyFuncs[cfg.RateLimitKey]
+  limiter := ratelimit.NewLimiter(keyFunc, nil, clock.Real)
+  defer limiter.Close()
+  unaryInterceptors = append(unaryInterceptors, limiter.UnaryInterceptor)
+  streamInterceptors = append(streamInterceptors, limiter.StreamInterceptor)
+
+  // Bounding the deadlines of the calls, giving one to those arriving without...
+  deadlines := deadline.New(
+    deadline.Bounds{Default: cfg.Deadlines.Default, Max: cfg.Deadlines.Max},
+    deadline.Bounds{Default: cfg.Deadlines.StreamDefault, Max: cfg.Deadlines.StreamMax},
+  )
+  unaryInterceptors = append(unaryInterceptors, deadlines.UnaryInterceptor)
+  streamInterceptors = append(streamInterceptors, deadlines.StreamInterceptor)
+
+  // Compressing the responses over the size threshold...
+  compressors, _ := compression.ParseMethods(cfg.Compression.Methods)
+  compressionPolicy := compression.Policy{Methods: compressors, MinSize: cfg.Compression.MinSize}
+  unaryInterceptors = append(unaryInterceptors, compressionPolicy.UnaryServerInterceptor)
+  streamInterceptors = append(streamInterceptors, compressionPolicy.StreamServerInterceptor)
+  expvar.Publish("calculator_compression", expvar.Func(func() interface{} {
+    return compression.AllStats()
+  }))
+
+  // Applying the live settings, now and on each reload...
+  apply := func(c config.Validator) error {
+    cfg := c.(*calculatorservice.Config)
+    if err := srv.Apply(*cfg); err != nil {
       return err
     }
-    number := req.GetNumber()
-    if number > maximum {
-      maximum = number
-      err = stream.Send(&calculatorpb.FindMaximumResponse{
-        Maximum: maximum,
-      })
-      if err != nil {
-        log.Fatalf("Error while sending client stream: %v", err)
-        return err
-      }
-    }
+    level, _ := logging.ParseLevel(cfg.LogLevel)
+    rules, _ := ratelimit.ParseRules(cfg.RateLimit)
+    logging.SetLevel(level)
+    limiter.SetRules(rules)
+    return nil
   }
-}
-
-func (*server) SquareRoot(ctx context.Context, req *calculatorpb.SquareRootRequest) (*calculatorpb.SquareRootResponse, error) {
-  log.Println("Received SquareRoot RPC.")
-
-  number := req.GetNumber()
-  if number < 0 {
-    return nil, status.Errorf(
-      codes.InvalidArgument,
-      fmt.Sprintf("Received a negative number: %v!", number),
-    )
+  if err := apply(&cfg); err != nil {
+    log.Fatalf("Invalid configuration: %v", err)
   }
-  return &calculatorpb.SquareRootResponse{
-    NumberRoot: math.Sqrt(float64(number)),
-  }, nil
-}
-
-func main() {
-  log.Println("SERVER - Starting...")
-
-  // Creating the port of GRPC server...
-  list, err := net.Listen("tcp", "0.0.0.0:50051")
-  if err != nil {
-    log.Fatalf("Failed to listen: %v", err)
+  reloader := config.NewReloader(src, &cfg, apply)
+  go reloader.Watch()
+
+  // Admitting the streams while there is capacity for them...
+  limits, _ := admission.ParseLimits(cfg.StreamLimits)
+  admissionController := admission.New(admission.Config{
+    MaxStreamsPerMethod: limits,
+    MaxStreamsPerClient: cfg.MaxClientStreams,
+    ClientKey:           keyFunc,
+    TargetLatency:       cfg.Shed.TargetLatency,
+    MaxStreams:          cfg.Shed.MaxStreams,
+    Clock:               clock.Real,
+  })
+  expvar.Publish("calculator_admission", expvar.Func(func() interface{} {
+    return admissionController.Stats()
+  }))
+
+  // Replaying the calls retried by a client with the same idempotency key...
+  if cfg.IdempotencyWindow > 0 {
+    idempotent := idempotency.NewStore(cfg.IdempotencyWindow, maxIdempotencyKeys, ratelimit.DefaultKey,
+      "/calculator.CalculatorService/Sum",
+      "/calculator.CalculatorService/SquareRootUnary",
+    )
+    unaryInterceptors = append(unaryInterceptors, idempotent.UnaryInterceptor)
   }
 
   // Creating GRPC server...
-  s := grpc.NewServer()
+  s := grpc.NewServer(
+    grpc.ChainUnaryInterceptor(unaryInterceptors...),
+    grpc.ChainStreamInterceptor(streamInterceptors...),
+    admissionController.ServerOption(),
+  )
 
   // Registring de CalculatorService in GRPC server...
-  calculatorpb.RegisterCalculatorServiceServer(s, &server{})
+  calculatorpb.RegisterCalculatorServiceServer(s, srv)
+
+  // Registring the health service, watched by the clients balancing over several servers...
+  healthServer := health.NewServer()
+  healthServer.SetServingStatus("calculator.CalculatorService", healthpb.HealthCheckResponse_SERVING)
+  healthpb.RegisterHealthServer(s, healthServer)
+
+  // Serving the AdminService, reloading the configuration, and the history of every client
+  // to the operators, apart from the clients...
+  if cfg.AdminAddr != "" {
+    adminList, err := net.Listen("tcp", cfg.AdminAddr)
+    if err != nil {
+      log.Fatalf("Failed to listen: %v", err)
+    }
+    adminServer := grpc.NewServer(
+      grpc.UnaryInterceptor(srv.AuditInterceptor),
+      grpc.StreamInterceptor(srv.AuditStreamInterceptor),
+    )
+    adminpb.RegisterAdminServiceServer(adminServer, admin.NewServer(reloader))
+    calculatorpb.RegisterCalculatorServiceServer(adminServer, srv)
+    go func() {
+      if err := adminServer.Serve(adminList); err != nil {
+        log.Printf("Failed to serve the AdminService: %v", err)
+      }
+    }()
+  }
+
+  // Stopping on SIGINT or SIGTERM once the calls end, the history writing the last entries...
+  stop := make(chan os.Signal, 1)
+  signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+  go func() {
+    <-stop
+    log.Println("SERVER - Stopping...")
+    s.GracefulStop()
+  }()
 
   log.Println("SERVER - Running...")
 
@@ -1,762 +1,3033 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
 // source: calculator/calculatorpb/calculator.proto
 
 package calculatorpb
 
 import (
-  fmt "fmt"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SumRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FirstNumber   int32                  `protobuf:"varint,1,opt,name=first_number,json=firstNumber,proto3" json:"first_number,omitempty"`
+	SecondNumber  int32                  `protobuf:"varint,2,opt,name=second_number,json=secondNumber,proto3" json:"second_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SumRequest) Reset() {
+	*x = SumRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SumRequest) ProtoMessage() {}
+
+func (x *SumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SumRequest.ProtoReflect.Descriptor instead.
+func (*SumRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SumRequest) GetFirstNumber() int32 {
+	if x != nil {
+		return x.FirstNumber
+	}
+	return 0
+}
+
+func (x *SumRequest) GetSecondNumber() int32 {
+	if x != nil {
+		return x.SecondNumber
+	}
+	return 0
+}
+
+type SumResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SumResult     int32                  `protobuf:"varint,1,opt,name=sum_result,json=sumResult,proto3" json:"sum_result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SumResponse) Reset() {
+	*x = SumResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SumResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SumResponse) ProtoMessage() {}
+
+func (x *SumResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SumResponse.ProtoReflect.Descriptor instead.
+func (*SumResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SumResponse) GetSumResult() int32 {
+	if x != nil {
+		return x.SumResult
+	}
+	return 0
+}
+
+type PrimeNumberDecompositionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        int64                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PrimeNumberDecompositionRequest) Reset() {
+	*x = PrimeNumberDecompositionRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrimeNumberDecompositionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrimeNumberDecompositionRequest) ProtoMessage() {}
+
+func (x *PrimeNumberDecompositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrimeNumberDecompositionRequest.ProtoReflect.Descriptor instead.
+func (*PrimeNumberDecompositionRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PrimeNumberDecompositionRequest) GetNumber() int64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+type PrimeNumberDecompositionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PrimeFactor   int64                  `protobuf:"varint,1,opt,name=prime_factor,json=primeFactor,proto3" json:"prime_factor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PrimeNumberDecompositionResponse) Reset() {
+	*x = PrimeNumberDecompositionResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PrimeNumberDecompositionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PrimeNumberDecompositionResponse) ProtoMessage() {}
+
+func (x *PrimeNumberDecompositionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PrimeNumberDecompositionResponse.ProtoReflect.Descriptor instead.
+func (*PrimeNumberDecompositionResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PrimeNumberDecompositionResponse) GetPrimeFactor() int64 {
+	if x != nil {
+		return x.PrimeFactor
+	}
+	return 0
+}
+
+type ComputeAverageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        int32                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComputeAverageRequest) Reset() {
+	*x = ComputeAverageRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputeAverageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeAverageRequest) ProtoMessage() {}
+
+func (x *ComputeAverageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeAverageRequest.ProtoReflect.Descriptor instead.
+func (*ComputeAverageRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ComputeAverageRequest) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+type ComputeAverageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Average       float64                `protobuf:"fixed64,1,opt,name=average,proto3" json:"average,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComputeAverageResponse) Reset() {
+	*x = ComputeAverageResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComputeAverageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComputeAverageResponse) ProtoMessage() {}
+
+func (x *ComputeAverageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComputeAverageResponse.ProtoReflect.Descriptor instead.
+func (*ComputeAverageResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ComputeAverageResponse) GetAverage() float64 {
+	if x != nil {
+		return x.Average
+	}
+	return 0
+}
+
+type FindMaximumRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        int32                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindMaximumRequest) Reset() {
+	*x = FindMaximumRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindMaximumRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindMaximumRequest) ProtoMessage() {}
+
+func (x *FindMaximumRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindMaximumRequest.ProtoReflect.Descriptor instead.
+func (*FindMaximumRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *FindMaximumRequest) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+type FindMaximumResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Maximum       int32                  `protobuf:"varint,1,opt,name=maximum,proto3" json:"maximum,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindMaximumResponse) Reset() {
+	*x = FindMaximumResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindMaximumResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindMaximumResponse) ProtoMessage() {}
+
+func (x *FindMaximumResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindMaximumResponse.ProtoReflect.Descriptor instead.
+func (*FindMaximumResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FindMaximumResponse) GetMaximum() int32 {
+	if x != nil {
+		return x.Maximum
+	}
+	return 0
+}
+
+type SquareRootRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        int32                  `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	AllowComplex  bool                   `protobuf:"varint,2,opt,name=allow_complex,json=allowComplex,proto3" json:"allow_complex,omitempty"` // answer negative numbers with a complex root instead of an error.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SquareRootRequest) Reset() {
+	*x = SquareRootRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SquareRootRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SquareRootRequest) ProtoMessage() {}
+
+func (x *SquareRootRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SquareRootRequest.ProtoReflect.Descriptor instead.
+func (*SquareRootRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *SquareRootRequest) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *SquareRootRequest) GetAllowComplex() bool {
+	if x != nil {
+		return x.AllowComplex
+	}
+	return false
+}
+
+type SquareRootError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"` // google.golang.org/grpc/codes value.
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SquareRootError) Reset() {
+	*x = SquareRootError{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SquareRootError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SquareRootError) ProtoMessage() {}
+
+func (x *SquareRootError) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SquareRootError.ProtoReflect.Descriptor instead.
+func (*SquareRootError) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SquareRootError) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+func (x *SquareRootError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type SquareRootResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Number int32                  `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"` // the number this response answers.
+	// Types that are valid to be assigned to Result:
+	//
+	//	*SquareRootResponse_NumberRoot
+	//	*SquareRootResponse_Error
+	//	*SquareRootResponse_ComplexRoot
+	Result        isSquareRootResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SquareRootResponse) Reset() {
+	*x = SquareRootResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SquareRootResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SquareRootResponse) ProtoMessage() {}
+
+func (x *SquareRootResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SquareRootResponse.ProtoReflect.Descriptor instead.
+func (*SquareRootResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SquareRootResponse) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *SquareRootResponse) GetResult() isSquareRootResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *SquareRootResponse) GetNumberRoot() float64 {
+	if x != nil {
+		if x, ok := x.Result.(*SquareRootResponse_NumberRoot); ok {
+			return x.NumberRoot
+		}
+	}
+	return 0
+}
+
+func (x *SquareRootResponse) GetError() *SquareRootError {
+	if x != nil {
+		if x, ok := x.Result.(*SquareRootResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
+}
+
+func (x *SquareRootResponse) GetComplexRoot() *Complex {
+	if x != nil {
+		if x, ok := x.Result.(*SquareRootResponse_ComplexRoot); ok {
+			return x.ComplexRoot
+		}
+	}
+	return nil
+}
+
+type isSquareRootResponse_Result interface {
+	isSquareRootResponse_Result()
+}
+
+type SquareRootResponse_NumberRoot struct {
+	NumberRoot float64 `protobuf:"fixed64,1,opt,name=number_root,json=numberRoot,proto3,oneof"`
+}
+
+type SquareRootResponse_Error struct {
+	Error *SquareRootError `protobuf:"bytes,3,opt,name=error,proto3,oneof"`
+}
+
+type SquareRootResponse_ComplexRoot struct {
+	ComplexRoot *Complex `protobuf:"bytes,4,opt,name=complex_root,json=complexRoot,proto3,oneof"`
+}
+
+func (*SquareRootResponse_NumberRoot) isSquareRootResponse_Result() {}
+
+func (*SquareRootResponse_Error) isSquareRootResponse_Result() {}
+
+func (*SquareRootResponse_ComplexRoot) isSquareRootResponse_Result() {}
+
+type Complex struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Real          float64                `protobuf:"fixed64,1,opt,name=real,proto3" json:"real,omitempty"`
+	Imaginary     float64                `protobuf:"fixed64,2,opt,name=imaginary,proto3" json:"imaginary,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Complex) Reset() {
+	*x = Complex{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Complex) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Complex) ProtoMessage() {}
+
+func (x *Complex) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Complex.ProtoReflect.Descriptor instead.
+func (*Complex) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Complex) GetReal() float64 {
+	if x != nil {
+		return x.Real
+	}
+	return 0
+}
+
+func (x *Complex) GetImaginary() float64 {
+	if x != nil {
+		return x.Imaginary
+	}
+	return 0
+}
+
+type ComplexAddRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	First         *Complex               `protobuf:"bytes,1,opt,name=first,proto3" json:"first,omitempty"`
+	Second        *Complex               `protobuf:"bytes,2,opt,name=second,proto3" json:"second,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComplexAddRequest) Reset() {
+	*x = ComplexAddRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComplexAddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComplexAddRequest) ProtoMessage() {}
+
+func (x *ComplexAddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComplexAddRequest.ProtoReflect.Descriptor instead.
+func (*ComplexAddRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ComplexAddRequest) GetFirst() *Complex {
+	if x != nil {
+		return x.First
+	}
+	return nil
+}
+
+func (x *ComplexAddRequest) GetSecond() *Complex {
+	if x != nil {
+		return x.Second
+	}
+	return nil
+}
+
+type ComplexAddResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Complex               `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComplexAddResponse) Reset() {
+	*x = ComplexAddResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComplexAddResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComplexAddResponse) ProtoMessage() {}
+
+func (x *ComplexAddResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComplexAddResponse.ProtoReflect.Descriptor instead.
+func (*ComplexAddResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ComplexAddResponse) GetResult() *Complex {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type ComplexMultiplyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	First         *Complex               `protobuf:"bytes,1,opt,name=first,proto3" json:"first,omitempty"`
+	Second        *Complex               `protobuf:"bytes,2,opt,name=second,proto3" json:"second,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComplexMultiplyRequest) Reset() {
+	*x = ComplexMultiplyRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComplexMultiplyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComplexMultiplyRequest) ProtoMessage() {}
+
+func (x *ComplexMultiplyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComplexMultiplyRequest.ProtoReflect.Descriptor instead.
+func (*ComplexMultiplyRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ComplexMultiplyRequest) GetFirst() *Complex {
+	if x != nil {
+		return x.First
+	}
+	return nil
+}
+
+func (x *ComplexMultiplyRequest) GetSecond() *Complex {
+	if x != nil {
+		return x.Second
+	}
+	return nil
+}
+
+type ComplexMultiplyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Complex               `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComplexMultiplyResponse) Reset() {
+	*x = ComplexMultiplyResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComplexMultiplyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComplexMultiplyResponse) ProtoMessage() {}
+
+func (x *ComplexMultiplyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComplexMultiplyResponse.ProtoReflect.Descriptor instead.
+func (*ComplexMultiplyResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ComplexMultiplyResponse) GetResult() *Complex {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type ComplexDivideRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dividend      *Complex               `protobuf:"bytes,1,opt,name=dividend,proto3" json:"dividend,omitempty"`
+	Divisor       *Complex               `protobuf:"bytes,2,opt,name=divisor,proto3" json:"divisor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComplexDivideRequest) Reset() {
+	*x = ComplexDivideRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComplexDivideRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComplexDivideRequest) ProtoMessage() {}
+
+func (x *ComplexDivideRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComplexDivideRequest.ProtoReflect.Descriptor instead.
+func (*ComplexDivideRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ComplexDivideRequest) GetDividend() *Complex {
+	if x != nil {
+		return x.Dividend
+	}
+	return nil
+}
+
+func (x *ComplexDivideRequest) GetDivisor() *Complex {
+	if x != nil {
+		return x.Divisor
+	}
+	return nil
+}
+
+type ComplexDivideResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Complex               `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComplexDivideResponse) Reset() {
+	*x = ComplexDivideResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComplexDivideResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComplexDivideResponse) ProtoMessage() {}
+
+func (x *ComplexDivideResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComplexDivideResponse.ProtoReflect.Descriptor instead.
+func (*ComplexDivideResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ComplexDivideResponse) GetResult() *Complex {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type ComplexModulusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        *Complex               `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComplexModulusRequest) Reset() {
+	*x = ComplexModulusRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComplexModulusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComplexModulusRequest) ProtoMessage() {}
+
+func (x *ComplexModulusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComplexModulusRequest.ProtoReflect.Descriptor instead.
+func (*ComplexModulusRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ComplexModulusRequest) GetNumber() *Complex {
+	if x != nil {
+		return x.Number
+	}
+	return nil
+}
+
+type ComplexModulusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Modulus       float64                `protobuf:"fixed64,1,opt,name=modulus,proto3" json:"modulus,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComplexModulusResponse) Reset() {
+	*x = ComplexModulusResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComplexModulusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComplexModulusResponse) ProtoMessage() {}
+
+func (x *ComplexModulusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComplexModulusResponse.ProtoReflect.Descriptor instead.
+func (*ComplexModulusResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ComplexModulusResponse) GetModulus() float64 {
+	if x != nil {
+		return x.Modulus
+	}
+	return 0
+}
+
+type ComplexArgumentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        *Complex               `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComplexArgumentRequest) Reset() {
+	*x = ComplexArgumentRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComplexArgumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComplexArgumentRequest) ProtoMessage() {}
+
+func (x *ComplexArgumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComplexArgumentRequest.ProtoReflect.Descriptor instead.
+func (*ComplexArgumentRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ComplexArgumentRequest) GetNumber() *Complex {
+	if x != nil {
+		return x.Number
+	}
+	return nil
+}
+
+type ComplexArgumentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Argument      float64                `protobuf:"fixed64,1,opt,name=argument,proto3" json:"argument,omitempty"` // in radians, in the range [-Pi, Pi].
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComplexArgumentResponse) Reset() {
+	*x = ComplexArgumentResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComplexArgumentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComplexArgumentResponse) ProtoMessage() {}
+
+func (x *ComplexArgumentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComplexArgumentResponse.ProtoReflect.Descriptor instead.
+func (*ComplexArgumentResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ComplexArgumentResponse) GetArgument() float64 {
+	if x != nil {
+		return x.Argument
+	}
+	return 0
+}
 
-  proto "github.com/golang/protobuf/proto"
+type ComplexRootsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Number        *Complex               `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	Degree        int32                  `protobuf:"varint,2,opt,name=degree,proto3" json:"degree,omitempty"` // the n of the nth roots.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-  math "math"
+func (x *ComplexRootsRequest) Reset() {
+	*x = ComplexRootsRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-  context "golang.org/x/net/context"
+func (x *ComplexRootsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
 
-  grpc "google.golang.org/grpc"
-)
+func (*ComplexRootsRequest) ProtoMessage() {}
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ = proto.Marshal
-var _ = fmt.Errorf
-var _ = math.Inf
+func (x *ComplexRootsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the proto package it is being compiled against.
-// A compilation error at this line likely means your copy of the
-// proto package needs to be updated.
-const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+// Deprecated: Use ComplexRootsRequest.ProtoReflect.Descriptor instead.
+func (*ComplexRootsRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{22}
+}
 
-type SumRequest struct {
-  FirstNumber          int32    `protobuf:"varint,1,opt,name=first_number,json=firstNumber,proto3" json:"first_number,omitempty"`
-  SecondNumber         int32    `protobuf:"varint,2,opt,name=second_number,json=secondNumber,proto3" json:"second_number,omitempty"`
-  XXX_NoUnkeyedLiteral struct{} `json:"-"`
-  XXX_unrecognized     []byte   `json:"-"`
-  XXX_sizecache        int32    `json:"-"`
+func (x *ComplexRootsRequest) GetNumber() *Complex {
+	if x != nil {
+		return x.Number
+	}
+	return nil
 }
 
-func (m *SumRequest) Reset()         { *m = SumRequest{} }
-func (m *SumRequest) String() string { return proto.CompactTextString(m) }
-func (*SumRequest) ProtoMessage()    {}
-func (*SumRequest) Descriptor() ([]byte, []int) {
-  return fileDescriptor_calculator_0799ea845ca306d9, []int{0}
+func (x *ComplexRootsRequest) GetDegree() int32 {
+	if x != nil {
+		return x.Degree
+	}
+	return 0
 }
-func (m *SumRequest) XXX_Unmarshal(b []byte) error {
-  return xxx_messageInfo_SumRequest.Unmarshal(m, b)
+
+type ComplexRootsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Root          *Complex               `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
-func (m *SumRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-  return xxx_messageInfo_SumRequest.Marshal(b, m, deterministic)
+
+func (x *ComplexRootsResponse) Reset() {
+	*x = ComplexRootsResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-func (dst *SumRequest) XXX_Merge(src proto.Message) {
-  xxx_messageInfo_SumRequest.Merge(dst, src)
+
+func (x *ComplexRootsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *SumRequest) XXX_Size() int {
-  return xxx_messageInfo_SumRequest.Size(m)
+
+func (*ComplexRootsResponse) ProtoMessage() {}
+
+func (x *ComplexRootsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *SumRequest) XXX_DiscardUnknown() {
-  xxx_messageInfo_SumRequest.DiscardUnknown(m)
+
+// Deprecated: Use ComplexRootsResponse.ProtoReflect.Descriptor instead.
+func (*ComplexRootsResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{23}
 }
 
-var xxx_messageInfo_SumRequest proto.InternalMessageInfo
+func (x *ComplexRootsResponse) GetRoot() *Complex {
+	if x != nil {
+		return x.Root
+	}
+	return nil
+}
 
-func (m *SumRequest) GetFirstNumber() int32 {
-  if m != nil {
-    return m.FirstNumber
-  }
-  return 0
+type Vector struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []float64              `protobuf:"fixed64,1,rep,packed,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (m *SumRequest) GetSecondNumber() int32 {
-  if m != nil {
-    return m.SecondNumber
-  }
-  return 0
+func (x *Vector) Reset() {
+	*x = Vector{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-type SumResponse struct {
-  SumResult            int32    `protobuf:"varint,1,opt,name=sum_result,json=sumResult,proto3" json:"sum_result,omitempty"`
-  XXX_NoUnkeyedLiteral struct{} `json:"-"`
-  XXX_unrecognized     []byte   `json:"-"`
-  XXX_sizecache        int32    `json:"-"`
+func (x *Vector) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (m *SumResponse) Reset()         { *m = SumResponse{} }
-func (m *SumResponse) String() string { return proto.CompactTextString(m) }
-func (*SumResponse) ProtoMessage()    {}
-func (*SumResponse) Descriptor() ([]byte, []int) {
-  return fileDescriptor_calculator_0799ea845ca306d9, []int{1}
+func (*Vector) ProtoMessage() {}
+
+func (x *Vector) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *SumResponse) XXX_Unmarshal(b []byte) error {
-  return xxx_messageInfo_SumResponse.Unmarshal(m, b)
+
+// Deprecated: Use Vector.ProtoReflect.Descriptor instead.
+func (*Vector) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{24}
 }
-func (m *SumResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-  return xxx_messageInfo_SumResponse.Marshal(b, m, deterministic)
+
+func (x *Vector) GetValues() []float64 {
+	if x != nil {
+		return x.Values
+	}
+	return nil
 }
-func (dst *SumResponse) XXX_Merge(src proto.Message) {
-  xxx_messageInfo_SumResponse.Merge(dst, src)
+
+// Matrix is stored row by row, every row must have the same length.
+type Matrix struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rows          []*Vector              `protobuf:"bytes,1,rep,name=rows,proto3" json:"rows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
-func (m *SumResponse) XXX_Size() int {
-  return xxx_messageInfo_SumResponse.Size(m)
+
+func (x *Matrix) Reset() {
+	*x = Matrix{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-func (m *SumResponse) XXX_DiscardUnknown() {
-  xxx_messageInfo_SumResponse.DiscardUnknown(m)
+
+func (x *Matrix) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var xxx_messageInfo_SumResponse proto.InternalMessageInfo
+func (*Matrix) ProtoMessage() {}
 
-func (m *SumResponse) GetSumResult() int32 {
-  if m != nil {
-    return m.SumResult
-  }
-  return 0
+func (x *Matrix) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-type PrimeNumberDecompositionRequest struct {
-  Number               int64    `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
-  XXX_NoUnkeyedLiteral struct{} `json:"-"`
-  XXX_unrecognized     []byte   `json:"-"`
-  XXX_sizecache        int32    `json:"-"`
+// Deprecated: Use Matrix.ProtoReflect.Descriptor instead.
+func (*Matrix) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{25}
 }
 
-func (m *PrimeNumberDecompositionRequest) Reset()         { *m = PrimeNumberDecompositionRequest{} }
-func (m *PrimeNumberDecompositionRequest) String() string { return proto.CompactTextString(m) }
-func (*PrimeNumberDecompositionRequest) ProtoMessage()    {}
-func (*PrimeNumberDecompositionRequest) Descriptor() ([]byte, []int) {
-  return fileDescriptor_calculator_0799ea845ca306d9, []int{2}
+func (x *Matrix) GetRows() []*Vector {
+	if x != nil {
+		return x.Rows
+	}
+	return nil
 }
-func (m *PrimeNumberDecompositionRequest) XXX_Unmarshal(b []byte) error {
-  return xxx_messageInfo_PrimeNumberDecompositionRequest.Unmarshal(m, b)
+
+type MatrixAddRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	First         *Matrix                `protobuf:"bytes,1,opt,name=first,proto3" json:"first,omitempty"`
+	Second        *Matrix                `protobuf:"bytes,2,opt,name=second,proto3" json:"second,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
-func (m *PrimeNumberDecompositionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-  return xxx_messageInfo_PrimeNumberDecompositionRequest.Marshal(b, m, deterministic)
+
+func (x *MatrixAddRequest) Reset() {
+	*x = MatrixAddRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-func (dst *PrimeNumberDecompositionRequest) XXX_Merge(src proto.Message) {
-  xxx_messageInfo_PrimeNumberDecompositionRequest.Merge(dst, src)
+
+func (x *MatrixAddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *PrimeNumberDecompositionRequest) XXX_Size() int {
-  return xxx_messageInfo_PrimeNumberDecompositionRequest.Size(m)
+
+func (*MatrixAddRequest) ProtoMessage() {}
+
+func (x *MatrixAddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *PrimeNumberDecompositionRequest) XXX_DiscardUnknown() {
-  xxx_messageInfo_PrimeNumberDecompositionRequest.DiscardUnknown(m)
+
+// Deprecated: Use MatrixAddRequest.ProtoReflect.Descriptor instead.
+func (*MatrixAddRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{26}
 }
 
-var xxx_messageInfo_PrimeNumberDecompositionRequest proto.InternalMessageInfo
+func (x *MatrixAddRequest) GetFirst() *Matrix {
+	if x != nil {
+		return x.First
+	}
+	return nil
+}
 
-func (m *PrimeNumberDecompositionRequest) GetNumber() int64 {
-  if m != nil {
-    return m.Number
-  }
-  return 0
+func (x *MatrixAddRequest) GetSecond() *Matrix {
+	if x != nil {
+		return x.Second
+	}
+	return nil
 }
 
-type PrimeNumberDecompositionResponse struct {
-  PrimeFactor          int64    `protobuf:"varint,1,opt,name=prime_factor,json=primeFactor,proto3" json:"prime_factor,omitempty"`
-  XXX_NoUnkeyedLiteral struct{} `json:"-"`
-  XXX_unrecognized     []byte   `json:"-"`
-  XXX_sizecache        int32    `json:"-"`
+type MatrixAddResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Matrix                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (m *PrimeNumberDecompositionResponse) Reset()         { *m = PrimeNumberDecompositionResponse{} }
-func (m *PrimeNumberDecompositionResponse) String() string { return proto.CompactTextString(m) }
-func (*PrimeNumberDecompositionResponse) ProtoMessage()    {}
-func (*PrimeNumberDecompositionResponse) Descriptor() ([]byte, []int) {
-  return fileDescriptor_calculator_0799ea845ca306d9, []int{3}
+func (x *MatrixAddResponse) Reset() {
+	*x = MatrixAddResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-func (m *PrimeNumberDecompositionResponse) XXX_Unmarshal(b []byte) error {
-  return xxx_messageInfo_PrimeNumberDecompositionResponse.Unmarshal(m, b)
+
+func (x *MatrixAddResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *PrimeNumberDecompositionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-  return xxx_messageInfo_PrimeNumberDecompositionResponse.Marshal(b, m, deterministic)
+
+func (*MatrixAddResponse) ProtoMessage() {}
+
+func (x *MatrixAddResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (dst *PrimeNumberDecompositionResponse) XXX_Merge(src proto.Message) {
-  xxx_messageInfo_PrimeNumberDecompositionResponse.Merge(dst, src)
+
+// Deprecated: Use MatrixAddResponse.ProtoReflect.Descriptor instead.
+func (*MatrixAddResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{27}
 }
-func (m *PrimeNumberDecompositionResponse) XXX_Size() int {
-  return xxx_messageInfo_PrimeNumberDecompositionResponse.Size(m)
+
+func (x *MatrixAddResponse) GetResult() *Matrix {
+	if x != nil {
+		return x.Result
+	}
+	return nil
 }
-func (m *PrimeNumberDecompositionResponse) XXX_DiscardUnknown() {
-  xxx_messageInfo_PrimeNumberDecompositionResponse.DiscardUnknown(m)
+
+type MatrixMultiplyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	First         *Matrix                `protobuf:"bytes,1,opt,name=first,proto3" json:"first,omitempty"`
+	Second        *Matrix                `protobuf:"bytes,2,opt,name=second,proto3" json:"second,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-var xxx_messageInfo_PrimeNumberDecompositionResponse proto.InternalMessageInfo
+func (x *MatrixMultiplyRequest) Reset() {
+	*x = MatrixMultiplyRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
 
-func (m *PrimeNumberDecompositionResponse) GetPrimeFactor() int64 {
-  if m != nil {
-    return m.PrimeFactor
-  }
-  return 0
+func (x *MatrixMultiplyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-type ComputeAverageRequest struct {
-  Number               int32    `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
-  XXX_NoUnkeyedLiteral struct{} `json:"-"`
-  XXX_unrecognized     []byte   `json:"-"`
-  XXX_sizecache        int32    `json:"-"`
+func (*MatrixMultiplyRequest) ProtoMessage() {}
+
+func (x *MatrixMultiplyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (m *ComputeAverageRequest) Reset()         { *m = ComputeAverageRequest{} }
-func (m *ComputeAverageRequest) String() string { return proto.CompactTextString(m) }
-func (*ComputeAverageRequest) ProtoMessage()    {}
-func (*ComputeAverageRequest) Descriptor() ([]byte, []int) {
-  return fileDescriptor_calculator_0799ea845ca306d9, []int{4}
+// Deprecated: Use MatrixMultiplyRequest.ProtoReflect.Descriptor instead.
+func (*MatrixMultiplyRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{28}
 }
-func (m *ComputeAverageRequest) XXX_Unmarshal(b []byte) error {
-  return xxx_messageInfo_ComputeAverageRequest.Unmarshal(m, b)
+
+func (x *MatrixMultiplyRequest) GetFirst() *Matrix {
+	if x != nil {
+		return x.First
+	}
+	return nil
 }
-func (m *ComputeAverageRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-  return xxx_messageInfo_ComputeAverageRequest.Marshal(b, m, deterministic)
+
+func (x *MatrixMultiplyRequest) GetSecond() *Matrix {
+	if x != nil {
+		return x.Second
+	}
+	return nil
 }
-func (dst *ComputeAverageRequest) XXX_Merge(src proto.Message) {
-  xxx_messageInfo_ComputeAverageRequest.Merge(dst, src)
+
+type MatrixMultiplyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Matrix                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
-func (m *ComputeAverageRequest) XXX_Size() int {
-  return xxx_messageInfo_ComputeAverageRequest.Size(m)
+
+func (x *MatrixMultiplyResponse) Reset() {
+	*x = MatrixMultiplyResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-func (m *ComputeAverageRequest) XXX_DiscardUnknown() {
-  xxx_messageInfo_ComputeAverageRequest.DiscardUnknown(m)
+
+func (x *MatrixMultiplyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var xxx_messageInfo_ComputeAverageRequest proto.InternalMessageInfo
+func (*MatrixMultiplyResponse) ProtoMessage() {}
 
-func (m *ComputeAverageRequest) GetNumber() int32 {
-  if m != nil {
-    return m.Number
-  }
-  return 0
+func (x *MatrixMultiplyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-type ComputeAverageResponse struct {
-  Average              float64  `protobuf:"fixed64,1,opt,name=average,proto3" json:"average,omitempty"`
-  XXX_NoUnkeyedLiteral struct{} `json:"-"`
-  XXX_unrecognized     []byte   `json:"-"`
-  XXX_sizecache        int32    `json:"-"`
+// Deprecated: Use MatrixMultiplyResponse.ProtoReflect.Descriptor instead.
+func (*MatrixMultiplyResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{29}
 }
 
-func (m *ComputeAverageResponse) Reset()         { *m = ComputeAverageResponse{} }
-func (m *ComputeAverageResponse) String() string { return proto.CompactTextString(m) }
-func (*ComputeAverageResponse) ProtoMessage()    {}
-func (*ComputeAverageResponse) Descriptor() ([]byte, []int) {
-  return fileDescriptor_calculator_0799ea845ca306d9, []int{5}
+func (x *MatrixMultiplyResponse) GetResult() *Matrix {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type MatrixTransposeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matrix        *Matrix                `protobuf:"bytes,1,opt,name=matrix,proto3" json:"matrix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
-func (m *ComputeAverageResponse) XXX_Unmarshal(b []byte) error {
-  return xxx_messageInfo_ComputeAverageResponse.Unmarshal(m, b)
+
+func (x *MatrixTransposeRequest) Reset() {
+	*x = MatrixTransposeRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-func (m *ComputeAverageResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-  return xxx_messageInfo_ComputeAverageResponse.Marshal(b, m, deterministic)
+
+func (x *MatrixTransposeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (dst *ComputeAverageResponse) XXX_Merge(src proto.Message) {
-  xxx_messageInfo_ComputeAverageResponse.Merge(dst, src)
+
+func (*MatrixTransposeRequest) ProtoMessage() {}
+
+func (x *MatrixTransposeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *ComputeAverageResponse) XXX_Size() int {
-  return xxx_messageInfo_ComputeAverageResponse.Size(m)
+
+// Deprecated: Use MatrixTransposeRequest.ProtoReflect.Descriptor instead.
+func (*MatrixTransposeRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{30}
 }
-func (m *ComputeAverageResponse) XXX_DiscardUnknown() {
-  xxx_messageInfo_ComputeAverageResponse.DiscardUnknown(m)
+
+func (x *MatrixTransposeRequest) GetMatrix() *Matrix {
+	if x != nil {
+		return x.Matrix
+	}
+	return nil
 }
 
-var xxx_messageInfo_ComputeAverageResponse proto.InternalMessageInfo
+type MatrixTransposeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Matrix                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-func (m *ComputeAverageResponse) GetAverage() float64 {
-  if m != nil {
-    return m.Average
-  }
-  return 0
+func (x *MatrixTransposeResponse) Reset() {
+	*x = MatrixTransposeResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-type FindMaximumRequest struct {
-  Number               int32    `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
-  XXX_NoUnkeyedLiteral struct{} `json:"-"`
-  XXX_unrecognized     []byte   `json:"-"`
-  XXX_sizecache        int32    `json:"-"`
+func (x *MatrixTransposeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (m *FindMaximumRequest) Reset()         { *m = FindMaximumRequest{} }
-func (m *FindMaximumRequest) String() string { return proto.CompactTextString(m) }
-func (*FindMaximumRequest) ProtoMessage()    {}
-func (*FindMaximumRequest) Descriptor() ([]byte, []int) {
-  return fileDescriptor_calculator_0799ea845ca306d9, []int{6}
+func (*MatrixTransposeResponse) ProtoMessage() {}
+
+func (x *MatrixTransposeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatrixTransposeResponse.ProtoReflect.Descriptor instead.
+func (*MatrixTransposeResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *MatrixTransposeResponse) GetResult() *Matrix {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+type MatrixDeterminantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matrix        *Matrix                `protobuf:"bytes,1,opt,name=matrix,proto3" json:"matrix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
-func (m *FindMaximumRequest) XXX_Unmarshal(b []byte) error {
-  return xxx_messageInfo_FindMaximumRequest.Unmarshal(m, b)
+
+func (x *MatrixDeterminantRequest) Reset() {
+	*x = MatrixDeterminantRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-func (m *FindMaximumRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-  return xxx_messageInfo_FindMaximumRequest.Marshal(b, m, deterministic)
+
+func (x *MatrixDeterminantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (dst *FindMaximumRequest) XXX_Merge(src proto.Message) {
-  xxx_messageInfo_FindMaximumRequest.Merge(dst, src)
+
+func (*MatrixDeterminantRequest) ProtoMessage() {}
+
+func (x *MatrixDeterminantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *FindMaximumRequest) XXX_Size() int {
-  return xxx_messageInfo_FindMaximumRequest.Size(m)
+
+// Deprecated: Use MatrixDeterminantRequest.ProtoReflect.Descriptor instead.
+func (*MatrixDeterminantRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{32}
 }
-func (m *FindMaximumRequest) XXX_DiscardUnknown() {
-  xxx_messageInfo_FindMaximumRequest.DiscardUnknown(m)
+
+func (x *MatrixDeterminantRequest) GetMatrix() *Matrix {
+	if x != nil {
+		return x.Matrix
+	}
+	return nil
 }
 
-var xxx_messageInfo_FindMaximumRequest proto.InternalMessageInfo
+type MatrixDeterminantResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Determinant   float64                `protobuf:"fixed64,1,opt,name=determinant,proto3" json:"determinant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-func (m *FindMaximumRequest) GetNumber() int32 {
-  if m != nil {
-    return m.Number
-  }
-  return 0
+func (x *MatrixDeterminantResponse) Reset() {
+	*x = MatrixDeterminantResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-type FindMaximumResponse struct {
-  Maximum              int32    `protobuf:"varint,1,opt,name=maximum,proto3" json:"maximum,omitempty"`
-  XXX_NoUnkeyedLiteral struct{} `json:"-"`
-  XXX_unrecognized     []byte   `json:"-"`
-  XXX_sizecache        int32    `json:"-"`
+func (x *MatrixDeterminantResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (m *FindMaximumResponse) Reset()         { *m = FindMaximumResponse{} }
-func (m *FindMaximumResponse) String() string { return proto.CompactTextString(m) }
-func (*FindMaximumResponse) ProtoMessage()    {}
-func (*FindMaximumResponse) Descriptor() ([]byte, []int) {
-  return fileDescriptor_calculator_0799ea845ca306d9, []int{7}
+func (*MatrixDeterminantResponse) ProtoMessage() {}
+
+func (x *MatrixDeterminantResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *FindMaximumResponse) XXX_Unmarshal(b []byte) error {
-  return xxx_messageInfo_FindMaximumResponse.Unmarshal(m, b)
+
+// Deprecated: Use MatrixDeterminantResponse.ProtoReflect.Descriptor instead.
+func (*MatrixDeterminantResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{33}
 }
-func (m *FindMaximumResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-  return xxx_messageInfo_FindMaximumResponse.Marshal(b, m, deterministic)
+
+func (x *MatrixDeterminantResponse) GetDeterminant() float64 {
+	if x != nil {
+		return x.Determinant
+	}
+	return 0
 }
-func (dst *FindMaximumResponse) XXX_Merge(src proto.Message) {
-  xxx_messageInfo_FindMaximumResponse.Merge(dst, src)
+
+type MatrixInverseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matrix        *Matrix                `protobuf:"bytes,1,opt,name=matrix,proto3" json:"matrix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
-func (m *FindMaximumResponse) XXX_Size() int {
-  return xxx_messageInfo_FindMaximumResponse.Size(m)
+
+func (x *MatrixInverseRequest) Reset() {
+	*x = MatrixInverseRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-func (m *FindMaximumResponse) XXX_DiscardUnknown() {
-  xxx_messageInfo_FindMaximumResponse.DiscardUnknown(m)
+
+func (x *MatrixInverseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var xxx_messageInfo_FindMaximumResponse proto.InternalMessageInfo
+func (*MatrixInverseRequest) ProtoMessage() {}
 
-func (m *FindMaximumResponse) GetMaximum() int32 {
-  if m != nil {
-    return m.Maximum
-  }
-  return 0
+func (x *MatrixInverseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-type SquareRootRequest struct {
-  Number               int32    `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
-  XXX_NoUnkeyedLiteral struct{} `json:"-"`
-  XXX_unrecognized     []byte   `json:"-"`
-  XXX_sizecache        int32    `json:"-"`
+// Deprecated: Use MatrixInverseRequest.ProtoReflect.Descriptor instead.
+func (*MatrixInverseRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{34}
 }
 
-func (m *SquareRootRequest) Reset()         { *m = SquareRootRequest{} }
-func (m *SquareRootRequest) String() string { return proto.CompactTextString(m) }
-func (*SquareRootRequest) ProtoMessage()    {}
-func (*SquareRootRequest) Descriptor() ([]byte, []int) {
-  return fileDescriptor_calculator_0799ea845ca306d9, []int{8}
+func (x *MatrixInverseRequest) GetMatrix() *Matrix {
+	if x != nil {
+		return x.Matrix
+	}
+	return nil
 }
-func (m *SquareRootRequest) XXX_Unmarshal(b []byte) error {
-  return xxx_messageInfo_SquareRootRequest.Unmarshal(m, b)
+
+type MatrixInverseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *Matrix                `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
-func (m *SquareRootRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-  return xxx_messageInfo_SquareRootRequest.Marshal(b, m, deterministic)
+
+func (x *MatrixInverseResponse) Reset() {
+	*x = MatrixInverseResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-func (dst *SquareRootRequest) XXX_Merge(src proto.Message) {
-  xxx_messageInfo_SquareRootRequest.Merge(dst, src)
+
+func (x *MatrixInverseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (m *SquareRootRequest) XXX_Size() int {
-  return xxx_messageInfo_SquareRootRequest.Size(m)
+
+func (*MatrixInverseResponse) ProtoMessage() {}
+
+func (x *MatrixInverseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *SquareRootRequest) XXX_DiscardUnknown() {
-  xxx_messageInfo_SquareRootRequest.DiscardUnknown(m)
+
+// Deprecated: Use MatrixInverseResponse.ProtoReflect.Descriptor instead.
+func (*MatrixInverseResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{35}
 }
 
-var xxx_messageInfo_SquareRootRequest proto.InternalMessageInfo
+func (x *MatrixInverseResponse) GetResult() *Matrix {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
 
-func (m *SquareRootRequest) GetNumber() int32 {
-  if m != nil {
-    return m.Number
-  }
-  return 0
+// SolveLinearSystemRequest describes the system coefficients * x = constants.
+type SolveLinearSystemRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Coefficients  *Matrix                `protobuf:"bytes,1,opt,name=coefficients,proto3" json:"coefficients,omitempty"`
+	Constants     *Vector                `protobuf:"bytes,2,opt,name=constants,proto3" json:"constants,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-type SquareRootResponse struct {
-  NumberRoot           float64  `protobuf:"fixed64,1,opt,name=number_root,json=numberRoot,proto3" json:"number_root,omitempty"`
-  XXX_NoUnkeyedLiteral struct{} `json:"-"`
-  XXX_unrecognized     []byte   `json:"-"`
-  XXX_sizecache        int32    `json:"-"`
+func (x *SolveLinearSystemRequest) Reset() {
+	*x = SolveLinearSystemRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (m *SquareRootResponse) Reset()         { *m = SquareRootResponse{} }
-func (m *SquareRootResponse) String() string { return proto.CompactTextString(m) }
-func (*SquareRootResponse) ProtoMessage()    {}
-func (*SquareRootResponse) Descriptor() ([]byte, []int) {
-  return fileDescriptor_calculator_0799ea845ca306d9, []int{9}
+func (x *SolveLinearSystemRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SolveLinearSystemRequest) ProtoMessage() {}
+
+func (x *SolveLinearSystemRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SolveLinearSystemRequest.ProtoReflect.Descriptor instead.
+func (*SolveLinearSystemRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SolveLinearSystemRequest) GetCoefficients() *Matrix {
+	if x != nil {
+		return x.Coefficients
+	}
+	return nil
+}
+
+func (x *SolveLinearSystemRequest) GetConstants() *Vector {
+	if x != nil {
+		return x.Constants
+	}
+	return nil
+}
+
+type SolveLinearSystemResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Solution      *Vector                `protobuf:"bytes,1,opt,name=solution,proto3" json:"solution,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SolveLinearSystemResponse) Reset() {
+	*x = SolveLinearSystemResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SolveLinearSystemResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SolveLinearSystemResponse) ProtoMessage() {}
+
+func (x *SolveLinearSystemResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SolveLinearSystemResponse.ProtoReflect.Descriptor instead.
+func (*SolveLinearSystemResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SolveLinearSystemResponse) GetSolution() *Vector {
+	if x != nil {
+		return x.Solution
+	}
+	return nil
+}
+
+type MatrixDeterminantRowsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Row           *Vector                `protobuf:"bytes,1,opt,name=row,proto3" json:"row,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatrixDeterminantRowsRequest) Reset() {
+	*x = MatrixDeterminantRowsRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatrixDeterminantRowsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatrixDeterminantRowsRequest) ProtoMessage() {}
+
+func (x *MatrixDeterminantRowsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatrixDeterminantRowsRequest.ProtoReflect.Descriptor instead.
+func (*MatrixDeterminantRowsRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{38}
 }
-func (m *SquareRootResponse) XXX_Unmarshal(b []byte) error {
-  return xxx_messageInfo_SquareRootResponse.Unmarshal(m, b)
+
+func (x *MatrixDeterminantRowsRequest) GetRow() *Vector {
+	if x != nil {
+		return x.Row
+	}
+	return nil
+}
+
+type MatrixDeterminantRowsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Determinant   float64                `protobuf:"fixed64,1,opt,name=determinant,proto3" json:"determinant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatrixDeterminantRowsResponse) Reset() {
+	*x = MatrixDeterminantRowsResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatrixDeterminantRowsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatrixDeterminantRowsResponse) ProtoMessage() {}
+
+func (x *MatrixDeterminantRowsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatrixDeterminantRowsResponse.ProtoReflect.Descriptor instead.
+func (*MatrixDeterminantRowsResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *MatrixDeterminantRowsResponse) GetDeterminant() float64 {
+	if x != nil {
+		return x.Determinant
+	}
+	return 0
+}
+
+type CreateSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSessionRequest) Reset() {
+	*x = CreateSessionRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionRequest) ProtoMessage() {}
+
+func (x *CreateSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSessionRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{40}
+}
+
+type CreateSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	TtlSeconds    int64                  `protobuf:"varint,2,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"` // the session expires after this many seconds without use.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSessionResponse) Reset() {
+	*x = CreateSessionResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSessionResponse) ProtoMessage() {}
+
+func (x *CreateSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSessionResponse.ProtoReflect.Descriptor instead.
+func (*CreateSessionResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CreateSessionResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *CreateSessionResponse) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type CloseSessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseSessionRequest) Reset() {
+	*x = CloseSessionRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseSessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseSessionRequest) ProtoMessage() {}
+
+func (x *CloseSessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseSessionRequest.ProtoReflect.Descriptor instead.
+func (*CloseSessionRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CloseSessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+type CloseSessionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CloseSessionResponse) Reset() {
+	*x = CloseSessionResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CloseSessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CloseSessionResponse) ProtoMessage() {}
+
+func (x *CloseSessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CloseSessionResponse.ProtoReflect.Descriptor instead.
+func (*CloseSessionResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{43}
 }
-func (m *SquareRootResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-  return xxx_messageInfo_SquareRootResponse.Marshal(b, m, deterministic)
+
+// EvaluateRequest carries an expression such as "x = 3 * (2 + 1)", "ans / 2" or "$1 + x",
+// where ans is the last result and $n the nth result of the session.
+type EvaluateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Expression    string                 `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateRequest) Reset() {
+	*x = EvaluateRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateRequest) ProtoMessage() {}
+
+func (x *EvaluateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateRequest.ProtoReflect.Descriptor instead.
+func (*EvaluateRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *EvaluateRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *EvaluateRequest) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+type EvaluateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        float64                `protobuf:"fixed64,1,opt,name=result,proto3" json:"result,omitempty"`
+	Index         int32                  `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"` // the n to reference this result as $n.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EvaluateResponse) Reset() {
+	*x = EvaluateResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EvaluateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EvaluateResponse) ProtoMessage() {}
+
+func (x *EvaluateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EvaluateResponse.ProtoReflect.Descriptor instead.
+func (*EvaluateResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *EvaluateResponse) GetResult() float64 {
+	if x != nil {
+		return x.Result
+	}
+	return 0
+}
+
+func (x *EvaluateResponse) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+type SessionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"` // only read from the first message, a new session is created when empty.
+	Expression    string                 `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionRequest) Reset() {
+	*x = SessionRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SessionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionRequest) ProtoMessage() {}
+
+func (x *SessionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SessionRequest.ProtoReflect.Descriptor instead.
+func (*SessionRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *SessionRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SessionRequest) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+type SessionError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"` // google.golang.org/grpc/codes value.
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SessionError) Reset() {
+	*x = SessionError{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-func (dst *SquareRootResponse) XXX_Merge(src proto.Message) {
-  xxx_messageInfo_SquareRootResponse.Merge(dst, src)
+
+func (x *SessionError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SessionError) ProtoMessage() {}
+
+func (x *SessionError) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-func (m *SquareRootResponse) XXX_Size() int {
-  return xxx_messageInfo_SquareRootResponse.Size(m)
+
+// Deprecated: Use SessionError.ProtoReflect.Descriptor instead.
+func (*SessionError) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SessionError) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
 }
-func (m *SquareRootResponse) XXX_DiscardUnknown() {
-  xxx_messageInfo_SquareRootResponse.DiscardUnknown(m)
+
+func (x *SessionError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
 }
 
-var xxx_messageInfo_SquareRootResponse proto.InternalMessageInfo
+type SessionResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	SessionId  string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Expression string                 `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"` // the expression this response answers.
+	// Types that are valid to be assigned to Result:
+	//
+	//	*SessionResponse_Evaluation
+	//	*SessionResponse_Error
+	Result        isSessionResponse_Result `protobuf_oneof:"result"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-func (m *SquareRootResponse) GetNumberRoot() float64 {
-  if m != nil {
-    return m.NumberRoot
-  }
-  return 0
+func (x *SessionResponse) Reset() {
+	*x = SessionResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func init() {
-  proto.RegisterType((*SumRequest)(nil), "calculator.SumRequest")
-  proto.RegisterType((*SumResponse)(nil), "calculator.SumResponse")
-  proto.RegisterType((*PrimeNumberDecompositionRequest)(nil), "calculator.PrimeNumberDecompositionRequest")
-  proto.RegisterType((*PrimeNumberDecompositionResponse)(nil), "calculator.PrimeNumberDecompositionResponse")
-  proto.RegisterType((*ComputeAverageRequest)(nil), "calculator.ComputeAverageRequest")
-  proto.RegisterType((*ComputeAverageResponse)(nil), "calculator.ComputeAverageResponse")
-  proto.RegisterType((*FindMaximumRequest)(nil), "calculator.FindMaximumRequest")
-  proto.RegisterType((*FindMaximumResponse)(nil), "calculator.FindMaximumResponse")
-  proto.RegisterType((*SquareRootRequest)(nil), "calculator.SquareRootRequest")
-  proto.RegisterType((*SquareRootResponse)(nil), "calculator.SquareRootResponse")
+func (x *SessionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (*SessionResponse) ProtoMessage() {}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (x *SessionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
 
-// CalculatorServiceClient is the client API for CalculatorService service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type CalculatorServiceClient interface {
-  Sum(ctx context.Context, in *SumRequest, opts ...grpc.CallOption) (*SumResponse, error)
-  PrimeNumberDecomposition(ctx context.Context, in *PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (CalculatorService_PrimeNumberDecompositionClient, error)
-  ComputeAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeAverageClient, error)
-  FindMaximum(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_FindMaximumClient, error)
-  SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error)
+// Deprecated: Use SessionResponse.ProtoReflect.Descriptor instead.
+func (*SessionResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{48}
 }
 
-type calculatorServiceClient struct {
-  cc *grpc.ClientConn
+func (x *SessionResponse) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
 }
 
-func NewCalculatorServiceClient(cc *grpc.ClientConn) CalculatorServiceClient {
-  return &calculatorServiceClient{cc}
+func (x *SessionResponse) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
 }
 
-func (c *calculatorServiceClient) Sum(ctx context.Context, in *SumRequest, opts ...grpc.CallOption) (*SumResponse, error) {
-  out := new(SumResponse)
-  err := c.cc.Invoke(ctx, "/calculator.CalculatorService/Sum", in, out, opts...)
-  if err != nil {
-    return nil, err
-  }
-  return out, nil
+func (x *SessionResponse) GetResult() isSessionResponse_Result {
+	if x != nil {
+		return x.Result
+	}
+	return nil
 }
 
-func (c *calculatorServiceClient) PrimeNumberDecomposition(ctx context.Context, in *PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (CalculatorService_PrimeNumberDecompositionClient, error) {
-  stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[0], "/calculator.CalculatorService/PrimeNumberDecomposition", opts...)
-  if err != nil {
-    return nil, err
-  }
-  x := &calculatorServicePrimeNumberDecompositionClient{stream}
-  if err := x.ClientStream.SendMsg(in); err != nil {
-    return nil, err
-  }
-  if err := x.ClientStream.CloseSend(); err != nil {
-    return nil, err
-  }
-  return x, nil
+func (x *SessionResponse) GetEvaluation() *EvaluateResponse {
+	if x != nil {
+		if x, ok := x.Result.(*SessionResponse_Evaluation); ok {
+			return x.Evaluation
+		}
+	}
+	return nil
 }
 
-type CalculatorService_PrimeNumberDecompositionClient interface {
-  Recv() (*PrimeNumberDecompositionResponse, error)
-  grpc.ClientStream
+func (x *SessionResponse) GetError() *SessionError {
+	if x != nil {
+		if x, ok := x.Result.(*SessionResponse_Error); ok {
+			return x.Error
+		}
+	}
+	return nil
 }
 
-type calculatorServicePrimeNumberDecompositionClient struct {
-  grpc.ClientStream
+type isSessionResponse_Result interface {
+	isSessionResponse_Result()
 }
 
-func (x *calculatorServicePrimeNumberDecompositionClient) Recv() (*PrimeNumberDecompositionResponse, error) {
-  m := new(PrimeNumberDecompositionResponse)
-  if err := x.ClientStream.RecvMsg(m); err != nil {
-    return nil, err
-  }
-  return m, nil
+type SessionResponse_Evaluation struct {
+	Evaluation *EvaluateResponse `protobuf:"bytes,3,opt,name=evaluation,proto3,oneof"`
 }
 
-func (c *calculatorServiceClient) ComputeAverage(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_ComputeAverageClient, error) {
-  stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[1], "/calculator.CalculatorService/ComputeAverage", opts...)
-  if err != nil {
-    return nil, err
-  }
-  x := &calculatorServiceComputeAverageClient{stream}
-  return x, nil
+type SessionResponse_Error struct {
+	Error *SessionError `protobuf:"bytes,4,opt,name=error,proto3,oneof"`
 }
 
-type CalculatorService_ComputeAverageClient interface {
-  Send(*ComputeAverageRequest) error
-  CloseAndRecv() (*ComputeAverageResponse, error)
-  grpc.ClientStream
+func (*SessionResponse_Evaluation) isSessionResponse_Result() {}
+
+func (*SessionResponse_Error) isSessionResponse_Result() {}
+
+type HistoryEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Method        string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`   // full method name, as in /calculator.CalculatorService/Sum.
+	Inputs        string                 `protobuf:"bytes,3,opt,name=inputs,proto3" json:"inputs,omitempty"`   // JSON of the request, a JSON array of the requests for client streams.
+	Result        string                 `protobuf:"bytes,4,opt,name=result,proto3" json:"result,omitempty"`   // JSON of the response, a JSON array of the responses for server streams.
+	Code          int32                  `protobuf:"varint,5,opt,name=code,proto3" json:"code,omitempty"`      // google.golang.org/grpc/codes value.
+	Message       string                 `protobuf:"bytes,6,opt,name=message,proto3" json:"message,omitempty"` // status message when the call failed.
+	Caller        string                 `protobuf:"bytes,7,opt,name=caller,proto3" json:"caller,omitempty"`   // peer address of the caller.
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Client        string                 `protobuf:"bytes,9,opt,name=client,proto3" json:"client,omitempty"` // identity of the caller: "principal:" and the name of its certificate, else "ip:" and its IP address.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-type calculatorServiceComputeAverageClient struct {
-  grpc.ClientStream
+func (x *HistoryEntry) Reset() {
+	*x = HistoryEntry{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *calculatorServiceComputeAverageClient) Send(m *ComputeAverageRequest) error {
-  return x.ClientStream.SendMsg(m)
+func (x *HistoryEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *calculatorServiceComputeAverageClient) CloseAndRecv() (*ComputeAverageResponse, error) {
-  if err := x.ClientStream.CloseSend(); err != nil {
-    return nil, err
-  }
-  m := new(ComputeAverageResponse)
-  if err := x.ClientStream.RecvMsg(m); err != nil {
-    return nil, err
-  }
-  return m, nil
+func (*HistoryEntry) ProtoMessage() {}
+
+func (x *HistoryEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (c *calculatorServiceClient) FindMaximum(ctx context.Context, opts ...grpc.CallOption) (CalculatorService_FindMaximumClient, error) {
-  stream, err := c.cc.NewStream(ctx, &_CalculatorService_serviceDesc.Streams[2], "/calculator.CalculatorService/FindMaximum", opts...)
-  if err != nil {
-    return nil, err
-  }
-  x := &calculatorServiceFindMaximumClient{stream}
-  return x, nil
+// Deprecated: Use HistoryEntry.ProtoReflect.Descriptor instead.
+func (*HistoryEntry) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{49}
 }
 
-type CalculatorService_FindMaximumClient interface {
-  Send(*FindMaximumRequest) error
-  Recv() (*FindMaximumResponse, error)
-  grpc.ClientStream
+func (x *HistoryEntry) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
 }
 
-type calculatorServiceFindMaximumClient struct {
-  grpc.ClientStream
+func (x *HistoryEntry) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
 }
 
-func (x *calculatorServiceFindMaximumClient) Send(m *FindMaximumRequest) error {
-  return x.ClientStream.SendMsg(m)
+func (x *HistoryEntry) GetInputs() string {
+	if x != nil {
+		return x.Inputs
+	}
+	return ""
 }
 
-func (x *calculatorServiceFindMaximumClient) Recv() (*FindMaximumResponse, error) {
-  m := new(FindMaximumResponse)
-  if err := x.ClientStream.RecvMsg(m); err != nil {
-    return nil, err
-  }
-  return m, nil
+func (x *HistoryEntry) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
 }
 
-func (c *calculatorServiceClient) SquareRoot(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error) {
-  out := new(SquareRootResponse)
-  err := c.cc.Invoke(ctx, "/calculator.CalculatorService/SquareRoot", in, out, opts...)
-  if err != nil {
-    return nil, err
-  }
-  return out, nil
+func (x *HistoryEntry) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
 }
 
-// CalculatorServiceServer is the server API for CalculatorService service.
-type CalculatorServiceServer interface {
-  Sum(context.Context, *SumRequest) (*SumResponse, error)
-  PrimeNumberDecomposition(*PrimeNumberDecompositionRequest, CalculatorService_PrimeNumberDecompositionServer) error
-  ComputeAverage(CalculatorService_ComputeAverageServer) error
-  FindMaximum(CalculatorService_FindMaximumServer) error
-  SquareRoot(context.Context, *SquareRootRequest) (*SquareRootResponse, error)
+func (x *HistoryEntry) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
 }
 
-func RegisterCalculatorServiceServer(s *grpc.Server, srv CalculatorServiceServer) {
-  s.RegisterService(&_CalculatorService_serviceDesc, srv)
+func (x *HistoryEntry) GetCaller() string {
+	if x != nil {
+		return x.Caller
+	}
+	return ""
 }
 
-func _CalculatorService_Sum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-  in := new(SumRequest)
-  if err := dec(in); err != nil {
-    return nil, err
-  }
-  if interceptor == nil {
-    return srv.(CalculatorServiceServer).Sum(ctx, in)
-  }
-  info := &grpc.UnaryServerInfo{
-    Server:     srv,
-    FullMethod: "/calculator.CalculatorService/Sum",
-  }
-  handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-    return srv.(CalculatorServiceServer).Sum(ctx, req.(*SumRequest))
-  }
-  return interceptor(ctx, in, info, handler)
+func (x *HistoryEntry) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
 }
 
-func _CalculatorService_PrimeNumberDecomposition_Handler(srv interface{}, stream grpc.ServerStream) error {
-  m := new(PrimeNumberDecompositionRequest)
-  if err := stream.RecvMsg(m); err != nil {
-    return err
-  }
-  return srv.(CalculatorServiceServer).PrimeNumberDecomposition(m, &calculatorServicePrimeNumberDecompositionServer{stream})
+func (x *HistoryEntry) GetClient() string {
+	if x != nil {
+		return x.Client
+	}
+	return ""
 }
 
-type CalculatorService_PrimeNumberDecompositionServer interface {
-  Send(*PrimeNumberDecompositionResponse) error
-  grpc.ServerStream
+type ListHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Method        string                 `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`                        // only entries of this method, full or short name.
+	StartTime     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"` // only entries at or after this time.
+	EndTime       *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`       // only entries before this time.
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`   // defaults to 50, at most 1000.
+	PageToken     string                 `protobuf:"bytes,5,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"` // next_page_token of the previous page.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-type calculatorServicePrimeNumberDecompositionServer struct {
-  grpc.ServerStream
+func (x *ListHistoryRequest) Reset() {
+	*x = ListHistoryRequest{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *calculatorServicePrimeNumberDecompositionServer) Send(m *PrimeNumberDecompositionResponse) error {
-  return x.ServerStream.SendMsg(m)
+func (x *ListHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func _CalculatorService_ComputeAverage_Handler(srv interface{}, stream grpc.ServerStream) error {
-  return srv.(CalculatorServiceServer).ComputeAverage(&calculatorServiceComputeAverageServer{stream})
+func (*ListHistoryRequest) ProtoMessage() {}
+
+func (x *ListHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-type CalculatorService_ComputeAverageServer interface {
-  SendAndClose(*ComputeAverageResponse) error
-  Recv() (*ComputeAverageRequest, error)
-  grpc.ServerStream
+// Deprecated: Use ListHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ListHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{50}
 }
 
-type calculatorServiceComputeAverageServer struct {
-  grpc.ServerStream
+func (x *ListHistoryRequest) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
 }
 
-func (x *calculatorServiceComputeAverageServer) SendAndClose(m *ComputeAverageResponse) error {
-  return x.ServerStream.SendMsg(m)
+func (x *ListHistoryRequest) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
 }
 
-func (x *calculatorServiceComputeAverageServer) Recv() (*ComputeAverageRequest, error) {
-  m := new(ComputeAverageRequest)
-  if err := x.ServerStream.RecvMsg(m); err != nil {
-    return nil, err
-  }
-  return m, nil
-}
-
-func _CalculatorService_FindMaximum_Handler(srv interface{}, stream grpc.ServerStream) error {
-  return srv.(CalculatorServiceServer).FindMaximum(&calculatorServiceFindMaximumServer{stream})
-}
-
-type CalculatorService_FindMaximumServer interface {
-  Send(*FindMaximumResponse) error
-  Recv() (*FindMaximumRequest, error)
-  grpc.ServerStream
-}
-
-type calculatorServiceFindMaximumServer struct {
-  grpc.ServerStream
-}
-
-func (x *calculatorServiceFindMaximumServer) Send(m *FindMaximumResponse) error {
-  return x.ServerStream.SendMsg(m)
-}
-
-func (x *calculatorServiceFindMaximumServer) Recv() (*FindMaximumRequest, error) {
-  m := new(FindMaximumRequest)
-  if err := x.ServerStream.RecvMsg(m); err != nil {
-    return nil, err
-  }
-  return m, nil
-}
-
-func _CalculatorService_SquareRoot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-  in := new(SquareRootRequest)
-  if err := dec(in); err != nil {
-    return nil, err
-  }
-  if interceptor == nil {
-    return srv.(CalculatorServiceServer).SquareRoot(ctx, in)
-  }
-  info := &grpc.UnaryServerInfo{
-    Server:     srv,
-    FullMethod: "/calculator.CalculatorService/SquareRoot",
-  }
-  handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-    return srv.(CalculatorServiceServer).SquareRoot(ctx, req.(*SquareRootRequest))
-  }
-  return interceptor(ctx, in, info, handler)
-}
-
-var _CalculatorService_serviceDesc = grpc.ServiceDesc{
-  ServiceName: "calculator.CalculatorService",
-  HandlerType: (*CalculatorServiceServer)(nil),
-  Methods: []grpc.MethodDesc{
-    {
-      MethodName: "Sum",
-      Handler:    _CalculatorService_Sum_Handler,
-    },
-    {
-      MethodName: "SquareRoot",
-      Handler:    _CalculatorService_SquareRoot_Handler,
-    },
-  },
-  Streams: []grpc.StreamDesc{
-    {
-      StreamName:    "PrimeNumberDecomposition",
-      Handler:       _CalculatorService_PrimeNumberDecomposition_Handler,
-      ServerStreams: true,
-    },
-    {
-      StreamName:    "ComputeAverage",
-      Handler:       _CalculatorService_ComputeAverage_Handler,
-      ClientStreams: true,
-    },
-    {
-      StreamName:    "FindMaximum",
-      Handler:       _CalculatorService_FindMaximum_Handler,
-      ServerStreams: true,
-      ClientStreams: true,
-    },
-  },
-  Metadata: "calculator/calculatorpb/calculator.proto",
-}
-
-func init() {
-  proto.RegisterFile("calculator/calculatorpb/calculator.proto", fileDescriptor_calculator_0799ea845ca306d9)
-}
-
-var fileDescriptor_calculator_0799ea845ca306d9 = []byte{
-  // 433 bytes of a gzipped FileDescriptorProto
-  0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x54, 0x5b, 0x6b, 0xd4, 0x40,
-  0x14, 0x36, 0x2e, 0xad, 0x78, 0x12, 0x0b, 0x3d, 0xe2, 0xba, 0x04, 0xda, 0xed, 0x8e, 0x2f, 0x0b,
-  0x5d, 0xba, 0xa5, 0x22, 0xe8, 0xa3, 0x56, 0xfb, 0x56, 0x91, 0xa4, 0x4f, 0xfa, 0x10, 0xb2, 0xe9,
-  0x54, 0x02, 0x99, 0x4c, 0x3a, 0x97, 0xa2, 0x7f, 0xd1, 0x5f, 0x25, 0x9d, 0xc9, 0x6c, 0x66, 0x2f,
-  0x71, 0x7d, 0xcb, 0xf9, 0xe6, 0xbb, 0x0c, 0x39, 0x1f, 0x03, 0xd3, 0x22, 0xaf, 0x0a, 0x5d, 0xe5,
-  0x8a, 0x8b, 0x79, 0xf7, 0xd9, 0x2c, 0xbc, 0xe1, 0xac, 0x11, 0x5c, 0x71, 0x84, 0x0e, 0x21, 0x37,
-  0x00, 0xa9, 0x66, 0x09, 0xbd, 0xd7, 0x54, 0x2a, 0x9c, 0x40, 0x74, 0x57, 0x0a, 0xa9, 0xb2, 0x5a,
-  0xb3, 0x05, 0x15, 0xa3, 0xe0, 0x24, 0x98, 0xee, 0x25, 0xa1, 0xc1, 0xbe, 0x1a, 0x08, 0xdf, 0xc0,
-  0x0b, 0x49, 0x0b, 0x5e, 0xdf, 0x3a, 0xce, 0x53, 0xc3, 0x89, 0x2c, 0x68, 0x49, 0x64, 0x06, 0xa1,
-  0x71, 0x95, 0x0d, 0xaf, 0x25, 0xc5, 0x23, 0x00, 0xa9, 0x59, 0x26, 0xa8, 0xd4, 0x95, 0x6a, 0x4d,
-  0x9f, 0x4b, 0x43, 0xd0, 0x95, 0x22, 0x1f, 0x60, 0xfc, 0x4d, 0x94, 0x8c, 0x5a, 0xf1, 0x67, 0x5a,
-  0x70, 0xd6, 0x70, 0x59, 0xaa, 0x92, 0xd7, 0xee, 0x62, 0x43, 0xd8, 0xf7, 0xae, 0x34, 0x48, 0xda,
-  0x89, 0x7c, 0x81, 0x93, 0x7e, 0x69, 0x9b, 0x3e, 0x81, 0xa8, 0x79, 0xe4, 0x64, 0x77, 0x79, 0xa1,
-  0xb8, 0x73, 0x08, 0x0d, 0x76, 0x65, 0x20, 0x32, 0x87, 0x57, 0x97, 0x9c, 0x35, 0x5a, 0xd1, 0x8f,
-  0x0f, 0x54, 0xe4, 0x3f, 0xe9, 0xf6, 0xdc, 0xbd, 0x65, 0xee, 0x05, 0x0c, 0xd7, 0x05, 0x6d, 0xda,
-  0x08, 0x9e, 0xe5, 0x16, 0x32, 0x92, 0x20, 0x71, 0x23, 0x99, 0x01, 0x5e, 0x95, 0xf5, 0xed, 0x75,
-  0xfe, 0xab, 0x64, 0xdd, 0x2f, 0xef, 0x4b, 0x98, 0xc3, 0xcb, 0x15, 0x76, 0x67, 0xcf, 0x2c, 0xd4,
-  0xf2, 0xdd, 0x48, 0x4e, 0xe1, 0x30, 0xbd, 0xd7, 0xb9, 0xa0, 0x09, 0xe7, 0x6a, 0x97, 0xfb, 0x3b,
-  0x40, 0x9f, 0xdc, 0x9a, 0x8f, 0x21, 0xb4, 0xe7, 0x99, 0xe0, 0x5c, 0xb5, 0xf7, 0x07, 0x0b, 0x3d,
-  0x12, 0x2f, 0xfe, 0x0c, 0xe0, 0xf0, 0x72, 0x59, 0x9e, 0x94, 0x8a, 0x87, 0xb2, 0xa0, 0xf8, 0x1e,
-  0x06, 0xa9, 0x66, 0x38, 0x3c, 0xf3, 0x9a, 0xd6, 0x95, 0x2a, 0x7e, 0xbd, 0x81, 0xdb, 0x38, 0xf2,
-  0x04, 0x7f, 0xc3, 0xa8, 0x6f, 0x7d, 0x78, 0xea, 0xcb, 0x76, 0xf4, 0x23, 0x9e, 0xfd, 0x1f, 0xd9,
-  0x05, 0x9f, 0x07, 0xf8, 0x03, 0x0e, 0x56, 0x37, 0x88, 0x13, 0xdf, 0x63, 0x6b, 0x1d, 0x62, 0xf2,
-  0x2f, 0x8a, 0x33, 0x9f, 0x06, 0x78, 0x03, 0xa1, 0xb7, 0x3c, 0x3c, 0xf6, 0x65, 0x9b, 0x1d, 0x88,
-  0xc7, 0xbd, 0xe7, 0x9d, 0xe7, 0x79, 0x80, 0xd7, 0x00, 0xdd, 0xd2, 0xf0, 0x68, 0xe5, 0xb7, 0xae,
-  0x6f, 0x3e, 0x3e, 0xee, 0x3b, 0x76, 0x96, 0x9f, 0x0e, 0xbe, 0x47, 0xfe, 0x3b, 0xb1, 0xd8, 0x37,
-  0xaf, 0xc3, 0xdb, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0x9e, 0x96, 0xbb, 0x6e, 0x49, 0x04, 0x00,
-  0x00,
+func (x *ListHistoryRequest) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *ListHistoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListHistoryRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*HistoryEntry        `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`                                    // oldest first.
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"` // empty on the last page.
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListHistoryResponse) Reset() {
+	*x = ListHistoryResponse{}
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListHistoryResponse) ProtoMessage() {}
+
+func (x *ListHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_calculator_calculatorpb_calculator_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListHistoryResponse.ProtoReflect.Descriptor instead.
+func (*ListHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_calculator_calculatorpb_calculator_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *ListHistoryResponse) GetEntries() []*HistoryEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *ListHistoryResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+var File_calculator_calculatorpb_calculator_proto protoreflect.FileDescriptor
+
+const file_calculator_calculatorpb_calculator_proto_rawDesc = "" +
+	"\n" +
+	"(calculator/calculatorpb/calculator.proto\x12\n" +
+	"calculator\x1a\x1fgoogle/protobuf/timestamp.proto\"T\n" +
+	"\n" +
+	"SumRequest\x12!\n" +
+	"\ffirst_number\x18\x01 \x01(\x05R\vfirstNumber\x12#\n" +
+	"\rsecond_number\x18\x02 \x01(\x05R\fsecondNumber\",\n" +
+	"\vSumResponse\x12\x1d\n" +
+	"\n" +
+	"sum_result\x18\x01 \x01(\x05R\tsumResult\"9\n" +
+	"\x1fPrimeNumberDecompositionRequest\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x03R\x06number\"E\n" +
+	" PrimeNumberDecompositionResponse\x12!\n" +
+	"\fprime_factor\x18\x01 \x01(\x03R\vprimeFactor\"/\n" +
+	"\x15ComputeAverageRequest\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x05R\x06number\"2\n" +
+	"\x16ComputeAverageResponse\x12\x18\n" +
+	"\aaverage\x18\x01 \x01(\x01R\aaverage\",\n" +
+	"\x12FindMaximumRequest\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x05R\x06number\"/\n" +
+	"\x13FindMaximumResponse\x12\x18\n" +
+	"\amaximum\x18\x01 \x01(\x05R\amaximum\"P\n" +
+	"\x11SquareRootRequest\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x05R\x06number\x12#\n" +
+	"\rallow_complex\x18\x02 \x01(\bR\fallowComplex\"?\n" +
+	"\x0fSquareRootError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xc8\x01\n" +
+	"\x12SquareRootResponse\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\x05R\x06number\x12!\n" +
+	"\vnumber_root\x18\x01 \x01(\x01H\x00R\n" +
+	"numberRoot\x123\n" +
+	"\x05error\x18\x03 \x01(\v2\x1b.calculator.SquareRootErrorH\x00R\x05error\x128\n" +
+	"\fcomplex_root\x18\x04 \x01(\v2\x13.calculator.ComplexH\x00R\vcomplexRootB\b\n" +
+	"\x06result\";\n" +
+	"\aComplex\x12\x12\n" +
+	"\x04real\x18\x01 \x01(\x01R\x04real\x12\x1c\n" +
+	"\timaginary\x18\x02 \x01(\x01R\timaginary\"k\n" +
+	"\x11ComplexAddRequest\x12)\n" +
+	"\x05first\x18\x01 \x01(\v2\x13.calculator.ComplexR\x05first\x12+\n" +
+	"\x06second\x18\x02 \x01(\v2\x13.calculator.ComplexR\x06second\"A\n" +
+	"\x12ComplexAddResponse\x12+\n" +
+	"\x06result\x18\x01 \x01(\v2\x13.calculator.ComplexR\x06result\"p\n" +
+	"\x16ComplexMultiplyRequest\x12)\n" +
+	"\x05first\x18\x01 \x01(\v2\x13.calculator.ComplexR\x05first\x12+\n" +
+	"\x06second\x18\x02 \x01(\v2\x13.calculator.ComplexR\x06second\"F\n" +
+	"\x17ComplexMultiplyResponse\x12+\n" +
+	"\x06result\x18\x01 \x01(\v2\x13.calculator.ComplexR\x06result\"v\n" +
+	"\x14ComplexDivideRequest\x12/\n" +
+	"\bdividend\x18\x01 \x01(\v2\x13.calculator.ComplexR\bdividend\x12-\n" +
+	"\adivisor\x18\x02 \x01(\v2\x13.calculator.ComplexR\adivisor\"D\n" +
+	"\x15ComplexDivideResponse\x12+\n" +
+	"\x06result\x18\x01 \x01(\v2\x13.calculator.ComplexR\x06result\"D\n" +
+	"\x15ComplexModulusRequest\x12+\n" +
+	"\x06number\x18\x01 \x01(\v2\x13.calculator.ComplexR\x06number\"2\n" +
+	"\x16ComplexModulusResponse\x12\x18\n" +
+	"\amodulus\x18\x01 \x01(\x01R\amodulus\"E\n" +
+	"\x16ComplexArgumentRequest\x12+\n" +
+	"\x06number\x18\x01 \x01(\v2\x13.calculator.ComplexR\x06number\"5\n" +
+	"\x17ComplexArgumentResponse\x12\x1a\n" +
+	"\bargument\x18\x01 \x01(\x01R\bargument\"Z\n" +
+	"\x13ComplexRootsRequest\x12+\n" +
+	"\x06number\x18\x01 \x01(\v2\x13.calculator.ComplexR\x06number\x12\x16\n" +
+	"\x06degree\x18\x02 \x01(\x05R\x06degree\"?\n" +
+	"\x14ComplexRootsResponse\x12'\n" +
+	"\x04root\x18\x01 \x01(\v2\x13.calculator.ComplexR\x04root\" \n" +
+	"\x06Vector\x12\x16\n" +
+	"\x06values\x18\x01 \x03(\x01R\x06values\"0\n" +
+	"\x06Matrix\x12&\n" +
+	"\x04rows\x18\x01 \x03(\v2\x12.calculator.VectorR\x04rows\"h\n" +
+	"\x10MatrixAddRequest\x12(\n" +
+	"\x05first\x18\x01 \x01(\v2\x12.calculator.MatrixR\x05first\x12*\n" +
+	"\x06second\x18\x02 \x01(\v2\x12.calculator.MatrixR\x06second\"?\n" +
+	"\x11MatrixAddResponse\x12*\n" +
+	"\x06result\x18\x01 \x01(\v2\x12.calculator.MatrixR\x06result\"m\n" +
+	"\x15MatrixMultiplyRequest\x12(\n" +
+	"\x05first\x18\x01 \x01(\v2\x12.calculator.MatrixR\x05first\x12*\n" +
+	"\x06second\x18\x02 \x01(\v2\x12.calculator.MatrixR\x06second\"D\n" +
+	"\x16MatrixMultiplyResponse\x12*\n" +
+	"\x06result\x18\x01 \x01(\v2\x12.calculator.MatrixR\x06result\"D\n" +
+	"\x16MatrixTransposeRequest\x12*\n" +
+	"\x06matrix\x18\x01 \x01(\v2\x12.calculator.MatrixR\x06matrix\"E\n" +
+	"\x17MatrixTransposeResponse\x12*\n" +
+	"\x06result\x18\x01 \x01(\v2\x12.calculator.MatrixR\x06result\"F\n" +
+	"\x18MatrixDeterminantRequest\x12*\n" +
+	"\x06matrix\x18\x01 \x01(\v2\x12.calculator.MatrixR\x06matrix\"=\n" +
+	"\x19MatrixDeterminantResponse\x12 \n" +
+	"\vdeterminant\x18\x01 \x01(\x01R\vdeterminant\"B\n" +
+	"\x14MatrixInverseRequest\x12*\n" +
+	"\x06matrix\x18\x01 \x01(\v2\x12.calculator.MatrixR\x06matrix\"C\n" +
+	"\x15MatrixInverseResponse\x12*\n" +
+	"\x06result\x18\x01 \x01(\v2\x12.calculator.MatrixR\x06result\"\x84\x01\n" +
+	"\x18SolveLinearSystemRequest\x126\n" +
+	"\fcoefficients\x18\x01 \x01(\v2\x12.calculator.MatrixR\fcoefficients\x120\n" +
+	"\tconstants\x18\x02 \x01(\v2\x12.calculator.VectorR\tconstants\"K\n" +
+	"\x19SolveLinearSystemResponse\x12.\n" +
+	"\bsolution\x18\x01 \x01(\v2\x12.calculator.VectorR\bsolution\"D\n" +
+	"\x1cMatrixDeterminantRowsRequest\x12$\n" +
+	"\x03row\x18\x01 \x01(\v2\x12.calculator.VectorR\x03row\"A\n" +
+	"\x1dMatrixDeterminantRowsResponse\x12 \n" +
+	"\vdeterminant\x18\x01 \x01(\x01R\vdeterminant\"\x16\n" +
+	"\x14CreateSessionRequest\"W\n" +
+	"\x15CreateSessionResponse\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1f\n" +
+	"\vttl_seconds\x18\x02 \x01(\x03R\n" +
+	"ttlSeconds\"4\n" +
+	"\x13CloseSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"\x16\n" +
+	"\x14CloseSessionResponse\"P\n" +
+	"\x0fEvaluateRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x02 \x01(\tR\n" +
+	"expression\"@\n" +
+	"\x10EvaluateResponse\x12\x16\n" +
+	"\x06result\x18\x01 \x01(\x01R\x06result\x12\x14\n" +
+	"\x05index\x18\x02 \x01(\x05R\x05index\"O\n" +
+	"\x0eSessionRequest\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x02 \x01(\tR\n" +
+	"expression\"<\n" +
+	"\fSessionError\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\xcc\x01\n" +
+	"\x0fSessionResponse\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x02 \x01(\tR\n" +
+	"expression\x12>\n" +
+	"\n" +
+	"evaluation\x18\x03 \x01(\v2\x1c.calculator.EvaluateResponseH\x00R\n" +
+	"evaluation\x120\n" +
+	"\x05error\x18\x04 \x01(\v2\x18.calculator.SessionErrorH\x00R\x05errorB\b\n" +
+	"\x06result\"\xfe\x01\n" +
+	"\fHistoryEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x16\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12\x16\n" +
+	"\x06inputs\x18\x03 \x01(\tR\x06inputs\x12\x16\n" +
+	"\x06result\x18\x04 \x01(\tR\x06result\x12\x12\n" +
+	"\x04code\x18\x05 \x01(\x05R\x04code\x12\x18\n" +
+	"\amessage\x18\x06 \x01(\tR\amessage\x12\x16\n" +
+	"\x06caller\x18\a \x01(\tR\x06caller\x128\n" +
+	"\ttimestamp\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x16\n" +
+	"\x06client\x18\t \x01(\tR\x06client\"\xda\x01\n" +
+	"\x12ListHistoryRequest\x12\x16\n" +
+	"\x06method\x18\x01 \x01(\tR\x06method\x129\n" +
+	"\n" +
+	"start_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
+	"\bend_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x05 \x01(\tR\tpageToken\"q\n" +
+	"\x13ListHistoryResponse\x122\n" +
+	"\aentries\x18\x01 \x03(\v2\x18.calculator.HistoryEntryR\aentries\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken2\xe0\x10\n" +
+	"\x11CalculatorService\x128\n" +
+	"\x03Sum\x12\x16.calculator.SumRequest\x1a\x17.calculator.SumResponse\"\x00\x12y\n" +
+	"\x18PrimeNumberDecomposition\x12+.calculator.PrimeNumberDecompositionRequest\x1a,.calculator.PrimeNumberDecompositionResponse\"\x000\x01\x12[\n" +
+	"\x0eComputeAverage\x12!.calculator.ComputeAverageRequest\x1a\".calculator.ComputeAverageResponse\"\x00(\x01\x12T\n" +
+	"\vFindMaximum\x12\x1e.calculator.FindMaximumRequest\x1a\x1f.calculator.FindMaximumResponse\"\x00(\x010\x01\x12Q\n" +
+	"\n" +
+	"SquareRoot\x12\x1d.calculator.SquareRootRequest\x1a\x1e.calculator.SquareRootResponse\"\x00(\x010\x01\x12R\n" +
+	"\x0fSquareRootUnary\x12\x1d.calculator.SquareRootRequest\x1a\x1e.calculator.SquareRootResponse\"\x00\x12M\n" +
+	"\n" +
+	"ComplexAdd\x12\x1d.calculator.ComplexAddRequest\x1a\x1e.calculator.ComplexAddResponse\"\x00\x12\\\n" +
+	"\x0fComplexMultiply\x12\".calculator.ComplexMultiplyRequest\x1a#.calculator.ComplexMultiplyResponse\"\x00\x12V\n" +
+	"\rComplexDivide\x12 .calculator.ComplexDivideRequest\x1a!.calculator.ComplexDivideResponse\"\x00\x12Y\n" +
+	"\x0eComplexModulus\x12!.calculator.ComplexModulusRequest\x1a\".calculator.ComplexModulusResponse\"\x00\x12\\\n" +
+	"\x0fComplexArgument\x12\".calculator.ComplexArgumentRequest\x1a#.calculator.ComplexArgumentResponse\"\x00\x12U\n" +
+	"\fComplexRoots\x12\x1f.calculator.ComplexRootsRequest\x1a .calculator.ComplexRootsResponse\"\x000\x01\x12J\n" +
+	"\tMatrixAdd\x12\x1c.calculator.MatrixAddRequest\x1a\x1d.calculator.MatrixAddResponse\"\x00\x12Y\n" +
+	"\x0eMatrixMultiply\x12!.calculator.MatrixMultiplyRequest\x1a\".calculator.MatrixMultiplyResponse\"\x00\x12\\\n" +
+	"\x0fMatrixTranspose\x12\".calculator.MatrixTransposeRequest\x1a#.calculator.MatrixTransposeResponse\"\x00\x12b\n" +
+	"\x11MatrixDeterminant\x12$.calculator.MatrixDeterminantRequest\x1a%.calculator.MatrixDeterminantResponse\"\x00\x12V\n" +
+	"\rMatrixInverse\x12 .calculator.MatrixInverseRequest\x1a!.calculator.MatrixInverseResponse\"\x00\x12b\n" +
+	"\x11SolveLinearSystem\x12$.calculator.SolveLinearSystemRequest\x1a%.calculator.SolveLinearSystemResponse\"\x00\x12p\n" +
+	"\x15MatrixDeterminantRows\x12(.calculator.MatrixDeterminantRowsRequest\x1a).calculator.MatrixDeterminantRowsResponse\"\x00(\x01\x12V\n" +
+	"\rCreateSession\x12 .calculator.CreateSessionRequest\x1a!.calculator.CreateSessionResponse\"\x00\x12S\n" +
+	"\fCloseSession\x12\x1f.calculator.CloseSessionRequest\x1a .calculator.CloseSessionResponse\"\x00\x12G\n" +
+	"\bEvaluate\x12\x1b.calculator.EvaluateRequest\x1a\x1c.calculator.EvaluateResponse\"\x00\x12H\n" +
+	"\aSession\x12\x1a.calculator.SessionRequest\x1a\x1b.calculator.SessionResponse\"\x00(\x010\x01\x12P\n" +
+	"\vListHistory\x12\x1e.calculator.ListHistoryRequest\x1a\x1f.calculator.ListHistoryResponse\"\x00BFZDgithub.com/_dev/grpc-go-example/calculator/calculatorpb;calculatorpbb\x06proto3"
+
+var (
+	file_calculator_calculatorpb_calculator_proto_rawDescOnce sync.Once
+	file_calculator_calculatorpb_calculator_proto_rawDescData []byte
+)
+
+func file_calculator_calculatorpb_calculator_proto_rawDescGZIP() []byte {
+	file_calculator_calculatorpb_calculator_proto_rawDescOnce.Do(func() {
+		file_calculator_calculatorpb_calculator_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_calculator_calculatorpb_calculator_proto_rawDesc), len(file_calculator_calculatorpb_calculator_proto_rawDesc)))
+	})
+	return file_calculator_calculatorpb_calculator_proto_rawDescData
+}
+
+var file_calculator_calculatorpb_calculator_proto_msgTypes = make([]protoimpl.MessageInfo, 52)
+var file_calculator_calculatorpb_calculator_proto_goTypes = []any{
+	(*SumRequest)(nil),                       // 0: calculator.SumRequest
+	(*SumResponse)(nil),                      // 1: calculator.SumResponse
+	(*PrimeNumberDecompositionRequest)(nil),  // 2: calculator.PrimeNumberDecompositionRequest
+	(*PrimeNumberDecompositionResponse)(nil), // 3: calculator.PrimeNumberDecompositionResponse
+	(*ComputeAverageRequest)(nil),            // 4: calculator.ComputeAverageRequest
+	(*ComputeAverageResponse)(nil),           // 5: calculator.ComputeAverageResponse
+	(*FindMaximumRequest)(nil),               // 6: calculator.FindMaximumRequest
+	(*FindMaximumResponse)(nil),              // 7: calculator.FindMaximumResponse
+	(*SquareRootRequest)(nil),                // 8: calculator.SquareRootRequest
+	(*SquareRootError)(nil),                  // 9: calculator.SquareRootError
+	(*SquareRootResponse)(nil),               // 10: calculator.SquareRootResponse
+	(*Complex)(nil),                          // 11: calculator.Complex
+	(*ComplexAddRequest)(nil),                // 12: calculator.ComplexAddRequest
+	(*ComplexAddResponse)(nil),               // 13: calculator.ComplexAddResponse
+	(*ComplexMultiplyRequest)(nil),           // 14: calculator.ComplexMultiplyRequest
+	(*ComplexMultiplyResponse)(nil),          // 15: calculator.ComplexMultiplyResponse
+	(*ComplexDivideRequest)(nil),             // 16: calculator.ComplexDivideRequest
+	(*ComplexDivideResponse)(nil),            // 17: calculator.ComplexDivideResponse
+	(*ComplexModulusRequest)(nil),            // 18: calculator.ComplexModulusRequest
+	(*ComplexModulusResponse)(nil),           // 19: calculator.ComplexModulusResponse
+	(*ComplexArgumentRequest)(nil),           // 20: calculator.ComplexArgumentRequest
+	(*ComplexArgumentResponse)(nil),          // 21: calculator.ComplexArgumentResponse
+	(*ComplexRootsRequest)(nil),              // 22: calculator.ComplexRootsRequest
+	(*ComplexRootsResponse)(nil),             // 23: calculator.ComplexRootsResponse
+	(*Vector)(nil),                           // 24: calculator.Vector
+	(*Matrix)(nil),                           // 25: calculator.Matrix
+	(*MatrixAddRequest)(nil),                 // 26: calculator.MatrixAddRequest
+	(*MatrixAddResponse)(nil),                // 27: calculator.MatrixAddResponse
+	(*MatrixMultiplyRequest)(nil),            // 28: calculator.MatrixMultiplyRequest
+	(*MatrixMultiplyResponse)(nil),           // 29: calculator.MatrixMultiplyResponse
+	(*MatrixTransposeRequest)(nil),           // 30: calculator.MatrixTransposeRequest
+	(*MatrixTransposeResponse)(nil),          // 31: calculator.MatrixTransposeResponse
+	(*MatrixDeterminantRequest)(nil),         // 32: calculator.MatrixDeterminantRequest
+	(*MatrixDeterminantResponse)(nil),        // 33: calculator.MatrixDeterminantResponse
+	(*MatrixInverseRequest)(nil),             // 34: calculator.MatrixInverseRequest
+	(*MatrixInverseResponse)(nil),            // 35: calculator.MatrixInverseResponse
+	(*SolveLinearSystemRequest)(nil),         // 36: calculator.SolveLinearSystemRequest
+	(*SolveLinearSystemResponse)(nil),        // 37: calculator.SolveLinearSystemResponse
+	(*MatrixDeterminantRowsRequest)(nil),     // 38: calculator.MatrixDeterminantRowsRequest
+	(*MatrixDeterminantRowsResponse)(nil),    // 39: calculator.MatrixDeterminantRowsResponse
+	(*CreateSessionRequest)(nil),             // 40: calculator.CreateSessionRequest
+	(*CreateSessionResponse)(nil),            // 41: calculator.CreateSessionResponse
+	(*CloseSessionRequest)(nil),              // 42: calculator.CloseSessionRequest
+	(*CloseSessionResponse)(nil),             // 43: calculator.CloseSessionResponse
+	(*EvaluateRequest)(nil),                  // 44: calculator.EvaluateRequest
+	(*EvaluateResponse)(nil),                 // 45: calculator.EvaluateResponse
+	(*SessionRequest)(nil),                   // 46: calculator.SessionRequest
+	(*SessionError)(nil),                     // 47: calculator.SessionError
+	(*SessionResponse)(nil),                  // 48: calculator.SessionResponse
+	(*HistoryEntry)(nil),                     // 49: calculator.HistoryEntry
+	(*ListHistoryRequest)(nil),               // 50: calculator.ListHistoryRequest
+	(*ListHistoryResponse)(nil),              // 51: calculator.ListHistoryResponse
+	(*timestamppb.Timestamp)(nil),            // 52: google.protobuf.Timestamp
+}
+var file_calculator_calculatorpb_calculator_proto_depIdxs = []int32{
+	9,  // 0: calculator.SquareRootResponse.error:type_name -> calculator.SquareRootError
+	11, // 1: calculator.SquareRootResponse.complex_root:type_name -> calculator.Complex
+	11, // 2: calculator.ComplexAddRequest.first:type_name -> calculator.Complex
+	11, // 3: calculator.ComplexAddRequest.second:type_name -> calculator.Complex
+	11, // 4: calculator.ComplexAddResponse.result:type_name -> calculator.Complex
+	11, // 5: calculator.ComplexMultiplyRequest.first:type_name -> calculator.Complex
+	11, // 6: calculator.ComplexMultiplyRequest.second:type_name -> calculator.Complex
+	11, // 7: calculator.ComplexMultiplyResponse.result:type_name -> calculator.Complex
+	11, // 8: calculator.ComplexDivideRequest.dividend:type_name -> calculator.Complex
+	11, // 9: calculator.ComplexDivideRequest.divisor:type_name -> calculator.Complex
+	11, // 10: calculator.ComplexDivideResponse.result:type_name -> calculator.Complex
+	11, // 11: calculator.ComplexModulusRequest.number:type_name -> calculator.Complex
+	11, // 12: calculator.ComplexArgumentRequest.number:type_name -> calculator.Complex
+	11, // 13: calculator.ComplexRootsRequest.number:type_name -> calculator.Complex
+	11, // 14: calculator.ComplexRootsResponse.root:type_name -> calculator.Complex
+	24, // 15: calculator.Matrix.rows:type_name -> calculator.Vector
+	25, // 16: calculator.MatrixAddRequest.first:type_name -> calculator.Matrix
+	25, // 17: calculator.MatrixAddRequest.second:type_name -> calculator.Matrix
+	25, // 18: calculator.MatrixAddResponse.result:type_name -> calculator.Matrix
+	25, // 19: calculator.MatrixMultiplyRequest.first:type_name -> calculator.Matrix
+	25, // 20: calculator.MatrixMultiplyRequest.second:type_name -> calculator.Matrix
+	25, // 21: calculator.MatrixMultiplyResponse.result:type_name -> calculator.Matrix
+	25, // 22: calculator.MatrixTransposeRequest.matrix:type_name -> calculator.Matrix
+	25, // 23: calculator.MatrixTransposeResponse.result:type_name -> calculator.Matrix
+	25, // 24: calculator.MatrixDeterminantRequest.matrix:type_name -> calculator.Matrix
+	25, // 25: calculator.MatrixInverseRequest.matrix:type_name -> calculator.Matrix
+	25, // 26: calculator.MatrixInverseResponse.result:type_name -> calculator.Matrix
+	25, // 27: calculator.SolveLinearSystemRequest.coefficients:type_name -> calculator.Matrix
+	24, // 28: calculator.SolveLinearSystemRequest.constants:type_name -> calculator.Vector
+	24, // 29: calculator.SolveLinearSystemResponse.solution:type_name -> calculator.Vector
+	24, // 30: calculator.MatrixDeterminantRowsRequest.row:type_name -> calculator.Vector
+	45, // 31: calculator.SessionResponse.evaluation:type_name -> calculator.EvaluateResponse
+	47, // 32: calculator.SessionResponse.error:type_name -> calculator.SessionError
+	52, // 33: calculator.HistoryEntry.timestamp:type_name -> google.protobuf.Timestamp
+	52, // 34: calculator.ListHistoryRequest.start_time:type_name -> google.protobuf.Timestamp
+	52, // 35: calculator.ListHistoryRequest.end_time:type_name -> google.protobuf.Timestamp
+	49, // 36: calculator.ListHistoryResponse.entries:type_name -> calculator.HistoryEntry
+	0,  // 37: calculator.CalculatorService.Sum:input_type -> calculator.SumRequest
+	2,  // 38: calculator.CalculatorService.PrimeNumberDecomposition:input_type -> calculator.PrimeNumberDecompositionRequest
+	4,  // 39: calculator.CalculatorService.ComputeAverage:input_type -> calculator.ComputeAverageRequest
+	6,  // 40: calculator.CalculatorService.FindMaximum:input_type -> calculator.FindMaximumRequest
+	8,  // 41: calculator.CalculatorService.SquareRoot:input_type -> calculator.SquareRootRequest
+	8,  // 42: calculator.CalculatorService.SquareRootUnary:input_type -> calculator.SquareRootRequest
+	12, // 43: calculator.CalculatorService.ComplexAdd:input_type -> calculator.ComplexAddRequest
+	14, // 44: calculator.CalculatorService.ComplexMultiply:input_type -> calculator.ComplexMultiplyRequest
+	16, // 45: calculator.CalculatorService.ComplexDivide:input_type -> calculator.ComplexDivideRequest
+	18, // 46: calculator.CalculatorService.ComplexModulus:input_type -> calculator.ComplexModulusRequest
+	20, // 47: calculator.CalculatorService.ComplexArgument:input_type -> calculator.ComplexArgumentRequest
+	22, // 48: calculator.CalculatorService.ComplexRoots:input_type -> calculator.ComplexRootsRequest
+	26, // 49: calculator.CalculatorService.MatrixAdd:input_type -> calculator.MatrixAddRequest
+	28, // 50: calculator.CalculatorService.MatrixMultiply:input_type -> calculator.MatrixMultiplyRequest
+	30, // 51: calculator.CalculatorService.MatrixTranspose:input_type -> calculator.MatrixTransposeRequest
+	32, // 52: calculator.CalculatorService.MatrixDeterminant:input_type -> calculator.MatrixDeterminantRequest
+	34, // 53: calculator.CalculatorService.MatrixInverse:input_type -> calculator.MatrixInverseRequest
+	36, // 54: calculator.CalculatorService.SolveLinearSystem:input_type -> calculator.SolveLinearSystemRequest
+	38, // 55: calculator.CalculatorService.MatrixDeterminantRows:input_type -> calculator.MatrixDeterminantRowsRequest
+	40, // 56: calculator.CalculatorService.CreateSession:input_type -> calculator.CreateSessionRequest
+	42, // 57: calculator.CalculatorService.CloseSession:input_type -> calculator.CloseSessionRequest
+	44, // 58: calculator.CalculatorService.Evaluate:input_type -> calculator.EvaluateRequest
+	46, // 59: calculator.CalculatorService.Session:input_type -> calculator.SessionRequest
+	50, // 60: calculator.CalculatorService.ListHistory:input_type -> calculator.ListHistoryRequest
+	1,  // 61: calculator.CalculatorService.Sum:output_type -> calculator.SumResponse
+	3,  // 62: calculator.CalculatorService.PrimeNumberDecomposition:output_type -> calculator.PrimeNumberDecompositionResponse
+	5,  // 63: calculator.CalculatorService.ComputeAverage:output_type -> calculator.ComputeAverageResponse
+	7,  // 64: calculator.CalculatorService.FindMaximum:output_type -> calculator.FindMaximumResponse
+	10, // 65: calculator.CalculatorService.SquareRoot:output_type -> calculator.SquareRootResponse
+	10, // 66: calculator.CalculatorService.SquareRootUnary:output_type -> calculator.SquareRootResponse
+	13, // 67: calculator.CalculatorService.ComplexAdd:output_type -> calculator.ComplexAddResponse
+	15, // 68: calculator.CalculatorService.ComplexMultiply:output_type -> calculator.ComplexMultiplyResponse
+	17, // 69: calculator.CalculatorService.ComplexDivide:output_type -> calculator.ComplexDivideResponse
+	19, // 70: calculator.CalculatorService.ComplexModulus:output_type -> calculator.ComplexModulusResponse
+	21, // 71: calculator.CalculatorService.ComplexArgument:output_type -> calculator.ComplexArgumentResponse
+	23, // 72: calculator.CalculatorService.ComplexRoots:output_type -> calculator.ComplexRootsResponse
+	27, // 73: calculator.CalculatorService.MatrixAdd:output_type -> calculator.MatrixAddResponse
+	29, // 74: calculator.CalculatorService.MatrixMultiply:output_type -> calculator.MatrixMultiplyResponse
+	31, // 75: calculator.CalculatorService.MatrixTranspose:output_type -> calculator.MatrixTransposeResponse
+	33, // 76: calculator.CalculatorService.MatrixDeterminant:output_type -> calculator.MatrixDeterminantResponse
+	35, // 77: calculator.CalculatorService.MatrixInverse:output_type -> calculator.MatrixInverseResponse
+	37, // 78: calculator.CalculatorService.SolveLinearSystem:output_type -> calculator.SolveLinearSystemResponse
+	39, // 79: calculator.CalculatorService.MatrixDeterminantRows:output_type -> calculator.MatrixDeterminantRowsResponse
+	41, // 80: calculator.CalculatorService.CreateSession:output_type -> calculator.CreateSessionResponse
+	43, // 81: calculator.CalculatorService.CloseSession:output_type -> calculator.CloseSessionResponse
+	45, // 82: calculator.CalculatorService.Evaluate:output_type -> calculator.EvaluateResponse
+	48, // 83: calculator.CalculatorService.Session:output_type -> calculator.SessionResponse
+	51, // 84: calculator.CalculatorService.ListHistory:output_type -> calculator.ListHistoryResponse
+	61, // [61:85] is the sub-list for method output_type
+	37, // [37:61] is the sub-list for method input_type
+	37, // [37:37] is the sub-list for extension type_name
+	37, // [37:37] is the sub-list for extension extendee
+	0,  // [0:37] is the sub-list for field type_name
+}
+
+func init() { file_calculator_calculatorpb_calculator_proto_init() }
+func file_calculator_calculatorpb_calculator_proto_init() {
+	if File_calculator_calculatorpb_calculator_proto != nil {
+		return
+	}
+	file_calculator_calculatorpb_calculator_proto_msgTypes[10].OneofWrappers = []any{
+		(*SquareRootResponse_NumberRoot)(nil),
+		(*SquareRootResponse_Error)(nil),
+		(*SquareRootResponse_ComplexRoot)(nil),
+	}
+	file_calculator_calculatorpb_calculator_proto_msgTypes[48].OneofWrappers = []any{
+		(*SessionResponse_Evaluation)(nil),
+		(*SessionResponse_Error)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_calculator_calculatorpb_calculator_proto_rawDesc), len(file_calculator_calculatorpb_calculator_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   52,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_calculator_calculatorpb_calculator_proto_goTypes,
+		DependencyIndexes: file_calculator_calculatorpb_calculator_proto_depIdxs,
+		MessageInfos:      file_calculator_calculatorpb_calculator_proto_msgTypes,
+	}.Build()
+	File_calculator_calculatorpb_calculator_proto = out.File
+	file_calculator_calculatorpb_calculator_proto_goTypes = nil
+	file_calculator_calculatorpb_calculator_proto_depIdxs = nil
 }
@@ -0,0 +1,1016 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: calculator/calculatorpb/calculator.proto
+
+package calculatorpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CalculatorService_Sum_FullMethodName                      = "/calculator.CalculatorService/Sum"
+	CalculatorService_PrimeNumberDecomposition_FullMethodName = "/calculator.CalculatorService/PrimeNumberDecomposition"
+	CalculatorService_ComputeAverage_FullMethodName           = "/calculator.CalculatorService/ComputeAverage"
+	CalculatorService_FindMaximum_FullMethodName              = "/calculator.CalculatorService/FindMaximum"
+	CalculatorService_SquareRoot_FullMethodName               = "/calculator.CalculatorService/SquareRoot"
+	CalculatorService_SquareRootUnary_FullMethodName          = "/calculator.CalculatorService/SquareRootUnary"
+	CalculatorService_ComplexAdd_FullMethodName               = "/calculator.CalculatorService/ComplexAdd"
+	CalculatorService_ComplexMultiply_FullMethodName          = "/calculator.CalculatorService/ComplexMultiply"
+	CalculatorService_ComplexDivide_FullMethodName            = "/calculator.CalculatorService/ComplexDivide"
+	CalculatorService_ComplexModulus_FullMethodName           = "/calculator.CalculatorService/ComplexModulus"
+	CalculatorService_ComplexArgument_FullMethodName          = "/calculator.CalculatorService/ComplexArgument"
+	CalculatorService_ComplexRoots_FullMethodName             = "/calculator.CalculatorService/ComplexRoots"
+	CalculatorService_MatrixAdd_FullMethodName                = "/calculator.CalculatorService/MatrixAdd"
+	CalculatorService_MatrixMultiply_FullMethodName           = "/calculator.CalculatorService/MatrixMultiply"
+	CalculatorService_MatrixTranspose_FullMethodName          = "/calculator.CalculatorService/MatrixTranspose"
+	CalculatorService_MatrixDeterminant_FullMethodName        = "/calculator.CalculatorService/MatrixDeterminant"
+	CalculatorService_MatrixInverse_FullMethodName            = "/calculator.CalculatorService/MatrixInverse"
+	CalculatorService_SolveLinearSystem_FullMethodName        = "/calculator.CalculatorService/SolveLinearSystem"
+	CalculatorService_MatrixDeterminantRows_FullMethodName    = "/calculator.CalculatorService/MatrixDeterminantRows"
+	CalculatorService_CreateSession_FullMethodName            = "/calculator.CalculatorService/CreateSession"
+	CalculatorService_CloseSession_FullMethodName             = "/calculator.CalculatorService/CloseSession"
+	CalculatorService_Evaluate_FullMethodName                 = "/calculator.CalculatorService/Evaluate"
+	CalculatorService_Session_FullMethodName                  = "/calculator.CalculatorService/Session"
+	CalculatorService_ListHistory_FullMethodName              = "/calculator.CalculatorService/ListHistory"
+)
+
+// CalculatorServiceClient is the client API for CalculatorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CalculatorServiceClient interface {
+	Sum(ctx context.Context, in *SumRequest, opts ...grpc.CallOption) (*SumResponse, error)
+	PrimeNumberDecomposition(ctx context.Context, in *PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PrimeNumberDecompositionResponse], error)
+	ComputeAverage(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ComputeAverageRequest, ComputeAverageResponse], error)
+	FindMaximum(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[FindMaximumRequest, FindMaximumResponse], error)
+	// error handling
+	// each number is answered in order; a negative number is answered with
+	// an error of code INVALID_ARGUMENT inside the response, the stream goes on
+	SquareRoot(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SquareRootRequest, SquareRootResponse], error)
+	// unary variant of SquareRoot
+	// this RPC will throw an exception if the sent number is negative
+	// the error being sent is of type INVALID_ARGUMENT
+	SquareRootUnary(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error)
+	// complex arithmetic
+	// ComplexDivide throws INVALID_ARGUMENT when dividing by zero
+	// ComplexRoots streams the n roots starting with the principal one,
+	// it throws INVALID_ARGUMENT if the degree is not between 1 and 1000
+	ComplexAdd(ctx context.Context, in *ComplexAddRequest, opts ...grpc.CallOption) (*ComplexAddResponse, error)
+	ComplexMultiply(ctx context.Context, in *ComplexMultiplyRequest, opts ...grpc.CallOption) (*ComplexMultiplyResponse, error)
+	ComplexDivide(ctx context.Context, in *ComplexDivideRequest, opts ...grpc.CallOption) (*ComplexDivideResponse, error)
+	ComplexModulus(ctx context.Context, in *ComplexModulusRequest, opts ...grpc.CallOption) (*ComplexModulusResponse, error)
+	ComplexArgument(ctx context.Context, in *ComplexArgumentRequest, opts ...grpc.CallOption) (*ComplexArgumentResponse, error)
+	ComplexRoots(ctx context.Context, in *ComplexRootsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ComplexRootsResponse], error)
+	// matrix and vector operations
+	// all of them throw INVALID_ARGUMENT when the dimensions do not match,
+	// MatrixInverse and SolveLinearSystem also throw it for singular matrices
+	MatrixAdd(ctx context.Context, in *MatrixAddRequest, opts ...grpc.CallOption) (*MatrixAddResponse, error)
+	MatrixMultiply(ctx context.Context, in *MatrixMultiplyRequest, opts ...grpc.CallOption) (*MatrixMultiplyResponse, error)
+	MatrixTranspose(ctx context.Context, in *MatrixTransposeRequest, opts ...grpc.CallOption) (*MatrixTransposeResponse, error)
+	MatrixDeterminant(ctx context.Context, in *MatrixDeterminantRequest, opts ...grpc.CallOption) (*MatrixDeterminantResponse, error)
+	MatrixInverse(ctx context.Context, in *MatrixInverseRequest, opts ...grpc.CallOption) (*MatrixInverseResponse, error)
+	SolveLinearSystem(ctx context.Context, in *SolveLinearSystemRequest, opts ...grpc.CallOption) (*SolveLinearSystemResponse, error)
+	// streaming variant of MatrixDeterminant for large matrices, sent row by row
+	MatrixDeterminantRows(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[MatrixDeterminantRowsRequest, MatrixDeterminantRowsResponse], error)
+	// sessions
+	// a session keeps named variables and previous results between calls,
+	// calls referencing an unknown or expired session throw NOT_FOUND
+	CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error)
+	CloseSession(ctx context.Context, in *CloseSessionRequest, opts ...grpc.CallOption) (*CloseSessionResponse, error)
+	// Evaluate throws INVALID_ARGUMENT for malformed expressions and NOT_FOUND for unknown references
+	Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error)
+	// remote REPL, each expression is answered in order with its result or an error
+	Session(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SessionRequest, SessionResponse], error)
+	// history of the calls made to this service by the caller, ListHistory calls are not recorded
+	// a caller only lists its own calls, identified as the client of the entries, but on the
+	// admin listener of the server, where ListHistory alone is served with the calls of every client
+	// it throws INVALID_ARGUMENT for a malformed page token
+	ListHistory(ctx context.Context, in *ListHistoryRequest, opts ...grpc.CallOption) (*ListHistoryResponse, error)
+}
+
+type calculatorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCalculatorServiceClient(cc grpc.ClientConnInterface) CalculatorServiceClient {
+	return &calculatorServiceClient{cc}
+}
+
+func (c *calculatorServiceClient) Sum(ctx context.Context, in *SumRequest, opts ...grpc.CallOption) (*SumResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SumResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_Sum_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) PrimeNumberDecomposition(ctx context.Context, in *PrimeNumberDecompositionRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PrimeNumberDecompositionResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[0], CalculatorService_PrimeNumberDecomposition_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[PrimeNumberDecompositionRequest, PrimeNumberDecompositionResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_PrimeNumberDecompositionClient = grpc.ServerStreamingClient[PrimeNumberDecompositionResponse]
+
+func (c *calculatorServiceClient) ComputeAverage(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[ComputeAverageRequest, ComputeAverageResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[1], CalculatorService_ComputeAverage_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ComputeAverageRequest, ComputeAverageResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_ComputeAverageClient = grpc.ClientStreamingClient[ComputeAverageRequest, ComputeAverageResponse]
+
+func (c *calculatorServiceClient) FindMaximum(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[FindMaximumRequest, FindMaximumResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[2], CalculatorService_FindMaximum_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[FindMaximumRequest, FindMaximumResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_FindMaximumClient = grpc.BidiStreamingClient[FindMaximumRequest, FindMaximumResponse]
+
+func (c *calculatorServiceClient) SquareRoot(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SquareRootRequest, SquareRootResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[3], CalculatorService_SquareRoot_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SquareRootRequest, SquareRootResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_SquareRootClient = grpc.BidiStreamingClient[SquareRootRequest, SquareRootResponse]
+
+func (c *calculatorServiceClient) SquareRootUnary(ctx context.Context, in *SquareRootRequest, opts ...grpc.CallOption) (*SquareRootResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SquareRootResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_SquareRootUnary_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ComplexAdd(ctx context.Context, in *ComplexAddRequest, opts ...grpc.CallOption) (*ComplexAddResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComplexAddResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_ComplexAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ComplexMultiply(ctx context.Context, in *ComplexMultiplyRequest, opts ...grpc.CallOption) (*ComplexMultiplyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComplexMultiplyResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_ComplexMultiply_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ComplexDivide(ctx context.Context, in *ComplexDivideRequest, opts ...grpc.CallOption) (*ComplexDivideResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComplexDivideResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_ComplexDivide_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ComplexModulus(ctx context.Context, in *ComplexModulusRequest, opts ...grpc.CallOption) (*ComplexModulusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComplexModulusResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_ComplexModulus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ComplexArgument(ctx context.Context, in *ComplexArgumentRequest, opts ...grpc.CallOption) (*ComplexArgumentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ComplexArgumentResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_ComplexArgument_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) ComplexRoots(ctx context.Context, in *ComplexRootsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ComplexRootsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[4], CalculatorService_ComplexRoots_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ComplexRootsRequest, ComplexRootsResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_ComplexRootsClient = grpc.ServerStreamingClient[ComplexRootsResponse]
+
+func (c *calculatorServiceClient) MatrixAdd(ctx context.Context, in *MatrixAddRequest, opts ...grpc.CallOption) (*MatrixAddResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MatrixAddResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_MatrixAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) MatrixMultiply(ctx context.Context, in *MatrixMultiplyRequest, opts ...grpc.CallOption) (*MatrixMultiplyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MatrixMultiplyResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_MatrixMultiply_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) MatrixTranspose(ctx context.Context, in *MatrixTransposeRequest, opts ...grpc.CallOption) (*MatrixTransposeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MatrixTransposeResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_MatrixTranspose_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) MatrixDeterminant(ctx context.Context, in *MatrixDeterminantRequest, opts ...grpc.CallOption) (*MatrixDeterminantResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MatrixDeterminantResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_MatrixDeterminant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) MatrixInverse(ctx context.Context, in *MatrixInverseRequest, opts ...grpc.CallOption) (*MatrixInverseResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MatrixInverseResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_MatrixInverse_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) SolveLinearSystem(ctx context.Context, in *SolveLinearSystemRequest, opts ...grpc.CallOption) (*SolveLinearSystemResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SolveLinearSystemResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_SolveLinearSystem_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) MatrixDeterminantRows(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[MatrixDeterminantRowsRequest, MatrixDeterminantRowsResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[5], CalculatorService_MatrixDeterminantRows_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[MatrixDeterminantRowsRequest, MatrixDeterminantRowsResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_MatrixDeterminantRowsClient = grpc.ClientStreamingClient[MatrixDeterminantRowsRequest, MatrixDeterminantRowsResponse]
+
+func (c *calculatorServiceClient) CreateSession(ctx context.Context, in *CreateSessionRequest, opts ...grpc.CallOption) (*CreateSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateSessionResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_CreateSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) CloseSession(ctx context.Context, in *CloseSessionRequest, opts ...grpc.CallOption) (*CloseSessionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CloseSessionResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_CloseSession_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) Evaluate(ctx context.Context, in *EvaluateRequest, opts ...grpc.CallOption) (*EvaluateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EvaluateResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_Evaluate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *calculatorServiceClient) Session(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[SessionRequest, SessionResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CalculatorService_ServiceDesc.Streams[6], CalculatorService_Session_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SessionRequest, SessionResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_SessionClient = grpc.BidiStreamingClient[SessionRequest, SessionResponse]
+
+func (c *calculatorServiceClient) ListHistory(ctx context.Context, in *ListHistoryRequest, opts ...grpc.CallOption) (*ListHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListHistoryResponse)
+	err := c.cc.Invoke(ctx, CalculatorService_ListHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CalculatorServiceServer is the server API for CalculatorService service.
+// All implementations must embed UnimplementedCalculatorServiceServer
+// for forward compatibility.
+type CalculatorServiceServer interface {
+	Sum(context.Context, *SumRequest) (*SumResponse, error)
+	PrimeNumberDecomposition(*PrimeNumberDecompositionRequest, grpc.ServerStreamingServer[PrimeNumberDecompositionResponse]) error
+	ComputeAverage(grpc.ClientStreamingServer[ComputeAverageRequest, ComputeAverageResponse]) error
+	FindMaximum(grpc.BidiStreamingServer[FindMaximumRequest, FindMaximumResponse]) error
+	// error handling
+	// each number is answered in order; a negative number is answered with
+	// an error of code INVALID_ARGUMENT inside the response, the stream goes on
+	SquareRoot(grpc.BidiStreamingServer[SquareRootRequest, SquareRootResponse]) error
+	// unary variant of SquareRoot
+	// this RPC will throw an exception if the sent number is negative
+	// the error being sent is of type INVALID_ARGUMENT
+	SquareRootUnary(context.Context, *SquareRootRequest) (*SquareRootResponse, error)
+	// complex arithmetic
+	// ComplexDivide throws INVALID_ARGUMENT when dividing by zero
+	// ComplexRoots streams the n roots starting with the principal one,
+	// it throws INVALID_ARGUMENT if the degree is not between 1 and 1000
+	ComplexAdd(context.Context, *ComplexAddRequest) (*ComplexAddResponse, error)
+	ComplexMultiply(context.Context, *ComplexMultiplyRequest) (*ComplexMultiplyResponse, error)
+	ComplexDivide(context.Context, *ComplexDivideRequest) (*ComplexDivideResponse, error)
+	ComplexModulus(context.Context, *ComplexModulusRequest) (*ComplexModulusResponse, error)
+	ComplexArgument(context.Context, *ComplexArgumentRequest) (*ComplexArgumentResponse, error)
+	ComplexRoots(*ComplexRootsRequest, grpc.ServerStreamingServer[ComplexRootsResponse]) error
+	// matrix and vector operations
+	// all of them throw INVALID_ARGUMENT when the dimensions do not match,
+	// MatrixInverse and SolveLinearSystem also throw it for singular matrices
+	MatrixAdd(context.Context, *MatrixAddRequest) (*MatrixAddResponse, error)
+	MatrixMultiply(context.Context, *MatrixMultiplyRequest) (*MatrixMultiplyResponse, error)
+	MatrixTranspose(context.Context, *MatrixTransposeRequest) (*MatrixTransposeResponse, error)
+	MatrixDeterminant(context.Context, *MatrixDeterminantRequest) (*MatrixDeterminantResponse, error)
+	MatrixInverse(context.Context, *MatrixInverseRequest) (*MatrixInverseResponse, error)
+	SolveLinearSystem(context.Context, *SolveLinearSystemRequest) (*SolveLinearSystemResponse, error)
+	// streaming variant of MatrixDeterminant for large matrices, sent row by row
+	MatrixDeterminantRows(grpc.ClientStreamingServer[MatrixDeterminantRowsRequest, MatrixDeterminantRowsResponse]) error
+	// sessions
+	// a session keeps named variables and previous results between calls,
+	// calls referencing an unknown or expired session throw NOT_FOUND
+	CreateSession(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error)
+	CloseSession(context.Context, *CloseSessionRequest) (*CloseSessionResponse, error)
+	// Evaluate throws INVALID_ARGUMENT for malformed expressions and NOT_FOUND for unknown references
+	Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error)
+	// remote REPL, each expression is answered in order with its result or an error
+	Session(grpc.BidiStreamingServer[SessionRequest, SessionResponse]) error
+	// history of the calls made to this service by the caller, ListHistory calls are not recorded
+	// a caller only lists its own calls, identified as the client of the entries, but on the
+	// admin listener of the server, where ListHistory alone is served with the calls of every client
+	// it throws INVALID_ARGUMENT for a malformed page token
+	ListHistory(context.Context, *ListHistoryRequest) (*ListHistoryResponse, error)
+	mustEmbedUnimplementedCalculatorServiceServer()
+}
+
+// UnimplementedCalculatorServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCalculatorServiceServer struct{}
+
+func (UnimplementedCalculatorServiceServer) Sum(context.Context, *SumRequest) (*SumResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sum not implemented")
+}
+func (UnimplementedCalculatorServiceServer) PrimeNumberDecomposition(*PrimeNumberDecompositionRequest, grpc.ServerStreamingServer[PrimeNumberDecompositionResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method PrimeNumberDecomposition not implemented")
+}
+func (UnimplementedCalculatorServiceServer) ComputeAverage(grpc.ClientStreamingServer[ComputeAverageRequest, ComputeAverageResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ComputeAverage not implemented")
+}
+func (UnimplementedCalculatorServiceServer) FindMaximum(grpc.BidiStreamingServer[FindMaximumRequest, FindMaximumResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method FindMaximum not implemented")
+}
+func (UnimplementedCalculatorServiceServer) SquareRoot(grpc.BidiStreamingServer[SquareRootRequest, SquareRootResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method SquareRoot not implemented")
+}
+func (UnimplementedCalculatorServiceServer) SquareRootUnary(context.Context, *SquareRootRequest) (*SquareRootResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SquareRootUnary not implemented")
+}
+func (UnimplementedCalculatorServiceServer) ComplexAdd(context.Context, *ComplexAddRequest) (*ComplexAddResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ComplexAdd not implemented")
+}
+func (UnimplementedCalculatorServiceServer) ComplexMultiply(context.Context, *ComplexMultiplyRequest) (*ComplexMultiplyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ComplexMultiply not implemented")
+}
+func (UnimplementedCalculatorServiceServer) ComplexDivide(context.Context, *ComplexDivideRequest) (*ComplexDivideResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ComplexDivide not implemented")
+}
+func (UnimplementedCalculatorServiceServer) ComplexModulus(context.Context, *ComplexModulusRequest) (*ComplexModulusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ComplexModulus not implemented")
+}
+func (UnimplementedCalculatorServiceServer) ComplexArgument(context.Context, *ComplexArgumentRequest) (*ComplexArgumentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ComplexArgument not implemented")
+}
+func (UnimplementedCalculatorServiceServer) ComplexRoots(*ComplexRootsRequest, grpc.ServerStreamingServer[ComplexRootsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ComplexRoots not implemented")
+}
+func (UnimplementedCalculatorServiceServer) MatrixAdd(context.Context, *MatrixAddRequest) (*MatrixAddResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatrixAdd not implemented")
+}
+func (UnimplementedCalculatorServiceServer) MatrixMultiply(context.Context, *MatrixMultiplyRequest) (*MatrixMultiplyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatrixMultiply not implemented")
+}
+func (UnimplementedCalculatorServiceServer) MatrixTranspose(context.Context, *MatrixTransposeRequest) (*MatrixTransposeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatrixTranspose not implemented")
+}
+func (UnimplementedCalculatorServiceServer) MatrixDeterminant(context.Context, *MatrixDeterminantRequest) (*MatrixDeterminantResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatrixDeterminant not implemented")
+}
+func (UnimplementedCalculatorServiceServer) MatrixInverse(context.Context, *MatrixInverseRequest) (*MatrixInverseResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MatrixInverse not implemented")
+}
+func (UnimplementedCalculatorServiceServer) SolveLinearSystem(context.Context, *SolveLinearSystemRequest) (*SolveLinearSystemResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SolveLinearSystem not implemented")
+}
+func (UnimplementedCalculatorServiceServer) MatrixDeterminantRows(grpc.ClientStreamingServer[MatrixDeterminantRowsRequest, MatrixDeterminantRowsResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method MatrixDeterminantRows not implemented")
+}
+func (UnimplementedCalculatorServiceServer) CreateSession(context.Context, *CreateSessionRequest) (*CreateSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
+}
+func (UnimplementedCalculatorServiceServer) CloseSession(context.Context, *CloseSessionRequest) (*CloseSessionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CloseSession not implemented")
+}
+func (UnimplementedCalculatorServiceServer) Evaluate(context.Context, *EvaluateRequest) (*EvaluateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Evaluate not implemented")
+}
+func (UnimplementedCalculatorServiceServer) Session(grpc.BidiStreamingServer[SessionRequest, SessionResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Session not implemented")
+}
+func (UnimplementedCalculatorServiceServer) ListHistory(context.Context, *ListHistoryRequest) (*ListHistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHistory not implemented")
+}
+func (UnimplementedCalculatorServiceServer) mustEmbedUnimplementedCalculatorServiceServer() {}
+func (UnimplementedCalculatorServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeCalculatorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CalculatorServiceServer will
+// result in compilation errors.
+type UnsafeCalculatorServiceServer interface {
+	mustEmbedUnimplementedCalculatorServiceServer()
+}
+
+func RegisterCalculatorServiceServer(s grpc.ServiceRegistrar, srv CalculatorServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCalculatorServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CalculatorService_ServiceDesc, srv)
+}
+
+func _CalculatorService_Sum_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SumRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).Sum(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_Sum_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).Sum(ctx, req.(*SumRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_PrimeNumberDecomposition_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(PrimeNumberDecompositionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalculatorServiceServer).PrimeNumberDecomposition(m, &grpc.GenericServerStream[PrimeNumberDecompositionRequest, PrimeNumberDecompositionResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_PrimeNumberDecompositionServer = grpc.ServerStreamingServer[PrimeNumberDecompositionResponse]
+
+func _CalculatorService_ComputeAverage_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).ComputeAverage(&grpc.GenericServerStream[ComputeAverageRequest, ComputeAverageResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_ComputeAverageServer = grpc.ClientStreamingServer[ComputeAverageRequest, ComputeAverageResponse]
+
+func _CalculatorService_FindMaximum_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).FindMaximum(&grpc.GenericServerStream[FindMaximumRequest, FindMaximumResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_FindMaximumServer = grpc.BidiStreamingServer[FindMaximumRequest, FindMaximumResponse]
+
+func _CalculatorService_SquareRoot_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).SquareRoot(&grpc.GenericServerStream[SquareRootRequest, SquareRootResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_SquareRootServer = grpc.BidiStreamingServer[SquareRootRequest, SquareRootResponse]
+
+func _CalculatorService_SquareRootUnary_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SquareRootRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).SquareRootUnary(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_SquareRootUnary_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).SquareRootUnary(ctx, req.(*SquareRootRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_ComplexAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComplexAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ComplexAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_ComplexAdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ComplexAdd(ctx, req.(*ComplexAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_ComplexMultiply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComplexMultiplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ComplexMultiply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_ComplexMultiply_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ComplexMultiply(ctx, req.(*ComplexMultiplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_ComplexDivide_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComplexDivideRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ComplexDivide(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_ComplexDivide_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ComplexDivide(ctx, req.(*ComplexDivideRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_ComplexModulus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComplexModulusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ComplexModulus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_ComplexModulus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ComplexModulus(ctx, req.(*ComplexModulusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_ComplexArgument_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ComplexArgumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ComplexArgument(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_ComplexArgument_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ComplexArgument(ctx, req.(*ComplexArgumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_ComplexRoots_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ComplexRootsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CalculatorServiceServer).ComplexRoots(m, &grpc.GenericServerStream[ComplexRootsRequest, ComplexRootsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_ComplexRootsServer = grpc.ServerStreamingServer[ComplexRootsResponse]
+
+func _CalculatorService_MatrixAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatrixAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).MatrixAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_MatrixAdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).MatrixAdd(ctx, req.(*MatrixAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_MatrixMultiply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatrixMultiplyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).MatrixMultiply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_MatrixMultiply_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).MatrixMultiply(ctx, req.(*MatrixMultiplyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_MatrixTranspose_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatrixTransposeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).MatrixTranspose(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_MatrixTranspose_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).MatrixTranspose(ctx, req.(*MatrixTransposeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_MatrixDeterminant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatrixDeterminantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).MatrixDeterminant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_MatrixDeterminant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).MatrixDeterminant(ctx, req.(*MatrixDeterminantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_MatrixInverse_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MatrixInverseRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).MatrixInverse(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_MatrixInverse_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).MatrixInverse(ctx, req.(*MatrixInverseRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_SolveLinearSystem_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SolveLinearSystemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).SolveLinearSystem(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_SolveLinearSystem_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).SolveLinearSystem(ctx, req.(*SolveLinearSystemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_MatrixDeterminantRows_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).MatrixDeterminantRows(&grpc.GenericServerStream[MatrixDeterminantRowsRequest, MatrixDeterminantRowsResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_MatrixDeterminantRowsServer = grpc.ClientStreamingServer[MatrixDeterminantRowsRequest, MatrixDeterminantRowsResponse]
+
+func _CalculatorService_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).CreateSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_CreateSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).CreateSession(ctx, req.(*CreateSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_CloseSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CloseSessionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).CloseSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_CloseSession_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).CloseSession(ctx, req.(*CloseSessionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_Evaluate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EvaluateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).Evaluate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_Evaluate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).Evaluate(ctx, req.(*EvaluateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CalculatorService_Session_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(CalculatorServiceServer).Session(&grpc.GenericServerStream[SessionRequest, SessionResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CalculatorService_SessionServer = grpc.BidiStreamingServer[SessionRequest, SessionResponse]
+
+func _CalculatorService_ListHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CalculatorServiceServer).ListHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CalculatorService_ListHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CalculatorServiceServer).ListHistory(ctx, req.(*ListHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CalculatorService_ServiceDesc is the grpc.ServiceDesc for CalculatorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CalculatorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "calculator.CalculatorService",
+	HandlerType: (*CalculatorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Sum",
+			Handler:    _CalculatorService_Sum_Handler,
+		},
+		{
+			MethodName: "SquareRootUnary",
+			Handler:    _CalculatorService_SquareRootUnary_Handler,
+		},
+		{
+			MethodName: "ComplexAdd",
+			Handler:    _CalculatorService_ComplexAdd_Handler,
+		},
+		{
+			MethodName: "ComplexMultiply",
+			Handler:    _CalculatorService_ComplexMultiply_Handler,
+		},
+		{
+			MethodName: "ComplexDivide",
+			Handler:    _CalculatorService_ComplexDivide_Handler,
+		},
+		{
+			MethodName: "ComplexModulus",
+			Handler:    _CalculatorService_ComplexModulus_Handler,
+		},
+		{
+			MethodName: "ComplexArgument",
+			Handler:    _CalculatorService_ComplexArgument_Handler,
+		},
+		{
+			MethodName: "MatrixAdd",
+			Handler:    _CalculatorService_MatrixAdd_Handler,
+		},
+		{
+			MethodName: "MatrixMultiply",
+			Handler:    _CalculatorService_MatrixMultiply_Handler,
+		},
+		{
+			MethodName: "MatrixTranspose",
+			Handler:    _CalculatorService_MatrixTranspose_Handler,
+		},
+		{
+			MethodName: "MatrixDeterminant",
+			Handler:    _CalculatorService_MatrixDeterminant_Handler,
+		},
+		{
+			MethodName: "MatrixInverse",
+			Handler:    _CalculatorService_MatrixInverse_Handler,
+		},
+		{
+			MethodName: "SolveLinearSystem",
+			Handler:    _CalculatorService_SolveLinearSystem_Handler,
+		},
+		{
+			MethodName: "CreateSession",
+			Handler:    _CalculatorService_CreateSession_Handler,
+		},
+		{
+			MethodName: "CloseSession",
+			Handler:    _CalculatorService_CloseSession_Handler,
+		},
+		{
+			MethodName: "Evaluate",
+			Handler:    _CalculatorService_Evaluate_Handler,
+		},
+		{
+			MethodName: "ListHistory",
+			Handler:    _CalculatorService_ListHistory_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "PrimeNumberDecomposition",
+			Handler:       _CalculatorService_PrimeNumberDecomposition_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ComputeAverage",
+			Handler:       _CalculatorService_ComputeAverage_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "FindMaximum",
+			Handler:       _CalculatorService_FindMaximum_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SquareRoot",
+			Handler:       _CalculatorService_SquareRoot_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "ComplexRoots",
+			Handler:       _CalculatorService_ComplexRoots_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "MatrixDeterminantRows",
+			Handler:       _CalculatorService_MatrixDeterminantRows_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Session",
+			Handler:       _CalculatorService_Session_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "calculator/calculatorpb/calculator.proto",
+}
@@ -0,0 +1,99 @@
+package calculatorservice
+
+import (
+  "context"
+  "strings"
+  "sync/atomic"
+
+  "github.com/_dev/grpc-go-example/cache"
+
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/protobuf/proto"
+)
+
+// resultCache keeps the results of the deterministic RPCs, a nil resultCache caches nothing.
+type resultCache struct {
+  cache    *cache.Cache
+  bypasses int64
+  disabled int32 // set while the cache feature is toggled off.
+}
+
+// CacheStats are published with the metrics.
+type CacheStats struct {
+  cache.Stats
+  Bypasses int64
+  Entries  int
+}
+
+func newResultCache(c *cache.Cache) *resultCache {
+  return &resultCache{cache: c}
+}
+
+// setEnabled toggles the cache, the entries kept while disabled are used again once enabled.
+func (rc *resultCache) setEnabled(enabled bool) {
+  if rc == nil {
+    return
+  }
+  var disabled int32
+  if !enabled {
+    disabled = 1
+  }
+  atomic.StoreInt32(&rc.disabled, disabled)
+}
+
+// get looks up the result of method for req, unless the caller asked for a fresh one
+// with the "cache-control: no-cache" metadata.
+func (rc *resultCache) get(ctx context.Context, method string, req proto.Message) (interface{}, bool) {
+  if rc == nil || atomic.LoadInt32(&rc.disabled) == 1 {
+    return nil, false
+  }
+  if noCache(ctx) {
+    atomic.AddInt64(&rc.bypasses, 1)
+    return nil, false
+  }
+  key, err := cacheKey(method, req)
+  if err != nil {
+    return nil, false
+  }
+  return rc.cache.Get(key)
+}
+
+func (rc *resultCache) add(method string, req proto.Message, value interface{}) {
+  if rc == nil || atomic.LoadInt32(&rc.disabled) == 1 {
+    return
+  }
+  key, err := cacheKey(method, req)
+  if err != nil {
+    return
+  }
+  rc.cache.Add(key, value)
+}
+
+func (rc *resultCache) stats() CacheStats {
+  return CacheStats{
+    Stats:    rc.cache.Stats(),
+    Bypasses: atomic.LoadInt64(&rc.bypasses),
+    Entries:  rc.cache.Len(),
+  }
+}
+
+// cacheKey canonicalizes the request with a deterministic marshaling.
+func cacheKey(method string, req proto.Message) (string, error) {
+  b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+  if err != nil {
+    return "", err
+  }
+  return method + "\x00" + string(b), nil
+}
+
+func noCache(ctx context.Context) bool {
+  md, _ := metadata.FromIncomingContext(ctx)
+  for _, value := range md.Get("cache-control") {
+    for _, directive := range strings.Split(value, ",") {
+      if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+        return true
+      }
+    }
+  }
+  return false
+}
@@ -0,0 +1,92 @@
+package calculatorservice
+
+import (
+  "context"
+  "math"
+  "math/cmplx"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/logging"
+
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// maxRootDegree limits the number of roots streamed by ComplexRoots.
+const maxRootDegree = 1000
+
+func (*Server) ComplexAdd(ctx context.Context, req *calculatorpb.ComplexAddRequest) (*calculatorpb.ComplexAddResponse, error) {
+  logging.Infof("Received ComplexAdd RPC: %v\n", req)
+  result := fromComplex(req.GetFirst()) + fromComplex(req.GetSecond())
+  return &calculatorpb.ComplexAddResponse{
+    Result: toComplex(result),
+  }, nil
+}
+
+func (*Server) ComplexMultiply(ctx context.Context, req *calculatorpb.ComplexMultiplyRequest) (*calculatorpb.ComplexMultiplyResponse, error) {
+  logging.Infof("Received ComplexMultiply RPC: %v\n", req)
+  result := fromComplex(req.GetFirst()) * fromComplex(req.GetSecond())
+  return &calculatorpb.ComplexMultiplyResponse{
+    Result: toComplex(result),
+  }, nil
+}
+
+func (*Server) ComplexDivide(ctx context.Context, req *calculatorpb.ComplexDivideRequest) (*calculatorpb.ComplexDivideResponse, error) {
+  logging.Infof("Received ComplexDivide RPC: %v\n", req)
+  divisor := fromComplex(req.GetDivisor())
+  if divisor == 0 {
+    return nil, status.Error(codes.InvalidArgument, "Received a zero divisor!")
+  }
+  result := fromComplex(req.GetDividend()) / divisor
+  return &calculatorpb.ComplexDivideResponse{
+    Result: toComplex(result),
+  }, nil
+}
+
+func (*Server) ComplexModulus(ctx context.Context, req *calculatorpb.ComplexModulusRequest) (*calculatorpb.ComplexModulusResponse, error) {
+  logging.Infof("Received ComplexModulus RPC: %v\n", req)
+  return &calculatorpb.ComplexModulusResponse{
+    Modulus: cmplx.Abs(fromComplex(req.GetNumber())),
+  }, nil
+}
+
+func (*Server) ComplexArgument(ctx context.Context, req *calculatorpb.ComplexArgumentRequest) (*calculatorpb.ComplexArgumentResponse, error) {
+  logging.Infof("Received ComplexArgument RPC: %v\n", req)
+  return &calculatorpb.ComplexArgumentResponse{
+    Argument: cmplx.Phase(fromComplex(req.GetNumber())),
+  }, nil
+}
+
+func (*Server) ComplexRoots(req *calculatorpb.ComplexRootsRequest, stream calculatorpb.CalculatorService_ComplexRootsServer) error {
+  logging.Infof("Received ComplexRoots RPC: %v\n", req)
+  degree := req.GetDegree()
+  if degree < 1 || degree > maxRootDegree {
+    return status.Errorf(codes.InvalidArgument, "Received an invalid degree: %v!", degree)
+  }
+
+  // The nth roots of r*e^(i*phi) are r^(1/n)*e^(i*(phi+2*k*Pi)/n), for k from 0 to n-1.
+  number := fromComplex(req.GetNumber())
+  modulus := math.Pow(cmplx.Abs(number), 1/float64(degree))
+  phase := cmplx.Phase(number)
+  for k := int32(0); k < degree; k++ {
+    angle := (phase + 2*math.Pi*float64(k)) / float64(degree)
+    err := stream.Send(&calculatorpb.ComplexRootsResponse{
+      Root: toComplex(cmplx.Rect(modulus, angle)),
+    })
+    if err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+func fromComplex(c *calculatorpb.Complex) complex128 {
+  return complex(c.GetReal(), c.GetImaginary())
+}
+
+func toComplex(c complex128) *calculatorpb.Complex {
+  return &calculatorpb.Complex{
+    Real:      real(c),
+    Imaginary: imag(c),
+  }
+}
@@ -0,0 +1,166 @@
+package calculatorservice
+
+import (
+  "errors"
+  "fmt"
+  "strings"
+  "time"
+
+  "github.com/_dev/grpc-go-example/admission"
+  "github.com/_dev/grpc-go-example/compression"
+  "github.com/_dev/grpc-go-example/logging"
+  "github.com/_dev/grpc-go-example/ratelimit"
+)
+
+// Config is the configuration of the calculator server, loaded by package config
+// from calculator.yaml or calculator.toml, the CALCULATOR_* environment variables and the flags.
+type Config struct {
+  LogLevel          string            `config:"log_level" reload:"live" usage:"least severity logged: debug, info or error"`
+  Addr              string            `config:"addr" usage:"address the server listens on"`
+  MetricsAddr       string            `config:"metrics_addr" usage:"address serving the metrics at /debug/vars, empty to disable"`
+  AdminAddr         string            `config:"admin_addr" usage:"address of the AdminService, reloading the configuration without authentication: keep it on the loopback or a private network, empty to disable"`
+  History           HistoryConfig     `config:"history"`
+  Cache             CacheConfig       `config:"cache"`
+  SessionTTL        time.Duration     `config:"session_ttl" usage:"how long a session lives without being used"`
+  MaxSessions       int               `config:"max_sessions" usage:"sessions kept at once, the others are refused until some close or expire; 0 for no limit"`
+  RateLimit         string            `config:"rate_limit" reload:"live" usage:"rate limits as method=rate:burst separated by commas, * for the other methods"`
+  RateLimitKey      string            `config:"rate_limit_key" usage:"how clients are told apart by the rate limits: default, ip, api-key or principal"`
+  StreamLimits      string            `config:"stream_limits" usage:"concurrent stream limits as method=streams separated by commas, * for the other methods"`
+  MaxClientStreams  int               `config:"max_client_streams" usage:"concurrent streams allowed to each client, 0 for no limit"`
+  Shed              ShedConfig        `config:"shed"`
+  IdempotencyWindow time.Duration     `config:"idempotency_window" usage:"how long the response of a call with an idempotency key is replayed, 0 to disable"`
+  Deadlines         DeadlinesConfig   `config:"deadlines"`
+  Compression       CompressionConfig `config:"compression"`
+  DisabledMethods   string            `config:"disabled_methods" reload:"live" usage:"full method names answered with Unimplemented, separated by commas"`
+  Features          Features          `config:"features" reload:"live"`
+}
+
+// Features are toggled while the server runs.
+type Features struct {
+  Cache          bool `config:"cache" usage:"answer the deterministic RPCs from the cache"`
+  ComplexResults bool `config:"complex_results" usage:"answer the square roots of negative numbers with complex roots when asked to"`
+}
+
+type HistoryConfig struct {
+  DB         string        `config:"db" usage:"BoltDB file where the calls are recorded, empty to disable the history"`
+  MaxAge     time.Duration `config:"max_age" usage:"how long history entries are kept, 0 to keep them forever"`
+  MaxEntries int           `config:"max_entries" usage:"how many history entries are kept, 0 for no limit"`
+}
+
+type CacheConfig struct {
+  Size int           `config:"size" usage:"how many results of the deterministic RPCs are cached, 0 to disable the cache"`
+  TTL  time.Duration `config:"ttl" usage:"how long results are cached, 0 to keep them until evicted"`
+}
+
+type ShedConfig struct {
+  TargetLatency time.Duration `config:"target_latency" usage:"answer latency over which new streams are shed, 0 to disable the load shedding"`
+  MaxStreams    int           `config:"max_streams" usage:"concurrent streams admitted by the load shedding when the latency is fine"`
+}
+
+// DeadlinesConfig bounds the deadlines of the calls received, see package deadline.
+type DeadlinesConfig struct {
+  Default       time.Duration `config:"default" usage:"deadline of the unary calls arriving without one, at most the max"`
+  Max           time.Duration `config:"max" usage:"longest deadline of the unary calls, 0 for no limit"`
+  StreamDefault time.Duration `config:"stream_default" usage:"deadline of the streams arriving without one, at most the stream max"`
+  StreamMax     time.Duration `config:"stream_max" usage:"longest deadline of the streams, 0 for no limit"`
+}
+
+// CompressionConfig picks the compressor of the responses, see package compression.
+type CompressionConfig struct {
+  Methods string `config:"methods" usage:"compressors of the responses as method=compressor separated by commas, * for the other methods: gzip, zstd or identity; the one of the call when none applies"`
+  MinSize int    `config:"min_size" usage:"size in bytes under which the unary responses are sent uncompressed"`
+}
+
+func DefaultConfig() Config {
+  return Config{
+    LogLevel:          "info",
+    Addr:              "0.0.0.0:50051",
+    AdminAddr:         "127.0.0.1:50061",
+    History:           HistoryConfig{DB: "calculator_history.db", MaxAge: 30 * 24 * time.Hour, MaxEntries: 100000},
+    Cache:             CacheConfig{Size: 1000, TTL: 10 * time.Minute},
+    SessionTTL:        30 * time.Minute,
+    MaxSessions:       10000,
+    RateLimitKey:      "default",
+    Shed:              ShedConfig{MaxStreams: 1000},
+    IdempotencyWindow: 10 * time.Minute,
+    Deadlines:         DeadlinesConfig{Default: 30 * time.Second, Max: 2 * time.Minute},
+    Compression:       CompressionConfig{MinSize: 1024},
+    Features:          Features{Cache: true, ComplexResults: true},
+  }
+}
+
+func (c *Config) Validate() error {
+  if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+    return err
+  }
+  if c.Addr == "" {
+    return errors.New("addr is required")
+  }
+  if c.AdminAddr == c.Addr {
+    return errors.New("admin_addr must differ from addr, the AdminService is not served to the clients")
+  }
+  if _, err := newSettings(*c); err != nil {
+    return err
+  }
+  if c.History.MaxAge < 0 || c.History.MaxEntries < 0 {
+    return errors.New("history retention cannot be negative")
+  }
+  if c.Cache.Size < 0 || c.Cache.TTL < 0 {
+    return errors.New("cache size and ttl cannot be negative")
+  }
+  if c.SessionTTL <= 0 {
+    return errors.New("session_ttl must be positive")
+  }
+  if c.MaxSessions < 0 {
+    return errors.New("max_sessions cannot be negative")
+  }
+  if _, err := ratelimit.ParseRules(c.RateLimit); err != nil {
+    return fmt.Errorf("invalid rate_limit: %v", err)
+  }
+  if _, ok := ratelimit.KeyFuncs[c.RateLimitKey]; !ok {
+    return fmt.Errorf("unknown rate_limit_key %q", c.RateLimitKey)
+  }
+  if _, err := admission.ParseLimits(c.StreamLimits); err != nil {
+    return fmt.Errorf("invalid stream_limits: %v", err)
+  }
+  if c.MaxClientStreams < 0 || c.Shed.MaxStreams < 0 {
+    return errors.New("stream limits cannot be negative")
+  }
+  if c.Shed.TargetLatency < 0 || c.IdempotencyWindow < 0 {
+    return errors.New("durations cannot be negative")
+  }
+  if _, err := compression.ParseMethods(c.Compression.Methods); err != nil {
+    return fmt.Errorf("invalid compression.methods: %v", err)
+  }
+  if c.Compression.MinSize < 0 {
+    return errors.New("compression.min_size cannot be negative")
+  }
+  if d := c.Deadlines; d.Default < 0 || d.Max < 0 || d.StreamDefault < 0 || d.StreamMax < 0 {
+    return errors.New("deadlines cannot be negative")
+  }
+  return nil
+}
+
+// settings is the running configuration of the server. It is replaced whole when the
+// configuration is reloaded.
+type settings struct {
+  cfg      Config
+  disabled map[string]bool
+}
+
+func newSettings(cfg Config) (*settings, error) {
+  st := &settings{
+    cfg:      cfg,
+    disabled: make(map[string]bool),
+  }
+  for _, method := range strings.Split(cfg.DisabledMethods, ",") {
+    if method = strings.TrimSpace(method); method == "" {
+      continue
+    }
+    if !strings.HasPrefix(method, "/") || strings.Count(method, "/") != 2 {
+      return nil, fmt.Errorf("invalid disabled method %q, expecting /package.Service/Method", method)
+    }
+    st.disabled[method] = true
+  }
+  return st, nil
+}
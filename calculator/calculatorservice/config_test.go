@@ -0,0 +1,48 @@
+package calculatorservice
+
+import (
+  "strings"
+  "testing"
+)
+
+func TestValidate(t *testing.T) {
+  for _, tc := range []struct {
+    name   string
+    change func(c *Config)
+    err    string // empty for a valid configuration.
+  }{
+    {name: "defaults", change: func(c *Config) {}},
+    {name: "no admin", change: func(c *Config) { c.AdminAddr = "" }},
+    {name: "log level", change: func(c *Config) { c.LogLevel = "loud" }, err: "loud"},
+    {name: "no addr", change: func(c *Config) { c.Addr = "" }, err: "addr is required"},
+    {name: "admin on addr", change: func(c *Config) { c.AdminAddr = c.Addr }, err: "admin_addr must differ from addr"},
+    {name: "disabled method", change: func(c *Config) { c.DisabledMethods = "Sum" }, err: "invalid disabled method"},
+    {name: "history retention", change: func(c *Config) { c.History.MaxEntries = -1 }, err: "history retention"},
+    {name: "cache", change: func(c *Config) { c.Cache.TTL = -1 }, err: "cache size and ttl"},
+    {name: "session ttl", change: func(c *Config) { c.SessionTTL = 0 }, err: "session_ttl"},
+    {name: "max sessions", change: func(c *Config) { c.MaxSessions = -1 }, err: "max_sessions"},
+    {name: "rate limit", change: func(c *Config) { c.RateLimit = "/calculator.CalculatorService/Sum" }, err: "invalid rate_limit"},
+    {name: "rate limit key", change: func(c *Config) { c.RateLimitKey = "name" }, err: "unknown rate_limit_key"},
+    {name: "stream limits", change: func(c *Config) { c.StreamLimits = "*=none" }, err: "invalid stream_limits"},
+    {name: "client streams", change: func(c *Config) { c.MaxClientStreams = -1 }, err: "stream limits cannot be negative"},
+    {name: "target latency", change: func(c *Config) { c.Shed.TargetLatency = -1 }, err: "durations cannot be negative"},
+    {name: "compression methods", change: func(c *Config) { c.Compression.Methods = "*=lz4" }, err: "invalid compression.methods"},
+    {name: "compression min size", change: func(c *Config) { c.Compression.MinSize = -1 }, err: "compression.min_size"},
+    {name: "deadlines", change: func(c *Config) { c.Deadlines.StreamMax = -1 }, err: "deadlines cannot be negative"},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      cfg := DefaultConfig()
+      tc.change(&cfg)
+      err := cfg.Validate()
+      if tc.err == "" {
+        if err != nil {
+          t.Fatalf("Validate() = %v", err)
+        }
+        return
+      }
+      if err == nil || !strings.Contains(err.Error(), tc.err) {
+        t.Fatalf("Validate() = %v, want an error with %q", err, tc.err)
+      }
+    })
+  }
+}
@@ -0,0 +1,314 @@
+package calculatorservice
+
+import (
+  "context"
+  "fmt"
+  "math"
+  "strconv"
+
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+const (
+  // maxExpressionLength limits the size of the expressions, in bytes.
+  maxExpressionLength = 4096
+
+  // maxExpressionDepth limits the nesting of parentheses, signs and powers, which the
+  // parser follows by recursion.
+  maxExpressionDepth = 100
+)
+
+// functions lists the functions available in expressions.
+var functions = map[string]func(float64) (float64, error){
+  "sqrt": func(x float64) (float64, error) {
+    if x < 0 {
+      return 0, status.Errorf(codes.InvalidArgument, "Received a negative number: %v!", x)
+    }
+    return math.Sqrt(x), nil
+  },
+  "abs": func(x float64) (float64, error) {
+    return math.Abs(x), nil
+  },
+}
+
+// scope resolves the references of an expression.
+type scope interface {
+  variable(name string) (float64, error)
+  result(index int) (float64, error)
+}
+
+// statement is a parsed line, assignment is empty when the line is a plain expression.
+type statement struct {
+  assignment string
+  expression string
+}
+
+// parseStatement splits "name = expression" lines, the expression itself is checked on evaluation.
+func parseStatement(line string) (statement, error) {
+  p := &parser{input: line}
+  p.skipSpaces()
+  start := p.pos
+  name := p.identifier()
+  p.skipSpaces()
+  if name != "" && p.peek() == '=' {
+    if name == "ans" {
+      return statement{}, status.Error(codes.InvalidArgument, "Cannot assign to ans!")
+    }
+    if _, ok := functions[name]; ok {
+      return statement{}, status.Errorf(codes.InvalidArgument, "Cannot assign to function %v!", name)
+    }
+    return statement{assignment: name, expression: line[p.pos+1:]}, nil
+  }
+  return statement{expression: line[start:]}, nil
+}
+
+// evaluate computes an expression with +, -, *, /, %, ^, parentheses, numbers,
+// variables, ans, $n and the functions above. It stops with the status of ctx once ctx
+// is done.
+func evaluate(ctx context.Context, expression string, s scope) (float64, error) {
+  if len(expression) > maxExpressionLength {
+    return 0, status.Errorf(codes.InvalidArgument, "Received an expression of %v bytes, at most %v are allowed!", len(expression), maxExpressionLength)
+  }
+  p := &parser{ctx: ctx, input: expression, scope: s}
+  value, err := p.expr()
+  if err != nil {
+    return 0, err
+  }
+  p.skipSpaces()
+  if p.pos < len(p.input) {
+    return 0, p.errorf("unexpected %q", p.input[p.pos])
+  }
+  return value, nil
+}
+
+// parser is a recursive descent parser evaluating as it goes.
+type parser struct {
+  ctx   context.Context
+  input string
+  pos   int
+  depth int // of the unary calls in progress, every nesting goes through one.
+  scope scope
+}
+
+// expr := term (('+' | '-') term)*
+func (p *parser) expr() (float64, error) {
+  left, err := p.term()
+  if err != nil {
+    return 0, err
+  }
+  for {
+    p.skipSpaces()
+    op := p.peek()
+    if op != '+' && op != '-' {
+      return left, nil
+    }
+    p.pos++
+    right, err := p.term()
+    if err != nil {
+      return 0, err
+    }
+    if op == '+' {
+      left += right
+    } else {
+      left -= right
+    }
+  }
+}
+
+// term := unary (('*' | '/' | '%') unary)*
+func (p *parser) term() (float64, error) {
+  left, err := p.unary()
+  if err != nil {
+    return 0, err
+  }
+  for {
+    p.skipSpaces()
+    op := p.peek()
+    if op != '*' && op != '/' && op != '%' {
+      return left, nil
+    }
+    p.pos++
+    right, err := p.unary()
+    if err != nil {
+      return 0, err
+    }
+    switch op {
+    case '*':
+      left *= right
+    case '/':
+      if right == 0 {
+        return 0, status.Error(codes.InvalidArgument, "Division by zero!")
+      }
+      left /= right
+    case '%':
+      if right == 0 {
+        return 0, status.Error(codes.InvalidArgument, "Division by zero!")
+      }
+      left = math.Mod(left, right)
+    }
+  }
+}
+
+// unary := ('-' | '+') unary | power
+func (p *parser) unary() (float64, error) {
+  // Every operand goes through here, checking ctx on each step of the loops above.
+  if err := p.ctx.Err(); err != nil {
+    return 0, contextStatus(err)
+  }
+  p.depth++
+  defer func() { p.depth-- }()
+  if p.depth > maxExpressionDepth {
+    return 0, p.errorf("nested more than %v levels deep", maxExpressionDepth)
+  }
+  p.skipSpaces()
+  switch p.peek() {
+  case '-':
+    p.pos++
+    value, err := p.unary()
+    return -value, err
+  case '+':
+    p.pos++
+    return p.unary()
+  }
+  return p.power()
+}
+
+// power := primary ('^' unary)?, right associative.
+func (p *parser) power() (float64, error) {
+  base, err := p.primary()
+  if err != nil {
+    return 0, err
+  }
+  p.skipSpaces()
+  if p.peek() != '^' {
+    return base, nil
+  }
+  p.pos++
+  exponent, err := p.unary()
+  if err != nil {
+    return 0, err
+  }
+  return math.Pow(base, exponent), nil
+}
+
+// primary := number | '$' digits | identifier | identifier '(' expr ')' | '(' expr ')'
+func (p *parser) primary() (float64, error) {
+  p.skipSpaces()
+  c := p.peek()
+  switch {
+  case c == '(':
+    p.pos++
+    value, err := p.expr()
+    if err != nil {
+      return 0, err
+    }
+    if err := p.expect(')'); err != nil {
+      return 0, err
+    }
+    return value, nil
+  case c == '$':
+    p.pos++
+    start := p.pos
+    for p.pos < len(p.input) && isDigit(p.input[p.pos]) {
+      p.pos++
+    }
+    index, err := strconv.Atoi(p.input[start:p.pos])
+    if err != nil {
+      return 0, p.errorf("expected a result number after $")
+    }
+    return p.scope.result(index)
+  case isDigit(c) || c == '.':
+    return p.number()
+  }
+
+  name := p.identifier()
+  if name == "" {
+    if p.pos >= len(p.input) {
+      return 0, p.errorf("unexpected end of expression")
+    }
+    return 0, p.errorf("unexpected %q", c)
+  }
+  if function, ok := functions[name]; ok {
+    if err := p.expect('('); err != nil {
+      return 0, err
+    }
+    argument, err := p.expr()
+    if err != nil {
+      return 0, err
+    }
+    if err := p.expect(')'); err != nil {
+      return 0, err
+    }
+    return function(argument)
+  }
+  return p.scope.variable(name)
+}
+
+func (p *parser) number() (float64, error) {
+  start := p.pos
+  for p.pos < len(p.input) && (isDigit(p.input[p.pos]) || p.input[p.pos] == '.') {
+    p.pos++
+  }
+  // Exponent part, as in 1e-3.
+  if p.pos < len(p.input) && (p.input[p.pos] == 'e' || p.input[p.pos] == 'E') {
+    p.pos++
+    if p.pos < len(p.input) && (p.input[p.pos] == '+' || p.input[p.pos] == '-') {
+      p.pos++
+    }
+    for p.pos < len(p.input) && isDigit(p.input[p.pos]) {
+      p.pos++
+    }
+  }
+  value, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+  if err != nil {
+    return 0, p.errorf("invalid number %q", p.input[start:p.pos])
+  }
+  return value, nil
+}
+
+func (p *parser) identifier() string {
+  start := p.pos
+  for p.pos < len(p.input) {
+    c := p.input[p.pos]
+    if !isLetter(c) && !(p.pos > start && isDigit(c)) {
+      break
+    }
+    p.pos++
+  }
+  return p.input[start:p.pos]
+}
+
+func (p *parser) expect(c byte) error {
+  p.skipSpaces()
+  if p.peek() != c {
+    return p.errorf("expected %q", c)
+  }
+  p.pos++
+  return nil
+}
+
+func (p *parser) peek() byte {
+  if p.pos >= len(p.input) {
+    return 0
+  }
+  return p.input[p.pos]
+}
+
+func (p *parser) skipSpaces() {
+  for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+    p.pos++
+  }
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+  return status.Errorf(codes.InvalidArgument, "Invalid expression at position %v: %v!", p.pos, fmt.Sprintf(format, args...))
+}
+
+func isDigit(c byte) bool {
+  return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+  return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
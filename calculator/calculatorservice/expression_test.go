@@ -0,0 +1,115 @@
+package calculatorservice
+
+import (
+  "context"
+  "strings"
+  "testing"
+  "time"
+
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// testScope has the variable x and the results 10 then 20.
+type testScope struct{}
+
+func (testScope) variable(name string) (float64, error) {
+  if name == "x" {
+    return 2, nil
+  }
+  return 0, status.Errorf(codes.NotFound, "Unknown variable %v!", name)
+}
+
+func (testScope) result(index int) (float64, error) {
+  if index < 1 || index > 2 {
+    return 0, status.Errorf(codes.NotFound, "Unknown result $%v!", index)
+  }
+  return float64(index * 10), nil
+}
+
+func TestEvaluate(t *testing.T) {
+  for _, tc := range []struct {
+    expression string
+    want       float64
+    code       codes.Code
+  }{
+    {expression: "1 + 2 * 3", want: 7},
+    {expression: "(1 + 2) * 3", want: 9},
+    {expression: "-2 ^ 2", want: -4},
+    {expression: "2 ^ 3 ^ 2", want: 512},
+    {expression: "7 % 4 - 1e1", want: -7},
+    {expression: "sqrt(x * 8) + abs(-1)", want: 5},
+    {expression: "$2 / $1", want: 2},
+    {expression: strings.Repeat("(", 50) + "1" + strings.Repeat(")", 50), want: 1},
+    {expression: strings.Repeat("-", 50) + "1", want: 1},
+    {expression: "1 / 0", code: codes.InvalidArgument},
+    {expression: "sqrt(-1)", code: codes.InvalidArgument},
+    {expression: "(1 + 2", code: codes.InvalidArgument},
+    {expression: "1 2", code: codes.InvalidArgument},
+    {expression: "", code: codes.InvalidArgument},
+    {expression: "y", code: codes.NotFound},
+    {expression: "$3", code: codes.NotFound},
+    {expression: strings.Repeat("(", maxExpressionDepth+1) + "1" + strings.Repeat(")", maxExpressionDepth+1), code: codes.InvalidArgument},
+    {expression: strings.Repeat("-", maxExpressionDepth+1) + "1", code: codes.InvalidArgument},
+    {expression: strings.Repeat("2^", maxExpressionDepth+1) + "1", code: codes.InvalidArgument},
+    {expression: strings.Repeat("(", 1000000), code: codes.InvalidArgument},
+    {expression: strings.Repeat("1+", maxExpressionLength/2) + "1", code: codes.InvalidArgument},
+  } {
+    name := tc.expression
+    if len(name) > 40 {
+      name = name[:40] + "..."
+    }
+    t.Run(name, func(t *testing.T) {
+      got, err := evaluate(context.Background(), tc.expression, testScope{})
+      if status.Code(err) != tc.code {
+        t.Fatalf("evaluate() = %v, %v, want code %v", got, err, tc.code)
+      }
+      if err == nil && got != tc.want {
+        t.Fatalf("evaluate() = %v, want %v", got, tc.want)
+      }
+    })
+  }
+}
+
+func TestParseStatement(t *testing.T) {
+  for _, tc := range []struct {
+    line       string
+    assignment string
+    expression string
+    code       codes.Code
+  }{
+    {line: "1 + 2", expression: "1 + 2"},
+    {line: " x = 1 + 2", assignment: "x", expression: " 1 + 2"},
+    {line: "ans = 1", code: codes.InvalidArgument},
+    {line: "sqrt = 1", code: codes.InvalidArgument},
+  } {
+    t.Run(tc.line, func(t *testing.T) {
+      got, err := parseStatement(tc.line)
+      if status.Code(err) != tc.code {
+        t.Fatalf("parseStatement() = %+v, %v, want code %v", got, err, tc.code)
+      }
+      if err == nil && (got.assignment != tc.assignment || got.expression != tc.expression) {
+        t.Fatalf("parseStatement() = %+v, want %v = %q", got, tc.assignment, tc.expression)
+      }
+    })
+  }
+}
+
+func TestEvaluateContextDone(t *testing.T) {
+  canceled, cancel := context.WithCancel(context.Background())
+  cancel()
+  expired, cancel := context.WithDeadline(context.Background(), time.Now())
+  defer cancel()
+
+  for _, tc := range []struct {
+    ctx  context.Context
+    code codes.Code
+  }{
+    {ctx: canceled, code: codes.Canceled},
+    {ctx: expired, code: codes.DeadlineExceeded},
+  } {
+    if _, err := evaluate(tc.ctx, "1 + 2 * 3", testScope{}); status.Code(err) != tc.code {
+      t.Errorf("evaluate() = %v, want %v", err, tc.code)
+    }
+  }
+}
@@ -0,0 +1,531 @@
+package calculatorservice
+
+import (
+  "bytes"
+  "context"
+  "encoding/base64"
+  "encoding/binary"
+  "strconv"
+  "strings"
+  "sync"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/clock"
+  "github.com/_dev/grpc-go-example/logging"
+  "github.com/_dev/grpc-go-example/ratelimit"
+
+  bolt "go.etcd.io/bbolt"
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/peer"
+  "google.golang.org/grpc/status"
+  "google.golang.org/protobuf/encoding/protojson"
+  "google.golang.org/protobuf/proto"
+  "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+  defaultHistoryPageSize = 50
+  maxHistoryPageSize     = 1000
+
+  // maxRecordedMessages limits how many messages of each direction are recorded for a stream.
+  maxRecordedMessages = 100
+
+  // historyQueueSize is how many entries wait for the writer, the entries recorded past it
+  // are dropped rather than slowing the calls down.
+  historyQueueSize = 10000
+)
+
+// historyBucket holds the entries keyed by time, clientsBucket the same keys prefixed by the
+// client of each entry, so that a page of a client reads its own entries only.
+var (
+  historyBucket = []byte("history")
+  clientsBucket = []byte("history_clients")
+)
+
+// historyKeySize is the size of the keys of the entries: the time of the call in
+// nanoseconds, then a sequence breaking the ties, both big endian so that keys sort by time.
+const historyKeySize = 16
+
+// retentionPolicy bounds the history, a zero value disables the bound.
+type retentionPolicy struct {
+  maxAge     time.Duration
+  maxEntries int
+}
+
+// historyStore records the calls made to the service in a BoltDB file. The entries are
+// written in the background, those waiting at once in a single transaction.
+type historyStore struct {
+  db        *bolt.DB
+  retention retentionPolicy
+  clock     clock.Clock
+  entries   chan *calculatorpb.HistoryEntry
+  flushes   chan chan struct{} // closed once the entries waiting are written.
+  done      chan struct{}      // closed to stop the writer and the retention.
+  stopped   chan struct{}      // closed once the writer wrote the last entries.
+}
+
+func openHistoryStore(path string, retention retentionPolicy, clk clock.Clock) (*historyStore, error) {
+  db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+  if err != nil {
+    return nil, err
+  }
+  err = db.Update(func(tx *bolt.Tx) error {
+    if _, err := tx.CreateBucketIfNotExists(historyBucket); err != nil {
+      return err
+    }
+    if _, err := tx.CreateBucketIfNotExists(clientsBucket); err != nil {
+      return err
+    }
+    return rekeyHistory(tx)
+  })
+  if err != nil {
+    db.Close()
+    return nil, err
+  }
+
+  h := &historyStore{
+    db:        db,
+    retention: retention,
+    clock:     clk,
+    entries:   make(chan *calculatorpb.HistoryEntry, historyQueueSize),
+    flushes:   make(chan chan struct{}),
+    done:      make(chan struct{}),
+    stopped:   make(chan struct{}),
+  }
+  go h.write()
+  if retention.maxAge > 0 || retention.maxEntries > 0 {
+    go h.enforceRetention(time.Minute)
+  }
+  return h, nil
+}
+
+// Close writes the entries waiting and closes the store.
+func (h *historyStore) Close() error {
+  close(h.done)
+  <-h.stopped
+  return h.db.Close()
+}
+
+// record queues an entry for the writer.
+func (h *historyStore) record(entry *calculatorpb.HistoryEntry) {
+  select {
+  case h.entries <- entry:
+  default:
+    logging.Errorf("Error while recording history: %v entries wait already, dropping the call to %v", historyQueueSize, entry.GetMethod())
+  }
+}
+
+// write stores the queued entries until the store is closed, then the last ones.
+func (h *historyStore) write() {
+  defer close(h.stopped)
+  for {
+    var flushed chan struct{}
+    var batch []*calculatorpb.HistoryEntry
+    select {
+    case entry := <-h.entries:
+      batch = append(batch, entry)
+    case flushed = <-h.flushes:
+    case <-h.done:
+      h.store(h.queued(nil))
+      return
+    }
+    h.store(h.queued(batch))
+    if flushed != nil {
+      close(flushed)
+    }
+  }
+}
+
+// queued appends the entries waiting to batch.
+func (h *historyStore) queued(batch []*calculatorpb.HistoryEntry) []*calculatorpb.HistoryEntry {
+  for {
+    select {
+    case entry := <-h.entries:
+      batch = append(batch, entry)
+    default:
+      return batch
+    }
+  }
+}
+
+// store writes the entries in a single transaction, keyed by their time and indexed by
+// their client.
+func (h *historyStore) store(batch []*calculatorpb.HistoryEntry) {
+  if len(batch) == 0 {
+    return
+  }
+  err := h.db.Update(func(tx *bolt.Tx) error {
+    b := tx.Bucket(historyBucket)
+    clients := tx.Bucket(clientsBucket)
+    for _, entry := range batch {
+      seq, err := b.NextSequence()
+      if err != nil {
+        return err
+      }
+      entry.Id = strconv.FormatUint(seq, 10)
+      value, err := proto.Marshal(entry)
+      if err != nil {
+        return err
+      }
+      key := historyKey(entry.GetTimestamp().AsTime(), seq)
+      if err := b.Put(key, value); err != nil {
+        return err
+      }
+      if err := clients.Put(clientKey(entry.GetClient(), key), nil); err != nil {
+        return err
+      }
+    }
+    return nil
+  })
+  if err != nil {
+    logging.Errorf("Error while recording history, dropping %v entries: %v", len(batch), err)
+  }
+}
+
+// flush waits for the entries recorded so far to be written.
+func (h *historyStore) flush() {
+  flushed := make(chan struct{})
+  select {
+  case h.flushes <- flushed:
+    <-flushed
+  case <-h.stopped:
+  }
+}
+
+// list returns a page of the entries matching the request, oldest first, those of client
+// only unless it is empty. The page starts at the token or at the start time, and reads
+// the entries of the client up to the end time, skipping only those of other methods.
+func (h *historyStore) list(client string, req *calculatorpb.ListHistoryRequest) (*calculatorpb.ListHistoryResponse, error) {
+  pageSize := int(req.GetPageSize())
+  if pageSize <= 0 {
+    pageSize = defaultHistoryPageSize
+  }
+  if pageSize > maxHistoryPageSize {
+    pageSize = maxHistoryPageSize
+  }
+
+  var start []byte
+  if req.GetStartTime() != nil {
+    start = historyKey(req.GetStartTime().AsTime(), 0)
+  }
+  if token := req.GetPageToken(); token != "" {
+    key, err := base64.RawURLEncoding.DecodeString(token)
+    if err != nil || len(key) != historyKeySize {
+      return nil, status.Errorf(codes.InvalidArgument, "Received an invalid page token: %v!", token)
+    }
+    start = key
+  }
+  var end []byte
+  if req.GetEndTime() != nil {
+    end = historyKey(req.GetEndTime().AsTime(), 0)
+  }
+
+  h.flush()
+  res := &calculatorpb.ListHistoryResponse{}
+  err := h.db.View(func(tx *bolt.Tx) error {
+    b := tx.Bucket(historyBucket)
+    next := historyCursor(tx, client, start)
+    for key := next(); key != nil; key = next() {
+      if end != nil && bytes.Compare(key, end) >= 0 {
+        return nil
+      }
+      entry := &calculatorpb.HistoryEntry{}
+      if err := proto.Unmarshal(b.Get(key), entry); err != nil {
+        return err
+      }
+      if !matchesHistoryMethod(entry, req.GetMethod()) {
+        continue
+      }
+      if len(res.Entries) == pageSize {
+        res.NextPageToken = base64.RawURLEncoding.EncodeToString(key)
+        return nil
+      }
+      res.Entries = append(res.Entries, entry)
+    }
+    return nil
+  })
+  if err != nil {
+    return nil, status.Errorf(codes.Internal, "Could not read history: %v", err)
+  }
+  return res, nil
+}
+
+// historyCursor returns the keys of the entries from start, those of client only unless
+// it is empty, in order and nil after the last one.
+func historyCursor(tx *bolt.Tx, client string, start []byte) func() []byte {
+  if client == "" {
+    c := tx.Bucket(historyBucket).Cursor()
+    k, _ := c.Seek(start)
+    return func() []byte {
+      key := k
+      k, _ = c.Next()
+      return key
+    }
+  }
+  prefix := clientKey(client, nil)
+  c := tx.Bucket(clientsBucket).Cursor()
+  k, _ := c.Seek(clientKey(client, start))
+  return func() []byte {
+    if k == nil || !bytes.HasPrefix(k, prefix) {
+      return nil
+    }
+    key := k[len(prefix):]
+    k, _ = c.Next()
+    return key
+  }
+}
+
+func matchesHistoryMethod(entry *calculatorpb.HistoryEntry, method string) bool {
+  return method == "" || entry.GetMethod() == method || strings.HasSuffix(entry.GetMethod(), "/"+method)
+}
+
+// enforceRetention periodically deletes the entries that are too old or too many.
+func (h *historyStore) enforceRetention(every time.Duration) {
+  ticker := h.clock.NewTicker(every)
+  defer ticker.Stop()
+  for {
+    select {
+    case <-ticker.C():
+    case <-h.done:
+      return
+    }
+    if err := h.prune(h.clock.Now()); err != nil {
+      logging.Errorf("Error while pruning history: %v", err)
+    }
+  }
+}
+
+func (h *historyStore) prune(now time.Time) error {
+  return h.db.Update(func(tx *bolt.Tx) error {
+    b := tx.Bucket(historyBucket)
+    clients := tx.Bucket(clientsBucket)
+    excess := 0
+    if h.retention.maxEntries > 0 {
+      excess = b.Stats().KeyN - h.retention.maxEntries
+    }
+
+    // Entries are in time order, so the ones to delete are all at the start.
+    c := b.Cursor()
+    for k, v := c.First(); k != nil; k, v = c.First() {
+      entry := &calculatorpb.HistoryEntry{}
+      if err := proto.Unmarshal(v, entry); err != nil {
+        return err
+      }
+      if excess <= 0 && (h.retention.maxAge <= 0 || now.Sub(entry.GetTimestamp().AsTime()) <= h.retention.maxAge) {
+        return nil
+      }
+      if err := clients.Delete(clientKey(entry.GetClient(), k)); err != nil {
+        return err
+      }
+      if err := c.Delete(); err != nil {
+        return err
+      }
+      excess--
+    }
+    return nil
+  })
+}
+
+// rekeyHistory moves the entries keyed by their sequence alone, as written by the earlier
+// versions, under the keys of their time, and indexes them by client.
+func rekeyHistory(tx *bolt.Tx) error {
+  b := tx.Bucket(historyBucket)
+  c := b.Cursor()
+  var old [][]byte
+  for k, _ := c.First(); k != nil && len(k) != historyKeySize; k, _ = c.Next() {
+    old = append(old, k)
+  }
+  for _, k := range old {
+    v := b.Get(k)
+    entry := &calculatorpb.HistoryEntry{}
+    if err := proto.Unmarshal(v, entry); err != nil {
+      return err
+    }
+    key := historyKey(entry.GetTimestamp().AsTime(), binary.BigEndian.Uint64(k))
+    if err := b.Put(key, append([]byte(nil), v...)); err != nil {
+      return err
+    }
+    if err := tx.Bucket(clientsBucket).Put(clientKey(entry.GetClient(), key), nil); err != nil {
+      return err
+    }
+    if err := b.Delete(k); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+func historyKey(t time.Time, seq uint64) []byte {
+  key := make([]byte, historyKeySize)
+  binary.BigEndian.PutUint64(key, uint64(t.UnixNano()))
+  binary.BigEndian.PutUint64(key[8:], seq)
+  return key
+}
+
+// clientKey returns the key of the index of client for the entry key.
+func clientKey(client string, key []byte) []byte {
+  return append(append([]byte(client), 0), key...)
+}
+
+// newEntry starts an entry for a call, the outcome is filled by finish.
+func (h *historyStore) newEntry(ctx context.Context, method string) *calculatorpb.HistoryEntry {
+  entry := &calculatorpb.HistoryEntry{
+    Method:    method,
+    Timestamp: timestamppb.New(h.clock.Now()),
+    Client:    ratelimit.DefaultKey(ctx),
+  }
+  if p, ok := peer.FromContext(ctx); ok {
+    entry.Caller = p.Addr.String()
+  }
+  return entry
+}
+
+func (h *historyStore) finish(entry *calculatorpb.HistoryEntry, err error) {
+  st := status.Convert(err)
+  entry.Code = int32(st.Code())
+  entry.Message = st.Message()
+  h.record(entry)
+}
+
+// UnaryInterceptor records every unary call but ListHistory.
+func (h *historyStore) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  if strings.HasSuffix(info.FullMethod, "/ListHistory") {
+    return handler(ctx, req)
+  }
+
+  entry := h.newEntry(ctx, info.FullMethod)
+  entry.Inputs = messageJSON(req)
+  res, err := handler(ctx, req)
+  if err == nil {
+    entry.Result = messageJSON(res)
+  }
+  h.finish(entry, err)
+  return res, err
+}
+
+// StreamInterceptor records every streaming call with the messages sent each way.
+func (h *historyStore) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  entry := h.newEntry(ss.Context(), info.FullMethod)
+  rs := &recordingStream{ServerStream: ss}
+  err := handler(srv, rs)
+  if info.IsClientStream {
+    entry.Inputs = rs.received.String()
+  } else {
+    entry.Inputs = rs.received.first()
+  }
+  if info.IsServerStream {
+    entry.Result = rs.sent.String()
+  } else {
+    entry.Result = rs.sent.first()
+  }
+  h.finish(entry, err)
+  return err
+}
+
+// recordingStream keeps the JSON of the messages going through a stream.
+type recordingStream struct {
+  grpc.ServerStream
+  received messageLog
+  sent     messageLog
+}
+
+func (s *recordingStream) RecvMsg(m interface{}) error {
+  err := s.ServerStream.RecvMsg(m)
+  if err == nil {
+    s.received.add(m)
+  }
+  return err
+}
+
+func (s *recordingStream) SendMsg(m interface{}) error {
+  err := s.ServerStream.SendMsg(m)
+  if err == nil {
+    s.sent.add(m)
+  }
+  return err
+}
+
+// messageLog is safe for the concurrent use of a sending and a receiving goroutine.
+type messageLog struct {
+  mu       sync.Mutex
+  messages []string
+}
+
+func (l *messageLog) add(m interface{}) {
+  l.mu.Lock()
+  defer l.mu.Unlock()
+  if len(l.messages) < maxRecordedMessages {
+    l.messages = append(l.messages, messageJSON(m))
+  }
+}
+
+func (l *messageLog) first() string {
+  l.mu.Lock()
+  defer l.mu.Unlock()
+  if len(l.messages) == 0 {
+    return ""
+  }
+  return l.messages[0]
+}
+
+func (l *messageLog) String() string {
+  l.mu.Lock()
+  defer l.mu.Unlock()
+  return "[" + strings.Join(l.messages, ",") + "]"
+}
+
+func messageJSON(m interface{}) string {
+  msg, ok := m.(proto.Message)
+  if !ok {
+    return ""
+  }
+  b, err := protojson.Marshal(msg)
+  if err != nil {
+    return ""
+  }
+  return string(b)
+}
+
+// ListHistory lists the calls of the caller only, which are not to be told to the others.
+func (s *Server) ListHistory(ctx context.Context, req *calculatorpb.ListHistoryRequest) (*calculatorpb.ListHistoryResponse, error) {
+  logging.Infof("Received ListHistory RPC: %v\n", req)
+  if s.history == nil {
+    return nil, status.Error(codes.Unimplemented, "History is not enabled on this server!")
+  }
+  client := ratelimit.DefaultKey(ctx)
+  if audit, _ := ctx.Value(auditKey{}).(bool); audit {
+    client = ""
+  }
+  return s.history.list(client, req)
+}
+
+// calculatorMethods prefixes the full names of the methods of the CalculatorService.
+const calculatorMethods = "/calculator.CalculatorService/"
+
+// auditKey marks the context of the calls served by AuditInterceptor.
+type auditKey struct{}
+
+// AuditInterceptor is to be installed on a listener trusted by the operators, such as the
+// one of the AdminService, where s is registered as well: it serves ListHistory over the
+// calls of every client, and none of the other methods of the CalculatorService. The
+// calls to the other services go through.
+func (s *Server) AuditInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  switch {
+  case info.FullMethod == calculatorMethods+"ListHistory":
+    return handler(context.WithValue(ctx, auditKey{}, true), req)
+  case strings.HasPrefix(info.FullMethod, calculatorMethods):
+    return nil, status.Errorf(codes.Unimplemented, "%v is not served on the audit listener!", info.FullMethod)
+  }
+  return handler(ctx, req)
+}
+
+// AuditStreamInterceptor is the stream counterpart of AuditInterceptor, the streams of the
+// CalculatorService are not served.
+func (s *Server) AuditStreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  if strings.HasPrefix(info.FullMethod, calculatorMethods) {
+    return status.Errorf(codes.Unimplemented, "%v is not served on the audit listener!", info.FullMethod)
+  }
+  return handler(srv, ss)
+}
@@ -0,0 +1,277 @@
+package calculatorservice
+
+import (
+  "context"
+  "encoding/binary"
+  "net"
+  "path/filepath"
+  "reflect"
+  "strconv"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/clock"
+
+  bolt "go.etcd.io/bbolt"
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/peer"
+  "google.golang.org/grpc/status"
+  "google.golang.org/protobuf/proto"
+  "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const sum = "/calculator.CalculatorService/Sum"
+
+func openTestHistory(t *testing.T, path string, retention retentionPolicy, clk clock.Clock) *historyStore {
+  h, err := openHistoryStore(path, retention, clk)
+  if err != nil {
+    t.Fatal(err)
+  }
+  return h
+}
+
+// callFrom records a call to Sum from addr.
+func callFrom(h *historyStore, addr string, first int32) {
+  ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 1234}})
+  h.UnaryInterceptor(ctx, &calculatorpb.SumRequest{FirstNumber: first}, &grpc.UnaryServerInfo{FullMethod: sum},
+    func(ctx context.Context, req interface{}) (interface{}, error) {
+      return &calculatorpb.SumResponse{}, nil
+    })
+}
+
+func TestHistoryClients(t *testing.T) {
+  h := openTestHistory(t, filepath.Join(t.TempDir(), "history.db"), retentionPolicy{}, clock.Real)
+  defer h.Close()
+  callFrom(h, "10.0.0.1", 1)
+  callFrom(h, "10.0.0.2", 2)
+  callFrom(h, "10.0.0.1", 3)
+
+  // Each client lists its own calls only, written by the time it lists them.
+  res, err := h.list("ip:10.0.0.1", &calculatorpb.ListHistoryRequest{})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(res.GetEntries()) != 2 {
+    t.Fatalf("list() = %v, want the 2 calls of the client", res.GetEntries())
+  }
+  for i, entry := range res.GetEntries() {
+    if entry.GetCaller() != "10.0.0.1:1234" || entry.GetInputs() != []string{`{"firstNumber":1}`, `{"firstNumber":3}`}[i] {
+      t.Errorf("Entry %v = %v, want the call %v of 10.0.0.1", i, entry, i)
+    }
+  }
+  if res, _ := h.list("ip:10.0.0.3", &calculatorpb.ListHistoryRequest{}); len(res.GetEntries()) != 0 {
+    t.Fatalf("list() of a client without calls = %v, want none", res.GetEntries())
+  }
+}
+
+func TestHistoryClose(t *testing.T) {
+  path := filepath.Join(t.TempDir(), "history.db")
+  h := openTestHistory(t, path, retentionPolicy{}, clock.Real)
+  for i := int32(0); i < 100; i++ {
+    callFrom(h, "10.0.0.1", i)
+  }
+  // Closing writes the entries still waiting.
+  if err := h.Close(); err != nil {
+    t.Fatal(err)
+  }
+
+  h = openTestHistory(t, path, retentionPolicy{}, clock.Real)
+  defer h.Close()
+  res, err := h.list("ip:10.0.0.1", &calculatorpb.ListHistoryRequest{PageSize: maxHistoryPageSize})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(res.GetEntries()) != 100 || res.GetEntries()[99].GetId() != "100" {
+    t.Fatalf("list() after Close = %v entries, want 100 in order", len(res.GetEntries()))
+  }
+}
+
+func TestHistoryRetention(t *testing.T) {
+  clk := clock.NewFake(time.Now())
+  h := openTestHistory(t, filepath.Join(t.TempDir(), "history.db"), retentionPolicy{maxAge: time.Hour, maxEntries: 3}, clk)
+  defer h.Close()
+  for i := int32(0); i < 5; i++ {
+    callFrom(h, "10.0.0.1", i)
+    clk.Advance(25 * time.Minute)
+  }
+  h.flush()
+
+  // The first 2 are too many, the third too old.
+  if err := h.prune(clk.Now()); err != nil {
+    t.Fatal(err)
+  }
+  res, err := h.list("ip:10.0.0.1", &calculatorpb.ListHistoryRequest{})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(res.GetEntries()) != 2 || res.GetEntries()[0].GetId() != "4" {
+    t.Fatalf("list() after prune() = %v, want the last 2 entries", res.GetEntries())
+  }
+}
+
+func TestHistoryPages(t *testing.T) {
+  start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+  clk := clock.NewFake(start)
+  h := openTestHistory(t, filepath.Join(t.TempDir(), "history.db"), retentionPolicy{}, clk)
+  defer h.Close()
+  // A call of 10.0.0.1 each minute, between those of another client.
+  for i := int32(0); i < 10; i++ {
+    callFrom(h, "10.0.0.1", i)
+    callFrom(h, "10.0.0.2", i)
+    clk.Advance(time.Minute)
+  }
+  h.flush()
+
+  inputs := func(res *calculatorpb.ListHistoryResponse) []string {
+    var inputs []string
+    for _, entry := range res.GetEntries() {
+      inputs = append(inputs, entry.GetInputs())
+    }
+    return inputs
+  }
+  req := &calculatorpb.ListHistoryRequest{
+    StartTime: timestamppb.New(start.Add(2 * time.Minute)),
+    EndTime:   timestamppb.New(start.Add(7 * time.Minute)),
+    PageSize:  2,
+  }
+  var pages [][]string
+  for {
+    res, err := h.list("ip:10.0.0.1", req)
+    if err != nil {
+      t.Fatal(err)
+    }
+    pages = append(pages, inputs(res))
+    if res.GetNextPageToken() == "" {
+      break
+    }
+    req.PageToken = res.GetNextPageToken()
+  }
+  want := [][]string{
+    {`{"firstNumber":2}`, `{"firstNumber":3}`},
+    {`{"firstNumber":4}`, `{"firstNumber":5}`},
+    {`{"firstNumber":6}`},
+  }
+  if !reflect.DeepEqual(pages, want) {
+    t.Errorf("list() pages = %v, want %v", pages, want)
+  }
+
+  // The cursor of a client seeks its own entries, from the start time.
+  var keys int
+  err := h.db.View(func(tx *bolt.Tx) error {
+    next := historyCursor(tx, "ip:10.0.0.1", historyKey(start.Add(5*time.Minute), 0))
+    for key := next(); key != nil; key = next() {
+      keys++
+    }
+    return nil
+  })
+  if err != nil || keys != 5 {
+    t.Errorf("historyCursor() from the sixth minute = %v keys, %v, want 5", keys, err)
+  }
+
+  if _, err := h.list("ip:10.0.0.1", &calculatorpb.ListHistoryRequest{PageToken: "AAAA"}); status.Code(err) != codes.InvalidArgument {
+    t.Errorf("list() of a malformed token = %v, want InvalidArgument", err)
+  }
+}
+
+func TestHistoryAudit(t *testing.T) {
+  cfg := DefaultConfig()
+  cfg.History.DB = filepath.Join(t.TempDir(), "history.db")
+  s, err := NewServer(cfg, clock.Real)
+  if err != nil {
+    t.Fatal(err)
+  }
+  defer s.Close()
+  callFrom(s.history, "10.0.0.1", 1)
+  callFrom(s.history, "10.0.0.2", 2)
+
+  ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 1234}})
+  list := func(ctx context.Context, req interface{}) (interface{}, error) {
+    return s.ListHistory(ctx, req.(*calculatorpb.ListHistoryRequest))
+  }
+
+  // Clients list their own calls, the audit listener those of every client.
+  res, err := list(ctx, &calculatorpb.ListHistoryRequest{})
+  if err != nil || len(res.(*calculatorpb.ListHistoryResponse).GetEntries()) != 1 {
+    t.Errorf("ListHistory() = %v, %v, want the call of the client", res, err)
+  }
+  res, err = s.AuditInterceptor(ctx, &calculatorpb.ListHistoryRequest{}, &grpc.UnaryServerInfo{FullMethod: "/calculator.CalculatorService/ListHistory"}, list)
+  if err != nil || len(res.(*calculatorpb.ListHistoryResponse).GetEntries()) != 2 {
+    t.Errorf("ListHistory() audited = %v, %v, want the calls of both clients", res, err)
+  }
+
+  // The other methods of the service are not served there, those of other services are.
+  reached := false
+  handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+    reached = true
+    return nil, nil
+  }
+  if _, err := s.AuditInterceptor(ctx, &calculatorpb.SumRequest{}, &grpc.UnaryServerInfo{FullMethod: sum}, handler); status.Code(err) != codes.Unimplemented || reached {
+    t.Errorf("Sum() audited = %v, want Unimplemented", err)
+  }
+  if _, err := s.AuditInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/admin.AdminService/Reload"}, handler); err != nil || !reached {
+    t.Errorf("Reload() audited = %v, want it served", err)
+  }
+  err = s.AuditStreamInterceptor(nil, nil, &grpc.StreamServerInfo{FullMethod: "/calculator.CalculatorService/Session"}, func(interface{}, grpc.ServerStream) error {
+    return nil
+  })
+  if status.Code(err) != codes.Unimplemented {
+    t.Errorf("Session() audited = %v, want Unimplemented", err)
+  }
+}
+
+func TestHistoryRekey(t *testing.T) {
+  path := filepath.Join(t.TempDir(), "history.db")
+  db, err := bolt.Open(path, 0600, nil)
+  if err != nil {
+    t.Fatal(err)
+  }
+  // Entries keyed by their sequence alone, as written by the earlier versions.
+  err = db.Update(func(tx *bolt.Tx) error {
+    b, err := tx.CreateBucket(historyBucket)
+    if err != nil {
+      return err
+    }
+    for i := int64(1); i <= 3; i++ {
+      seq, err := b.NextSequence()
+      if err != nil {
+        return err
+      }
+      value, err := proto.Marshal(&calculatorpb.HistoryEntry{
+        Id:        strconv.FormatUint(seq, 10),
+        Method:    sum,
+        Timestamp: timestamppb.New(time.Unix(i, 0)),
+        Client:    "ip:10.0.0.1",
+      })
+      if err != nil {
+        return err
+      }
+      key := make([]byte, 8)
+      binary.BigEndian.PutUint64(key, seq)
+      if err := b.Put(key, value); err != nil {
+        return err
+      }
+    }
+    return nil
+  })
+  db.Close()
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  h := openTestHistory(t, path, retentionPolicy{}, clock.Real)
+  defer h.Close()
+  callFrom(h, "10.0.0.1", 4)
+  res, err := h.list("ip:10.0.0.1", &calculatorpb.ListHistoryRequest{PageSize: 2})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(res.GetEntries()) != 2 || res.GetEntries()[0].GetId() != "1" || res.GetNextPageToken() == "" {
+    t.Fatalf("list() after rekeying = %v, want the first 2 entries", res)
+  }
+  res, err = h.list("ip:10.0.0.1", &calculatorpb.ListHistoryRequest{PageToken: res.GetNextPageToken()})
+  if err != nil || len(res.GetEntries()) != 2 || res.GetEntries()[1].GetId() != "4" {
+    t.Fatalf("list() of the second page = %v, %v, want the last 2 entries", res, err)
+  }
+}
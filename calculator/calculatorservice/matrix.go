@@ -0,0 +1,379 @@
+package calculatorservice
+
+import (
+  "context"
+  "io"
+  "math"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/logging"
+
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+  "google.golang.org/protobuf/proto"
+)
+
+// maxStreamedRows limits the size of the matrices accepted by MatrixDeterminantRows.
+const maxStreamedRows = 2000
+
+// singularEpsilon is the pivot magnitude under which a matrix is considered singular.
+const singularEpsilon = 1e-12
+
+func (*Server) MatrixAdd(ctx context.Context, req *calculatorpb.MatrixAddRequest) (*calculatorpb.MatrixAddResponse, error) {
+  logging.Infof("Received MatrixAdd RPC.")
+  first, err := fromMatrix(req.GetFirst())
+  if err != nil {
+    return nil, err
+  }
+  second, err := fromMatrix(req.GetSecond())
+  if err != nil {
+    return nil, err
+  }
+  if len(first) != len(second) || len(first[0]) != len(second[0]) {
+    return nil, status.Errorf(codes.InvalidArgument, "Cannot add a %vx%v matrix to a %vx%v matrix!", len(first), len(first[0]), len(second), len(second[0]))
+  }
+
+  result := newMatrix(len(first), len(first[0]))
+  for i := range first {
+    if err := ctx.Err(); err != nil {
+      return nil, contextStatus(err)
+    }
+    for j := range first[i] {
+      result[i][j] = first[i][j] + second[i][j]
+    }
+  }
+  return &calculatorpb.MatrixAddResponse{
+    Result: toMatrix(result),
+  }, nil
+}
+
+func (*Server) MatrixMultiply(ctx context.Context, req *calculatorpb.MatrixMultiplyRequest) (*calculatorpb.MatrixMultiplyResponse, error) {
+  logging.Infof("Received MatrixMultiply RPC.")
+  first, err := fromMatrix(req.GetFirst())
+  if err != nil {
+    return nil, err
+  }
+  second, err := fromMatrix(req.GetSecond())
+  if err != nil {
+    return nil, err
+  }
+  if len(first[0]) != len(second) {
+    return nil, status.Errorf(codes.InvalidArgument, "Cannot multiply a %vx%v matrix by a %vx%v matrix!", len(first), len(first[0]), len(second), len(second[0]))
+  }
+
+  result := newMatrix(len(first), len(second[0]))
+  for i := range first {
+    if err := ctx.Err(); err != nil {
+      return nil, contextStatus(err)
+    }
+    for j := range second[0] {
+      for k := range second {
+        result[i][j] += first[i][k] * second[k][j]
+      }
+    }
+  }
+  return &calculatorpb.MatrixMultiplyResponse{
+    Result: toMatrix(result),
+  }, nil
+}
+
+func (*Server) MatrixTranspose(ctx context.Context, req *calculatorpb.MatrixTransposeRequest) (*calculatorpb.MatrixTransposeResponse, error) {
+  logging.Infof("Received MatrixTranspose RPC.")
+  matrix, err := fromMatrix(req.GetMatrix())
+  if err != nil {
+    return nil, err
+  }
+
+  result := newMatrix(len(matrix[0]), len(matrix))
+  for i := range matrix {
+    if err := ctx.Err(); err != nil {
+      return nil, contextStatus(err)
+    }
+    for j := range matrix[i] {
+      result[j][i] = matrix[i][j]
+    }
+  }
+  return &calculatorpb.MatrixTransposeResponse{
+    Result: toMatrix(result),
+  }, nil
+}
+
+func (s *Server) MatrixDeterminant(ctx context.Context, req *calculatorpb.MatrixDeterminantRequest) (*calculatorpb.MatrixDeterminantResponse, error) {
+  logging.Infof("Received MatrixDeterminant RPC.")
+  res, err := s.shared(ctx, "MatrixDeterminant", req, func(ctx context.Context) (interface{}, error) {
+    return matrixDeterminant(ctx, req)
+  })
+  if err != nil {
+    return nil, err
+  }
+  return res.(*calculatorpb.MatrixDeterminantResponse), nil
+}
+
+func matrixDeterminant(ctx context.Context, req *calculatorpb.MatrixDeterminantRequest) (*calculatorpb.MatrixDeterminantResponse, error) {
+  matrix, err := fromSquareMatrix(req.GetMatrix())
+  if err != nil {
+    return nil, err
+  }
+  det, err := determinant(ctx, matrix)
+  if err != nil {
+    return nil, err
+  }
+  return &calculatorpb.MatrixDeterminantResponse{
+    Determinant: det,
+  }, nil
+}
+
+func (s *Server) MatrixInverse(ctx context.Context, req *calculatorpb.MatrixInverseRequest) (*calculatorpb.MatrixInverseResponse, error) {
+  logging.Infof("Received MatrixInverse RPC.")
+  res, err := s.shared(ctx, "MatrixInverse", req, func(ctx context.Context) (interface{}, error) {
+    return matrixInverse(ctx, req)
+  })
+  if err != nil {
+    return nil, err
+  }
+  return res.(*calculatorpb.MatrixInverseResponse), nil
+}
+
+func matrixInverse(ctx context.Context, req *calculatorpb.MatrixInverseRequest) (*calculatorpb.MatrixInverseResponse, error) {
+  matrix, err := fromSquareMatrix(req.GetMatrix())
+  if err != nil {
+    return nil, err
+  }
+
+  // Solving against the identity gives the inverse.
+  identity := newMatrix(len(matrix), len(matrix))
+  for i := range identity {
+    identity[i][i] = 1
+  }
+  if err := gaussJordan(ctx, matrix, identity); err != nil {
+    return nil, err
+  }
+  return &calculatorpb.MatrixInverseResponse{
+    Result: toMatrix(identity),
+  }, nil
+}
+
+func (s *Server) SolveLinearSystem(ctx context.Context, req *calculatorpb.SolveLinearSystemRequest) (*calculatorpb.SolveLinearSystemResponse, error) {
+  logging.Infof("Received SolveLinearSystem RPC.")
+  res, err := s.shared(ctx, "SolveLinearSystem", req, func(ctx context.Context) (interface{}, error) {
+    return solveLinearSystem(ctx, req)
+  })
+  if err != nil {
+    return nil, err
+  }
+  return res.(*calculatorpb.SolveLinearSystemResponse), nil
+}
+
+func solveLinearSystem(ctx context.Context, req *calculatorpb.SolveLinearSystemRequest) (*calculatorpb.SolveLinearSystemResponse, error) {
+  coefficients, err := fromSquareMatrix(req.GetCoefficients())
+  if err != nil {
+    return nil, err
+  }
+  constants := req.GetConstants().GetValues()
+  if len(constants) != len(coefficients) {
+    return nil, status.Errorf(codes.InvalidArgument, "Received %v constants for %v equations!", len(constants), len(coefficients))
+  }
+
+  solution := newMatrix(len(constants), 1)
+  for i, constant := range constants {
+    solution[i][0] = constant
+  }
+  if err := gaussJordan(ctx, coefficients, solution); err != nil {
+    return nil, err
+  }
+  values := make([]float64, len(solution))
+  for i := range solution {
+    values[i] = solution[i][0]
+  }
+  return &calculatorpb.SolveLinearSystemResponse{
+    Solution: &calculatorpb.Vector{Values: values},
+  }, nil
+}
+
+func (*Server) MatrixDeterminantRows(stream calculatorpb.CalculatorService_MatrixDeterminantRowsServer) error {
+  logging.Infof("Received MatrixDeterminantRows RPC.")
+
+  var matrix [][]float64
+  for {
+    req, err := stream.Recv()
+    if err == io.EOF {
+      break
+    }
+    if err != nil {
+      logging.Errorf("Error while reading client stream: %v", err)
+      return err
+    }
+
+    row := req.GetRow().GetValues()
+    if len(row) == 0 {
+      return status.Errorf(codes.InvalidArgument, "Received an empty row %v!", len(matrix))
+    }
+    if len(matrix) > 0 && len(row) != len(matrix[0]) {
+      return status.Errorf(codes.InvalidArgument, "Received row %v with %v columns, expected %v!", len(matrix), len(row), len(matrix[0]))
+    }
+    if len(matrix) == maxStreamedRows {
+      return status.Errorf(codes.InvalidArgument, "Received more than %v rows!", maxStreamedRows)
+    }
+    matrix = append(matrix, append([]float64(nil), row...))
+  }
+
+  if len(matrix) == 0 {
+    return status.Error(codes.InvalidArgument, "Received an empty matrix!")
+  }
+  if len(matrix) != len(matrix[0]) {
+    return status.Errorf(codes.InvalidArgument, "Received a %vx%v matrix, expected a square one!", len(matrix), len(matrix[0]))
+  }
+  det, err := determinant(stream.Context(), matrix)
+  if err != nil {
+    return err
+  }
+  return stream.SendAndClose(&calculatorpb.MatrixDeterminantRowsResponse{
+    Determinant: det,
+  })
+}
+
+// shared runs compute once for the concurrent identical requests of method, on a context
+// done once every caller has given up.
+func (s *Server) shared(ctx context.Context, method string, req proto.Message, compute func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+  key, err := cacheKey(method, req)
+  if err != nil {
+    return compute(ctx)
+  }
+
+  var res interface{}
+  _, err = s.flights.Do(ctx, key, func(ctx context.Context, emit func(interface{})) error {
+    result, err := compute(ctx)
+    if err != nil {
+      return err
+    }
+    emit(result)
+    return nil
+  }, func(result interface{}) error {
+    res = result
+    return nil
+  })
+  return res, contextStatus(err)
+}
+
+// determinant computes the determinant of a square matrix by Gaussian elimination,
+// the matrix is modified in place. It stops with the status of ctx once ctx is done.
+func determinant(ctx context.Context, matrix [][]float64) (float64, error) {
+  result := 1.0
+  for col := range matrix {
+    if err := ctx.Err(); err != nil {
+      return 0, contextStatus(err)
+    }
+    pivot := pivotRow(matrix, col)
+    if math.Abs(matrix[pivot][col]) < singularEpsilon {
+      return 0, nil
+    }
+    if pivot != col {
+      matrix[pivot], matrix[col] = matrix[col], matrix[pivot]
+      result = -result
+    }
+    result *= matrix[col][col]
+    for row := col + 1; row < len(matrix); row++ {
+      factor := matrix[row][col] / matrix[col][col]
+      for k := col; k < len(matrix); k++ {
+        matrix[row][k] -= factor * matrix[col][k]
+      }
+    }
+  }
+  return result, nil
+}
+
+// gaussJordan reduces the square matrix to the identity applying the same row
+// operations to rhs, which then holds the solution. It fails with InvalidArgument
+// when the matrix is singular, and with the status of ctx once ctx is done. Both
+// matrices are modified in place.
+func gaussJordan(ctx context.Context, matrix, rhs [][]float64) error {
+  for col := range matrix {
+    if err := ctx.Err(); err != nil {
+      return contextStatus(err)
+    }
+    pivot := pivotRow(matrix, col)
+    if math.Abs(matrix[pivot][col]) < singularEpsilon {
+      return status.Error(codes.InvalidArgument, "Received a singular matrix!")
+    }
+    matrix[pivot], matrix[col] = matrix[col], matrix[pivot]
+    rhs[pivot], rhs[col] = rhs[col], rhs[pivot]
+
+    scale := matrix[col][col]
+    for k := range matrix[col] {
+      matrix[col][k] /= scale
+    }
+    for k := range rhs[col] {
+      rhs[col][k] /= scale
+    }
+
+    for row := range matrix {
+      if row == col {
+        continue
+      }
+      factor := matrix[row][col]
+      for k := range matrix[row] {
+        matrix[row][k] -= factor * matrix[col][k]
+      }
+      for k := range rhs[row] {
+        rhs[row][k] -= factor * rhs[col][k]
+      }
+    }
+  }
+  return nil
+}
+
+// pivotRow returns the row, from col down, with the largest magnitude in column col.
+func pivotRow(matrix [][]float64, col int) int {
+  pivot := col
+  for row := col + 1; row < len(matrix); row++ {
+    if math.Abs(matrix[row][col]) > math.Abs(matrix[pivot][col]) {
+      pivot = row
+    }
+  }
+  return pivot
+}
+
+func newMatrix(rows, columns int) [][]float64 {
+  matrix := make([][]float64, rows)
+  for i := range matrix {
+    matrix[i] = make([]float64, columns)
+  }
+  return matrix
+}
+
+// fromMatrix copies a non-empty rectangular matrix, anything else is an InvalidArgument error.
+func fromMatrix(m *calculatorpb.Matrix) ([][]float64, error) {
+  rows := m.GetRows()
+  if len(rows) == 0 || len(rows[0].GetValues()) == 0 {
+    return nil, status.Error(codes.InvalidArgument, "Received an empty matrix!")
+  }
+
+  matrix := make([][]float64, len(rows))
+  for i, row := range rows {
+    values := row.GetValues()
+    if len(values) != len(rows[0].GetValues()) {
+      return nil, status.Errorf(codes.InvalidArgument, "Received row %v with %v columns, expected %v!", i, len(values), len(rows[0].GetValues()))
+    }
+    matrix[i] = append([]float64(nil), values...)
+  }
+  return matrix, nil
+}
+
+func fromSquareMatrix(m *calculatorpb.Matrix) ([][]float64, error) {
+  matrix, err := fromMatrix(m)
+  if err != nil {
+    return nil, err
+  }
+  if len(matrix) != len(matrix[0]) {
+    return nil, status.Errorf(codes.InvalidArgument, "Received a %vx%v matrix, expected a square one!", len(matrix), len(matrix[0]))
+  }
+  return matrix, nil
+}
+
+func toMatrix(matrix [][]float64) *calculatorpb.Matrix {
+  rows := make([]*calculatorpb.Vector, len(matrix))
+  for i, row := range matrix {
+    rows[i] = &calculatorpb.Vector{Values: row}
+  }
+  return &calculatorpb.Matrix{Rows: rows}
+}
@@ -0,0 +1,59 @@
+package calculatorservice
+
+import (
+  "context"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+func TestMatrixContextDone(t *testing.T) {
+  canceled, cancel := context.WithCancel(context.Background())
+  cancel()
+  expired, cancel := context.WithDeadline(context.Background(), time.Now())
+  defer cancel()
+
+  s := &Server{}
+  m := &calculatorpb.Matrix{Rows: []*calculatorpb.Vector{{Values: []float64{2, 1}}, {Values: []float64{1, 3}}}}
+  calls := map[string]func(ctx context.Context) error{
+    "MatrixAdd": func(ctx context.Context) error {
+      _, err := s.MatrixAdd(ctx, &calculatorpb.MatrixAddRequest{First: m, Second: m})
+      return err
+    },
+    "MatrixMultiply": func(ctx context.Context) error {
+      _, err := s.MatrixMultiply(ctx, &calculatorpb.MatrixMultiplyRequest{First: m, Second: m})
+      return err
+    },
+    "MatrixTranspose": func(ctx context.Context) error {
+      _, err := s.MatrixTranspose(ctx, &calculatorpb.MatrixTransposeRequest{Matrix: m})
+      return err
+    },
+    "MatrixDeterminant": func(ctx context.Context) error {
+      _, err := matrixDeterminant(ctx, &calculatorpb.MatrixDeterminantRequest{Matrix: m})
+      return err
+    },
+    "MatrixInverse": func(ctx context.Context) error {
+      _, err := matrixInverse(ctx, &calculatorpb.MatrixInverseRequest{Matrix: m})
+      return err
+    },
+    "SolveLinearSystem": func(ctx context.Context) error {
+      _, err := solveLinearSystem(ctx, &calculatorpb.SolveLinearSystemRequest{Coefficients: m, Constants: &calculatorpb.Vector{Values: []float64{3, 5}}})
+      return err
+    },
+  }
+  for name, call := range calls {
+    if err := call(context.Background()); err != nil {
+      t.Errorf("%v() = %v", name, err)
+    }
+    if err := call(canceled); status.Code(err) != codes.Canceled {
+      t.Errorf("%v() canceled = %v, want Canceled", name, err)
+    }
+    if err := call(expired); status.Code(err) != codes.DeadlineExceeded {
+      t.Errorf("%v() past its deadline = %v, want DeadlineExceeded", name, err)
+    }
+  }
+}
@@ -0,0 +1,323 @@
+// Package calculatorservice implements the CalculatorService, run by the calculator
+// server and started in-process by package grpctest.
+package calculatorservice
+
+import (
+  "context"
+  "fmt"
+  "io"
+  "math"
+  "math/cmplx"
+  "strconv"
+  "sync/atomic"
+
+  "github.com/_dev/grpc-go-example/cache"
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/clock"
+  "github.com/_dev/grpc-go-example/dedup"
+  "github.com/_dev/grpc-go-example/logging"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// Server answers the calls of the CalculatorService.
+type Server struct {
+  calculatorpb.UnimplementedCalculatorServiceServer
+  live     atomic.Value // *settings, replaced whole when the configuration is reloaded.
+  sessions *sessionStore
+  history  *historyStore // nil when history is disabled.
+  cache    *resultCache  // nil when caching is disabled.
+  flights  dedup.Group   // concurrent identical expensive requests.
+}
+
+// NewServer returns a server running with cfg, with its history store open when cfg has
+// one. Close releases them. The sessions and the cached results expire and the history is
+// timed by clk, the real clock when nil.
+func NewServer(cfg Config, clk clock.Clock) (*Server, error) {
+  clk = clock.Or(clk)
+  s := &Server{
+    sessions: newSessionStore(cfg.SessionTTL, cfg.MaxSessions, clk),
+  }
+  if cfg.Cache.Size > 0 {
+    s.cache = newResultCache(cache.New(cfg.Cache.Size, cfg.Cache.TTL, clk))
+  }
+  if cfg.History.DB != "" {
+    history, err := openHistoryStore(cfg.History.DB, retentionPolicy{
+      maxAge:     cfg.History.MaxAge,
+      maxEntries: cfg.History.MaxEntries,
+    }, clk)
+    if err != nil {
+      s.Close()
+      return nil, fmt.Errorf("failed to open history: %v", err)
+    }
+    s.history = history
+  }
+  if err := s.Apply(cfg); err != nil {
+    s.Close()
+    return nil, err
+  }
+  return s, nil
+}
+
+// Apply replaces the live settings of the server, as when the configuration is reloaded.
+func (s *Server) Apply(cfg Config) error {
+  st, err := newSettings(cfg)
+  if err != nil {
+    return err
+  }
+  s.cache.setEnabled(cfg.Features.Cache)
+  s.live.Store(st)
+  return nil
+}
+
+// Close stops the expiry of the sessions and closes the history store.
+func (s *Server) Close() error {
+  s.sessions.stop()
+  if s.history != nil {
+    return s.history.Close()
+  }
+  return nil
+}
+
+// UnaryInterceptors are to be installed on the gRPC server running s, recording the calls
+// in the history and answering the calls of the disabled methods.
+func (s *Server) UnaryInterceptors() []grpc.UnaryServerInterceptor {
+  var interceptors []grpc.UnaryServerInterceptor
+  if s.history != nil {
+    interceptors = append(interceptors, s.history.UnaryInterceptor)
+  }
+  return append(interceptors, s.unaryToggle)
+}
+
+// StreamInterceptors are the stream counterparts of UnaryInterceptors.
+func (s *Server) StreamInterceptors() []grpc.StreamServerInterceptor {
+  var interceptors []grpc.StreamServerInterceptor
+  if s.history != nil {
+    interceptors = append(interceptors, s.history.StreamInterceptor)
+  }
+  return append(interceptors, s.streamToggle)
+}
+
+// CacheStats returns the statistics of the result cache, zero when caching is disabled.
+func (s *Server) CacheStats() CacheStats {
+  if s.cache == nil {
+    return CacheStats{}
+  }
+  return s.cache.stats()
+}
+
+func (*Server) Sum(ctx context.Context, req *calculatorpb.SumRequest) (*calculatorpb.SumResponse, error) {
+  logging.Infof("Received Sum RPC: %v\n", req)
+  firstNumber := req.FirstNumber
+  secondNumber := req.SecondNumber
+  sum := firstNumber + secondNumber
+  res := &calculatorpb.SumResponse{
+    SumResult: sum,
+  }
+  return res, nil
+}
+
+func (s *Server) PrimeNumberDecomposition(req *calculatorpb.PrimeNumberDecompositionRequest, stream calculatorpb.CalculatorService_PrimeNumberDecompositionServer) error {
+  logging.Infof("Received PrimeNumberDecomposition RPC: %v\n", req)
+  send := func(factor interface{}) error {
+    return stream.Send(&calculatorpb.PrimeNumberDecompositionResponse{
+      PrimeFactor: factor.(int64),
+    })
+  }
+  if factors, ok := s.cache.get(stream.Context(), "PrimeNumberDecomposition", req); ok {
+    for _, factor := range factors.([]int64) {
+      if err := send(factor); err != nil {
+        return err
+      }
+    }
+    return nil
+  }
+
+  // Concurrent requests for the same number share a single decomposition.
+  key := "PrimeNumberDecomposition/" + strconv.FormatInt(req.GetNumber(), 10)
+  shared, err := s.flights.Do(stream.Context(), key, func(ctx context.Context, emit func(interface{})) error {
+    factors, err := primeFactors(ctx, req.GetNumber(), emit)
+    if err != nil {
+      return err
+    }
+    s.cache.add("PrimeNumberDecomposition", req, factors)
+    return nil
+  }, send)
+  if shared {
+    logging.Infof("PrimeNumberDecomposition of %v was shared with a concurrent request.\n", req.GetNumber())
+  }
+  return contextStatus(err)
+}
+
+// primeFactors emits the prime factors of number as they are found, it stops when ctx is done.
+func primeFactors(ctx context.Context, number int64, emit func(interface{})) ([]int64, error) {
+  divisor := int64(2)
+  var factors []int64
+
+  for number > 1 {
+    if err := ctx.Err(); err != nil {
+      return nil, err
+    }
+    if number%divisor == 0 {
+      emit(divisor)
+      factors = append(factors, divisor)
+      number = number / divisor
+    } else {
+      divisor++
+      logging.Debugf("Divisor has increased to %v\n", divisor)
+    }
+  }
+  return factors, nil
+}
+
+func (*Server) ComputeAverage(stream calculatorpb.CalculatorService_ComputeAverageServer) error {
+  logging.Infof("Received ComputeAverage RPC.")
+
+  sum := int32(0)
+  count := 0
+  for {
+    req, err := stream.Recv()
+    if err == io.EOF {
+      average := float64(sum) / float64(count)
+      return stream.SendAndClose(&calculatorpb.ComputeAverageResponse{
+        Average: average,
+      })
+    }
+    if err != nil {
+      logging.Errorf("Error while reading client stream: %v", err)
+      return err
+    }
+    sum += req.GetNumber()
+    count++
+  }
+}
+
+func (*Server) FindMaximum(stream calculatorpb.CalculatorService_FindMaximumServer) error {
+  logging.Infof("Received FindMaximum RPC.")
+
+  maximum := int32(0)
+  for {
+    req, err := stream.Recv()
+    if err == io.EOF {
+      return nil
+    }
+    if err != nil {
+      logging.Errorf("Error while reading client stream: %v", err)
+      return err
+    }
+    number := req.GetNumber()
+    if number > maximum {
+      maximum = number
+      err = stream.Send(&calculatorpb.FindMaximumResponse{
+        Maximum: maximum,
+      })
+      if err != nil {
+        logging.Errorf("Error while sending client stream: %v", err)
+        return err
+      }
+    }
+  }
+}
+
+func (s *Server) SquareRoot(stream calculatorpb.CalculatorService_SquareRootServer) error {
+  logging.Infof("Received SquareRoot RPC.")
+
+  for {
+    req, err := stream.Recv()
+    if err == io.EOF {
+      return nil
+    }
+    if err != nil {
+      logging.Errorf("Error while reading client stream: %v", err)
+      return err
+    }
+    err = stream.Send(s.cachedSquareRoot(stream.Context(), req))
+    if err != nil {
+      logging.Errorf("Error while sending client stream: %v", err)
+      return err
+    }
+  }
+}
+
+func (s *Server) SquareRootUnary(ctx context.Context, req *calculatorpb.SquareRootRequest) (*calculatorpb.SquareRootResponse, error) {
+  logging.Infof("Received SquareRootUnary RPC.")
+
+  res := s.cachedSquareRoot(ctx, req)
+  if e := res.GetError(); e != nil {
+    return nil, status.Error(codes.Code(e.GetCode()), e.GetMessage())
+  }
+  return res, nil
+}
+
+// cachedSquareRoot answers both SquareRoot variants from the same cache entries.
+func (s *Server) cachedSquareRoot(ctx context.Context, req *calculatorpb.SquareRootRequest) *calculatorpb.SquareRootResponse {
+  if req.GetAllowComplex() && !s.settings().cfg.Features.ComplexResults {
+    req = &calculatorpb.SquareRootRequest{Number: req.GetNumber()}
+  }
+  if res, ok := s.cache.get(ctx, "SquareRoot", req); ok {
+    return res.(*calculatorpb.SquareRootResponse)
+  }
+  res := squareRoot(req.GetNumber(), req.GetAllowComplex())
+  s.cache.add("SquareRoot", req, res)
+  return res
+}
+
+// squareRoot answers a single number, negative numbers are answered with an InvalidArgument error
+// unless allowComplex is set, in which case they are answered with a complex root.
+func squareRoot(number int32, allowComplex bool) *calculatorpb.SquareRootResponse {
+  if number < 0 && allowComplex {
+    return &calculatorpb.SquareRootResponse{
+      Number: number,
+      Result: &calculatorpb.SquareRootResponse_ComplexRoot{
+        ComplexRoot: toComplex(cmplx.Sqrt(complex(float64(number), 0))),
+      },
+    }
+  }
+  if number < 0 {
+    return &calculatorpb.SquareRootResponse{
+      Number: number,
+      Result: &calculatorpb.SquareRootResponse_Error{
+        Error: &calculatorpb.SquareRootError{
+          Code:    int32(codes.InvalidArgument),
+          Message: fmt.Sprintf("Received a negative number: %v!", number),
+        },
+      },
+    }
+  }
+  return &calculatorpb.SquareRootResponse{
+    Number: number,
+    Result: &calculatorpb.SquareRootResponse_NumberRoot{
+      NumberRoot: math.Sqrt(float64(number)),
+    },
+  }
+}
+
+// contextStatus turns the errors of a done context into their matching status.
+func contextStatus(err error) error {
+  if err == context.Canceled || err == context.DeadlineExceeded {
+    return status.FromContextError(err).Err()
+  }
+  return err
+}
+
+func (s *Server) settings() *settings {
+  return s.live.Load().(*settings)
+}
+
+// unaryToggle answers the calls of the disabled methods with Unimplemented.
+func (s *Server) unaryToggle(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  if s.settings().disabled[info.FullMethod] {
+    return nil, status.Errorf(codes.Unimplemented, "%v is disabled!", info.FullMethod)
+  }
+  return handler(ctx, req)
+}
+
+// streamToggle answers the streams of the disabled methods with Unimplemented.
+func (s *Server) streamToggle(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  if s.settings().disabled[info.FullMethod] {
+    return status.Errorf(codes.Unimplemented, "%v is disabled!", info.FullMethod)
+  }
+  return handler(srv, ss)
+}
@@ -0,0 +1,158 @@
+package calculatorservice
+
+import (
+  "context"
+  "reflect"
+  "strconv"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/dedup"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// waitForFlight waits for n callers to share the computation of key.
+func waitForFlight(t *testing.T, flights *dedup.Group, key string, n int) {
+  t.Helper()
+  for start := time.Now(); flights.Waiters(key) != n; time.Sleep(time.Millisecond) {
+    if time.Since(start) > 10*time.Second {
+      t.Fatalf("Waiters() = %v, want %v", flights.Waiters(key), n)
+    }
+  }
+}
+
+// factorStream collects the factors sent on a PrimeNumberDecomposition stream.
+type factorStream struct {
+  grpc.ServerStream
+  ctx     context.Context
+  factors chan int64
+}
+
+func (s *factorStream) Context() context.Context {
+  return s.ctx
+}
+
+func (s *factorStream) Send(res *calculatorpb.PrimeNumberDecompositionResponse) error {
+  s.factors <- res.GetPrimeFactor()
+  return nil
+}
+
+func decompose(s *Server, ctx context.Context, number int64) (*factorStream, chan error) {
+  stream := &factorStream{ctx: ctx, factors: make(chan int64, 64)}
+  done := make(chan error, 1)
+  go func() {
+    done <- s.PrimeNumberDecomposition(&calculatorpb.PrimeNumberDecompositionRequest{Number: number}, stream)
+  }()
+  return stream, done
+}
+
+func TestPrimeNumberDecompositionLeaderCancels(t *testing.T) {
+  s := &Server{}
+  // The second factor takes the decomposition a while to find.
+  number := int64(2 * 3000017)
+  key := "PrimeNumberDecomposition/" + strconv.FormatInt(number, 10)
+
+  leaderCtx, cancel := context.WithCancel(context.Background())
+  leader, leaderDone := decompose(s, leaderCtx, number)
+  if factor := <-leader.factors; factor != 2 {
+    t.Fatalf("First factor = %v, want 2", factor)
+  }
+  follower, followerDone := decompose(s, context.Background(), number)
+  waitForFlight(t, &s.flights, key, 2)
+  cancel()
+  if err := <-leaderDone; status.Code(err) != codes.Canceled {
+    t.Fatalf("PrimeNumberDecomposition() of the leader = %v, want Canceled", err)
+  }
+
+  // The follower joined the decomposition of the leader, which went on without it.
+  if err := <-followerDone; err != nil {
+    t.Fatalf("PrimeNumberDecomposition() of the follower = %v", err)
+  }
+  close(follower.factors)
+  var factors []int64
+  for factor := range follower.factors {
+    factors = append(factors, factor)
+  }
+  if want := []int64{2, 3000017}; !reflect.DeepEqual(factors, want) {
+    t.Fatalf("Follower got the factors %v, want %v", factors, want)
+  }
+}
+
+func TestPrimeNumberDecompositionLastWaiterCancels(t *testing.T) {
+  s := &Server{}
+  // The second factor is too large to be found during the test.
+  number := int64(2 * 1000000000039)
+  key := "PrimeNumberDecomposition/" + strconv.FormatInt(number, 10)
+
+  var cancels []context.CancelFunc
+  var dones []chan error
+  for i := 0; i < 2; i++ {
+    ctx, cancel := context.WithCancel(context.Background())
+    _, done := decompose(s, ctx, number)
+    waitForFlight(t, &s.flights, key, i+1)
+    cancels = append(cancels, cancel)
+    dones = append(dones, done)
+  }
+  for i, cancel := range cancels {
+    cancel()
+    if err := <-dones[i]; status.Code(err) != codes.Canceled {
+      t.Fatalf("PrimeNumberDecomposition() %v = %v, want Canceled", i, err)
+    }
+  }
+  if n := s.flights.Waiters(key); n != 0 {
+    t.Fatalf("Waiters() = %v once every caller left, want 0", n)
+  }
+}
+
+func TestMatrixDeterminantLeaderCancels(t *testing.T) {
+  s := &Server{}
+  // A large matrix, dominated by its diagonal, takes the elimination a while.
+  const n = 500
+  m := &calculatorpb.Matrix{}
+  for i := 0; i < n; i++ {
+    row := make([]float64, n)
+    for j := range row {
+      row[j] = float64((i*j)%7) / 7
+    }
+    row[i] = n
+    m.Rows = append(m.Rows, &calculatorpb.Vector{Values: row})
+  }
+  req := &calculatorpb.MatrixDeterminantRequest{Matrix: m}
+  want, err := matrixDeterminant(context.Background(), req)
+  if err != nil {
+    t.Fatal(err)
+  }
+  key, err := cacheKey("MatrixDeterminant", req)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  type result struct {
+    res *calculatorpb.MatrixDeterminantResponse
+    err error
+  }
+  call := func(ctx context.Context) chan result {
+    results := make(chan result, 1)
+    go func() {
+      res, err := s.MatrixDeterminant(ctx, req)
+      results <- result{res, err}
+    }()
+    return results
+  }
+  leaderCtx, cancel := context.WithCancel(context.Background())
+  leader := call(leaderCtx)
+  waitForFlight(t, &s.flights, key, 1)
+  follower := call(context.Background())
+  waitForFlight(t, &s.flights, key, 2)
+  cancel()
+  if r := <-leader; status.Code(r.err) != codes.Canceled {
+    t.Fatalf("MatrixDeterminant() of the leader = %v, want Canceled", r.err)
+  }
+  if r := <-follower; r.err != nil || r.res.GetDeterminant() != want.GetDeterminant() {
+    t.Fatalf("MatrixDeterminant() of the follower = %v, %v, want %v", r.res, r.err, want)
+  }
+}
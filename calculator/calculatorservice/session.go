@@ -0,0 +1,254 @@
+package calculatorservice
+
+import (
+  "context"
+  "crypto/rand"
+  "encoding/hex"
+  "io"
+  "sync"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/clock"
+  "github.com/_dev/grpc-go-example/logging"
+
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// session keeps the variables and results of a sequence of evaluations.
+type session struct {
+  mu        sync.Mutex
+  variables map[string]float64
+  results   []float64
+  lastUsed  time.Time // guarded by the store mutex.
+}
+
+func (s *session) variable(name string) (float64, error) {
+  if name == "ans" {
+    if len(s.results) == 0 {
+      return 0, status.Error(codes.NotFound, "There is no previous result!")
+    }
+    return s.results[len(s.results)-1], nil
+  }
+  value, ok := s.variables[name]
+  if !ok {
+    return 0, status.Errorf(codes.NotFound, "Unknown variable %v!", name)
+  }
+  return value, nil
+}
+
+func (s *session) result(index int) (float64, error) {
+  if index < 1 || index > len(s.results) {
+    return 0, status.Errorf(codes.NotFound, "Unknown result $%v!", index)
+  }
+  return s.results[index-1], nil
+}
+
+// evaluate runs a line of the session, storing its result and the assigned variable if any.
+func (s *session) evaluate(ctx context.Context, line string) (*calculatorpb.EvaluateResponse, error) {
+  s.mu.Lock()
+  defer s.mu.Unlock()
+
+  stmt, err := parseStatement(line)
+  if err != nil {
+    return nil, err
+  }
+  value, err := evaluate(ctx, stmt.expression, s)
+  if err != nil {
+    return nil, err
+  }
+  if stmt.assignment != "" {
+    s.variables[stmt.assignment] = value
+  }
+  s.results = append(s.results, value)
+  return &calculatorpb.EvaluateResponse{
+    Result: value,
+    Index:  int32(len(s.results)),
+  }, nil
+}
+
+// sessionStore holds the live sessions, expired ones are removed on access and by a janitor.
+type sessionStore struct {
+  mu       sync.Mutex
+  ttl      time.Duration
+  max      int // 0 for no limit.
+  sessions map[string]*session
+  clock    clock.Clock
+  done     chan struct{} // closed to stop the janitor.
+}
+
+func newSessionStore(ttl time.Duration, max int, clk clock.Clock) *sessionStore {
+  store := &sessionStore{
+    ttl:      ttl,
+    max:      max,
+    clock:    clk,
+    sessions: make(map[string]*session),
+    done:     make(chan struct{}),
+  }
+  go store.janitor()
+  return store
+}
+
+func (st *sessionStore) create() (string, error) {
+  buf := make([]byte, 16)
+  if _, err := rand.Read(buf); err != nil {
+    return "", status.Errorf(codes.Internal, "Could not create session: %v", err)
+  }
+  id := hex.EncodeToString(buf)
+
+  st.mu.Lock()
+  defer st.mu.Unlock()
+  if st.max > 0 && len(st.sessions) >= st.max {
+    // The sessions expired since the janitor last ran make room first.
+    st.removeExpired()
+    if len(st.sessions) >= st.max {
+      return "", status.Errorf(codes.ResourceExhausted, "Too many sessions, at most %v are kept: close one or wait for one to expire!", st.max)
+    }
+  }
+  st.sessions[id] = &session{
+    variables: make(map[string]float64),
+    lastUsed:  st.clock.Now(),
+  }
+  return id, nil
+}
+
+func (st *sessionStore) close(id string) error {
+  st.mu.Lock()
+  defer st.mu.Unlock()
+  if _, ok := st.sessions[id]; !ok {
+    return status.Errorf(codes.NotFound, "Session %v not found or expired!", id)
+  }
+  delete(st.sessions, id)
+  return nil
+}
+
+// get returns a live session, refreshing its expiry.
+func (st *sessionStore) get(id string) (*session, error) {
+  st.mu.Lock()
+  defer st.mu.Unlock()
+  s, ok := st.sessions[id]
+  if !ok || st.clock.Since(s.lastUsed) > st.ttl {
+    delete(st.sessions, id)
+    return nil, status.Errorf(codes.NotFound, "Session %v not found or expired!", id)
+  }
+  s.lastUsed = st.clock.Now()
+  return s, nil
+}
+
+func (st *sessionStore) janitor() {
+  ticker := st.clock.NewTicker(st.ttl / 2)
+  defer ticker.Stop()
+  for {
+    select {
+    case <-ticker.C():
+    case <-st.done:
+      return
+    }
+    st.mu.Lock()
+    st.removeExpired()
+    st.mu.Unlock()
+  }
+}
+
+// removeExpired deletes the sessions unused for longer than the ttl, st.mu held.
+func (st *sessionStore) removeExpired() {
+  for id, s := range st.sessions {
+    if st.clock.Since(s.lastUsed) > st.ttl {
+      delete(st.sessions, id)
+    }
+  }
+}
+
+func (st *sessionStore) stop() {
+  close(st.done)
+}
+
+func (s *Server) CreateSession(ctx context.Context, req *calculatorpb.CreateSessionRequest) (*calculatorpb.CreateSessionResponse, error) {
+  logging.Infof("Received CreateSession RPC.")
+  id, err := s.sessions.create()
+  if err != nil {
+    return nil, err
+  }
+  return &calculatorpb.CreateSessionResponse{
+    SessionId:  id,
+    TtlSeconds: int64(s.sessions.ttl / time.Second),
+  }, nil
+}
+
+func (s *Server) CloseSession(ctx context.Context, req *calculatorpb.CloseSessionRequest) (*calculatorpb.CloseSessionResponse, error) {
+  logging.Infof("Received CloseSession RPC: %v\n", req)
+  if err := s.sessions.close(req.GetSessionId()); err != nil {
+    return nil, err
+  }
+  return &calculatorpb.CloseSessionResponse{}, nil
+}
+
+func (s *Server) Evaluate(ctx context.Context, req *calculatorpb.EvaluateRequest) (*calculatorpb.EvaluateResponse, error) {
+  logging.Infof("Received Evaluate RPC: %v\n", req)
+  sess, err := s.sessions.get(req.GetSessionId())
+  if err != nil {
+    return nil, err
+  }
+  return sess.evaluate(ctx, req.GetExpression())
+}
+
+func (s *Server) Session(stream calculatorpb.CalculatorService_SessionServer) error {
+  logging.Infof("Received Session RPC.")
+
+  id := ""
+  for {
+    req, err := stream.Recv()
+    if err == io.EOF {
+      return nil
+    }
+    if err != nil {
+      logging.Errorf("Error while reading client stream: %v", err)
+      return err
+    }
+
+    if id == "" {
+      id = req.GetSessionId()
+      if id == "" {
+        id, err = s.sessions.create()
+        if err != nil {
+          return err
+        }
+      }
+    }
+
+    // A session that is gone ends the stream, errors in the expression do not.
+    sess, err := s.sessions.get(id)
+    if err != nil {
+      return err
+    }
+
+    res := &calculatorpb.SessionResponse{
+      SessionId:  id,
+      Expression: req.GetExpression(),
+    }
+    evaluation, err := sess.evaluate(stream.Context(), req.GetExpression())
+    if code := status.Code(err); code == codes.Canceled || code == codes.DeadlineExceeded {
+      return err
+    }
+    if err != nil {
+      st := status.Convert(err)
+      res.Result = &calculatorpb.SessionResponse_Error{
+        Error: &calculatorpb.SessionError{
+          Code:    int32(st.Code()),
+          Message: st.Message(),
+        },
+      }
+    } else {
+      res.Result = &calculatorpb.SessionResponse_Evaluation{
+        Evaluation: evaluation,
+      }
+    }
+
+    err = stream.Send(res)
+    if err != nil {
+      logging.Errorf("Error while sending client stream: %v", err)
+      return err
+    }
+  }
+}
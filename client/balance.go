@@ -0,0 +1,105 @@
+package client
+
+import (
+  "encoding/json"
+  "fmt"
+  "strings"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/balancer/leastrequest"
+  "google.golang.org/grpc/balancer/roundrobin"
+  "google.golang.org/grpc/resolver"
+  "google.golang.org/grpc/resolver/manual"
+
+  // Registering the client side of the health checks.
+  _ "google.golang.org/grpc/health"
+)
+
+// The balancing policies spreading the calls over the backends.
+const (
+  RoundRobin   = roundrobin.Name
+  LeastRequest = leastrequest.Name
+)
+
+// Policies names the balancing policies for the flags of the clients.
+var Policies = map[string]string{
+  "round-robin":   RoundRobin,
+  "least-request": LeastRequest,
+}
+
+// BalanceConfig tells how the calls are spread over the backends of a connection.
+type BalanceConfig struct {
+  // Policy is RoundRobin or LeastRequest.
+  Policy string
+
+  // HealthCheck leaves out the backends whose gRPC health service does not report
+  // HealthService as serving, "" standing for the whole server.
+  HealthCheck   bool
+  HealthService string
+}
+
+// WithServiceConfig enables the retries of r and the balancing of b, those not nil,
+// through the gRPC service config.
+func WithServiceConfig(r *RetryConfig, b *BalanceConfig) (grpc.DialOption, error) {
+  sc, err := serviceConfig(r, b)
+  if err != nil {
+    return nil, err
+  }
+  return grpc.WithDefaultServiceConfig(sc), nil
+}
+
+func serviceConfig(r *RetryConfig, b *BalanceConfig) (string, error) {
+  type healthCheckConfig struct {
+    ServiceName string `json:"serviceName"`
+  }
+  var sc struct {
+    LoadBalancingConfig []map[string]struct{} `json:"loadBalancingConfig,omitempty"`
+    HealthCheckConfig   *healthCheckConfig    `json:"healthCheckConfig,omitempty"`
+    MethodConfig        []methodConfig        `json:"methodConfig,omitempty"`
+  }
+
+  if r != nil {
+    configs, err := r.methodConfigs()
+    if err != nil {
+      return "", err
+    }
+    sc.MethodConfig = configs
+  }
+  if b != nil {
+    if b.Policy != RoundRobin && b.Policy != LeastRequest {
+      return "", fmt.Errorf("unknown balancing policy %q", b.Policy)
+    }
+    sc.LoadBalancingConfig = []map[string]struct{}{{b.Policy: {}}}
+    if b.HealthCheck {
+      sc.HealthCheckConfig = &healthCheckConfig{ServiceName: b.HealthService}
+    }
+  }
+
+  bytes, err := json.Marshal(sc)
+  return string(bytes), err
+}
+
+// Target turns the backends given to a client into a gRPC target. The backends are
+// either a target with a scheme, as in "dns:///calculator:50051" whose addresses are
+// all balanced over or "file:///etc/greet/endpoints.json" (see FileScheme), or a static
+// list of addresses separated by commas.
+func Target(backends string) (string, []grpc.DialOption, error) {
+  if strings.Contains(backends, "://") {
+    return backends, nil, nil
+  }
+
+  var addrs []resolver.Address
+  for _, addr := range strings.Split(backends, ",") {
+    if addr = strings.TrimSpace(addr); addr != "" {
+      addrs = append(addrs, resolver.Address{Addr: addr})
+    }
+  }
+  if len(addrs) == 0 {
+    return "", nil, fmt.Errorf("no backend in %q", backends)
+  }
+
+  // A resolver of its own for each connection, always answering the same addresses.
+  r := manual.NewBuilderWithScheme("static")
+  r.InitialState(resolver.State{Addresses: addrs})
+  return r.Scheme() + ":///backends", []grpc.DialOption{grpc.WithResolvers(r)}, nil
+}
@@ -0,0 +1,282 @@
+package client_test
+
+import (
+  "context"
+  "fmt"
+  "net"
+  "os"
+  "path/filepath"
+  "strings"
+  "sync/atomic"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/calculator/calculatorservice"
+  "github.com/_dev/grpc-go-example/client"
+  "github.com/_dev/grpc-go-example/grpctest"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/health"
+  healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const calculatorService = "calculator.CalculatorService"
+
+// backend is a calculator server listening on the loopback, counting the calls it
+// answers.
+type backend struct {
+  addr    string
+  health  *health.Server
+  calls   atomic.Int64
+  streams atomic.Int64
+}
+
+func (b *backend) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  b.calls.Add(1)
+  return handler(ctx, req)
+}
+
+func (b *backend) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  b.streams.Add(1)
+  return handler(srv, ss)
+}
+
+// startBackends starts n calculator servers, stopped when the test ends.
+func startBackends(t *testing.T, n int) []*backend {
+  backends := make([]*backend, n)
+  for i := range backends {
+    lis, err := net.Listen("tcp", "127.0.0.1:0")
+    if err != nil {
+      t.Fatalf("Failed to listen: %v", err)
+    }
+    srv, err := calculatorservice.NewServer(grpctest.CalculatorConfig(), nil)
+    if err != nil {
+      t.Fatal(err)
+    }
+    b := &backend{addr: lis.Addr().String(), health: health.NewServer()}
+    s := grpc.NewServer(
+      grpc.ChainUnaryInterceptor(append(srv.UnaryInterceptors(), b.unary)...),
+      grpc.ChainStreamInterceptor(append(srv.StreamInterceptors(), b.stream)...),
+    )
+    calculatorpb.RegisterCalculatorServiceServer(s, srv)
+    b.health.SetServingStatus(calculatorService, healthpb.HealthCheckResponse_SERVING)
+    healthpb.RegisterHealthServer(s, b.health)
+    go s.Serve(lis)
+    t.Cleanup(func() {
+      s.Stop()
+      srv.Close()
+    })
+    backends[i] = b
+  }
+  return backends
+}
+
+func addrs(backends []*backend) string {
+  var list []string
+  for _, b := range backends {
+    list = append(list, b.addr)
+  }
+  return strings.Join(list, ",")
+}
+
+// dialBackends connects to backends, a target given to client.Target, balancing as b
+// tells.
+func dialBackends(t *testing.T, backends string, b client.BalanceConfig) calculatorpb.CalculatorServiceClient {
+  target, opts, err := client.Target(backends)
+  if err != nil {
+    t.Fatal(err)
+  }
+  sc, err := client.WithServiceConfig(nil, &b)
+  if err != nil {
+    t.Fatal(err)
+  }
+  cc, err := grpc.Dial(target, append(opts, sc, grpc.WithInsecure())...)
+  if err != nil {
+    t.Fatalf("Failed to connect: %v", err)
+  }
+  t.Cleanup(func() { cc.Close() })
+  return calculatorpb.NewCalculatorServiceClient(cc)
+}
+
+// sums calls Sum n times, failing the test on the first error.
+func sums(t *testing.T, c calculatorpb.CalculatorServiceClient, n int) {
+  t.Helper()
+  for i := 0; i < n; i++ {
+    if _, err := c.Sum(context.Background(), &calculatorpb.SumRequest{}, grpc.WaitForReady(true)); err != nil {
+      t.Fatalf("Sum() = %v", err)
+    }
+  }
+}
+
+// waitFor calls Sum until cond holds, failing the test after 5s.
+func waitFor(t *testing.T, c calculatorpb.CalculatorServiceClient, what string, cond func() bool) {
+  t.Helper()
+  deadline := time.Now().Add(5 * time.Second)
+  for !cond() {
+    if time.Now().After(deadline) {
+      t.Fatalf("Timed out waiting for %v", what)
+    }
+    sums(t, c, 1)
+    time.Sleep(time.Millisecond)
+  }
+}
+
+func reset(backends []*backend) {
+  for _, b := range backends {
+    b.calls.Store(0)
+  }
+}
+
+func TestRoundRobin(t *testing.T) {
+  backends := startBackends(t, 3)
+  c := dialBackends(t, addrs(backends), client.BalanceConfig{Policy: client.RoundRobin})
+
+  // Once connected to every backend, the calls go to each in turn.
+  waitFor(t, c, "the calls to reach every backend", func() bool {
+    for _, b := range backends {
+      if b.calls.Load() == 0 {
+        return false
+      }
+    }
+    return true
+  })
+  reset(backends)
+  sums(t, c, 30)
+  for i, b := range backends {
+    if got := b.calls.Load(); got != 10 {
+      t.Errorf("Backend %v answered %v calls out of 30, want 10", i, got)
+    }
+  }
+}
+
+func TestHealthCheck(t *testing.T) {
+  backends := startBackends(t, 2)
+  c := dialBackends(t, addrs(backends), client.BalanceConfig{
+    Policy:        client.RoundRobin,
+    HealthCheck:   true,
+    HealthService: calculatorService,
+  })
+  sick := backends[0]
+
+  sick.health.SetServingStatus(calculatorService, healthpb.HealthCheckResponse_NOT_SERVING)
+  waitFor(t, c, "the client to leave out the sick backend", func() bool {
+    before := sick.calls.Load()
+    sums(t, c, 4)
+    return sick.calls.Load() == before
+  })
+  reset(backends)
+  sums(t, c, 20)
+  if got := sick.calls.Load(); got != 0 {
+    t.Fatalf("The backend not serving answered %v calls, want 0", got)
+  }
+
+  // The backend serving again gets its share back.
+  sick.health.SetServingStatus(calculatorService, healthpb.HealthCheckResponse_SERVING)
+  waitFor(t, c, "the client to call the recovered backend", func() bool {
+    return sick.calls.Load() > 0
+  })
+}
+
+func TestLeastRequest(t *testing.T) {
+  backends := startBackends(t, 2)
+  c := dialBackends(t, addrs(backends), client.BalanceConfig{Policy: client.LeastRequest})
+  waitFor(t, c, "the calls to reach every backend", func() bool {
+    return backends[0].calls.Load() > 0 && backends[1].calls.Load() > 0
+  })
+
+  // A stream left open keeps a request outstanding on its backend.
+  ctx, cancel := context.WithCancel(context.Background())
+  defer cancel()
+  stream, err := c.FindMaximum(ctx)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if err := stream.Send(&calculatorpb.FindMaximumRequest{Number: 1}); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := stream.Recv(); err != nil {
+    t.Fatal(err)
+  }
+  busy, idle := backends[0], backends[1]
+  if idle.streams.Load() == 1 {
+    busy, idle = idle, busy
+  }
+
+  // Each call picks the least loaded of two backends drawn at random, possibly the same
+  // one twice: the idle backend gets 3 calls in 4, far more than the half of round robin.
+  reset(backends)
+  sums(t, c, 200)
+  if got := idle.calls.Load(); got < 120 {
+    t.Fatalf("The idle backend answered %v calls out of 200, want at least 120", got)
+  }
+}
+
+func TestFileResolver(t *testing.T) {
+  interval := client.FileWatchInterval
+  client.FileWatchInterval = 10 * time.Millisecond
+  defer func() { client.FileWatchInterval = interval }()
+
+  backends := startBackends(t, 2)
+  path := filepath.Join(t.TempDir(), "endpoints.json")
+  modTime := time.Now()
+  write := func(backends ...*backend) {
+    t.Helper()
+    content := fmt.Sprintf(`{"endpoints": [%q]}`, strings.ReplaceAll(addrs(backends), ",", `", "`))
+    if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+      t.Fatal(err)
+    }
+    // Dating each version apart, a rewrite of the same size within the same instant
+    // would go unnoticed.
+    modTime = modTime.Add(time.Second)
+    if err := os.Chtimes(path, modTime, modTime); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  write(backends[0])
+  c := dialBackends(t, "file://"+path, client.BalanceConfig{Policy: client.RoundRobin})
+  sums(t, c, 5)
+  if got := backends[1].calls.Load(); got != 0 {
+    t.Fatalf("The backend missing from the file answered %v calls, want 0", got)
+  }
+
+  // The connection follows the file.
+  write(backends[1])
+  waitFor(t, c, "the client to follow the endpoints file", func() bool {
+    before := backends[0].calls.Load()
+    sums(t, c, 4)
+    return backends[0].calls.Load() == before
+  })
+}
+
+func TestTarget(t *testing.T) {
+  for _, tc := range []struct {
+    backends string
+    target   string
+    static   bool
+    invalid  bool
+  }{
+    {backends: "dns:///calculator:50051", target: "dns:///calculator:50051"},
+    {backends: "file:///etc/greet/endpoints.json", target: "file:///etc/greet/endpoints.json"},
+    {backends: "localhost:50051, localhost:50052", static: true},
+    {backends: " , ", invalid: true},
+  } {
+    target, opts, err := client.Target(tc.backends)
+    if (err != nil) != tc.invalid {
+      t.Fatalf("Target(%q) = %v, want an error: %v", tc.backends, err, tc.invalid)
+    }
+    if tc.invalid {
+      continue
+    }
+    if tc.static {
+      if !strings.HasPrefix(target, "static:///") || len(opts) != 1 {
+        t.Errorf("Target(%q) = %q with %v options, want a static target with its resolver", tc.backends, target, len(opts))
+      }
+      continue
+    }
+    if target != tc.target || len(opts) != 0 {
+      t.Errorf("Target(%q) = %q with %v options, want %q", tc.backends, target, len(opts), tc.target)
+    }
+  }
+}
@@ -0,0 +1,304 @@
+package client
+
+import (
+  "context"
+  "io"
+  "sync"
+  "time"
+
+  "github.com/_dev/grpc-go-example/clock"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// BreakerState is the state of the circuit of a method.
+type BreakerState int
+
+const (
+  // Closed lets the calls through while counting their failures.
+  Closed BreakerState = iota
+  // Open fails the calls fast, until the cool-down is over.
+  Open
+  // HalfOpen lets a few probe calls through to decide whether to close again.
+  HalfOpen
+)
+
+func (s BreakerState) String() string {
+  switch s {
+  case Closed:
+    return "closed"
+  case Open:
+    return "open"
+  case HalfOpen:
+    return "half-open"
+  }
+  return "unknown"
+}
+
+// BreakerConfig tells when the circuit of a method opens and closes again.
+type BreakerConfig struct {
+  // The circuit opens when, within a Window, at least MinRequests calls were made
+  // and FailureRate of them (from 0 to 1) failed with one of the FailureCodes.
+  Window       time.Duration
+  MinRequests  int
+  FailureRate  float64
+  FailureCodes []codes.Code
+
+  // CoolDown is how long the circuit stays open before letting HalfOpenRequests
+  // probe calls through, which close it when all of them succeed.
+  CoolDown         time.Duration
+  HalfOpenRequests int
+
+  // OnStateChange, when set, is called in order on every change of state, it must
+  // not call back the breaker.
+  OnStateChange func(method string, from, to BreakerState)
+
+  // Clock times the windows and the cool-downs, the real clock when nil.
+  Clock clock.Clock
+}
+
+// DefaultBreakerConfig opens after half of at least 10 calls failed within 10 seconds.
+var DefaultBreakerConfig = BreakerConfig{
+  Window:           10 * time.Second,
+  MinRequests:      10,
+  FailureRate:      0.5,
+  FailureCodes:     []codes.Code{codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Internal, codes.Unknown},
+  CoolDown:         5 * time.Second,
+  HalfOpenRequests: 3,
+}
+
+// CircuitStats describes the circuit of a method.
+type CircuitStats struct {
+  State       string
+  Requests    int64 // calls let through.
+  Failures    int64
+  Rejected    int64 // calls failed fast.
+  Transitions int64
+}
+
+// Breaker keeps a circuit per method, calls on an open circuit fail fast with Unavailable.
+type Breaker struct {
+  cfg BreakerConfig
+
+  mu       sync.Mutex
+  circuits map[string]*circuit
+}
+
+type circuit struct {
+  state       BreakerState
+  generation  uint64 // changes with the state and the window, the results of older calls are ignored.
+  windowStart time.Time
+  requests    int // in the current window, or probes in flight when half-open.
+  failures    int
+  successes   int // of the probes when half-open.
+  openedAt    time.Time
+  stats       CircuitStats
+}
+
+// NewBreaker creates a breaker, see BreakerConfig.
+func NewBreaker(cfg BreakerConfig) *Breaker {
+  if cfg.HalfOpenRequests < 1 {
+    cfg.HalfOpenRequests = 1
+  }
+  cfg.Clock = clock.Or(cfg.Clock)
+  return &Breaker{
+    cfg:      cfg,
+    circuits: make(map[string]*circuit),
+  }
+}
+
+// WithBreaker installs the breaker on the unary calls and streams of a connection.
+func WithBreaker(b *Breaker) []grpc.DialOption {
+  return []grpc.DialOption{
+    grpc.WithChainUnaryInterceptor(b.UnaryInterceptor),
+    grpc.WithChainStreamInterceptor(b.StreamInterceptor),
+  }
+}
+
+// State returns the state of the circuit of method.
+func (b *Breaker) State(method string) BreakerState {
+  b.mu.Lock()
+  defer b.mu.Unlock()
+  if c, ok := b.circuits[method]; ok {
+    return c.state
+  }
+  return Closed
+}
+
+// Stats returns a snapshot of the circuits, by method.
+func (b *Breaker) Stats() map[string]CircuitStats {
+  b.mu.Lock()
+  defer b.mu.Unlock()
+  stats := make(map[string]CircuitStats, len(b.circuits))
+  for method, c := range b.circuits {
+    s := c.stats
+    s.State = c.state.String()
+    stats[method] = s
+  }
+  return stats
+}
+
+// UnaryInterceptor fails the calls fast while the circuit of their method is open.
+func (b *Breaker) UnaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+  generation, err := b.allow(method)
+  if err != nil {
+    return err
+  }
+  err = invoker(ctx, method, req, reply, cc, opts...)
+  b.done(method, generation, err)
+  return err
+}
+
+// StreamInterceptor fails the streams fast while the circuit of their method is open,
+// a stream counts as failed when it ends with one of the failure codes.
+func (b *Breaker) StreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+  generation, err := b.allow(method)
+  if err != nil {
+    return nil, err
+  }
+  cs, err := streamer(ctx, desc, cc, method, opts...)
+  if err != nil {
+    b.done(method, generation, err)
+    return nil, err
+  }
+  s := &breakerStream{
+    ClientStream:  cs,
+    serverStreams: desc.ServerStreams,
+    done:          func(err error) { b.done(method, generation, err) },
+  }
+  // A stream canceled, or past its deadline, may end without RecvMsg failing.
+  go func() {
+    <-cs.Context().Done()
+    if err := ctx.Err(); err != nil {
+      s.report(status.FromContextError(err).Err())
+    }
+  }()
+  return s, nil
+}
+
+// allow lets a call through unless the circuit of method says otherwise, returning the
+// generation of the circuit the call started in.
+func (b *Breaker) allow(method string) (uint64, error) {
+  b.mu.Lock()
+  defer b.mu.Unlock()
+  c, ok := b.circuits[method]
+  if !ok {
+    c = &circuit{windowStart: b.cfg.Clock.Now()}
+    b.circuits[method] = c
+  }
+
+  switch c.state {
+  case Open:
+    if b.cfg.Clock.Since(c.openedAt) < b.cfg.CoolDown {
+      c.stats.Rejected++
+      return 0, status.Errorf(codes.Unavailable, "Circuit breaker open for %v!", method)
+    }
+    b.transition(method, c, HalfOpen)
+    fallthrough
+  case HalfOpen:
+    if c.requests >= b.cfg.HalfOpenRequests {
+      c.stats.Rejected++
+      return 0, status.Errorf(codes.Unavailable, "Circuit breaker half-open for %v, waiting for the probes!", method)
+    }
+  case Closed:
+    if b.cfg.Clock.Since(c.windowStart) > b.cfg.Window {
+      c.windowStart = b.cfg.Clock.Now()
+      c.generation++
+      c.requests = 0
+      c.failures = 0
+    }
+  }
+  c.requests++
+  c.stats.Requests++
+  return c.generation, nil
+}
+
+// done counts the result of a call started in generation, unless the circuit changed
+// since: a call started while closed does not probe a half-open circuit, nor does a
+// late probe weigh on the circuit it closed.
+func (b *Breaker) done(method string, generation uint64, err error) {
+  failed := b.failure(err)
+  b.mu.Lock()
+  defer b.mu.Unlock()
+  c := b.circuits[method]
+  if failed {
+    c.stats.Failures++
+  }
+  if generation != c.generation {
+    return
+  }
+
+  switch c.state {
+  case Closed:
+    if failed {
+      c.failures++
+    }
+    if c.requests >= b.cfg.MinRequests && float64(c.failures) >= b.cfg.FailureRate*float64(c.requests) {
+      b.transition(method, c, Open)
+    }
+  case HalfOpen:
+    if failed {
+      b.transition(method, c, Open)
+      return
+    }
+    c.successes++
+    if c.successes >= b.cfg.HalfOpenRequests {
+      b.transition(method, c, Closed)
+    }
+  }
+}
+
+// transition resets the counters of c for its new state, b.mu must be held.
+func (b *Breaker) transition(method string, c *circuit, to BreakerState) {
+  from := c.state
+  c.state = to
+  c.generation++
+  c.requests = 0
+  c.failures = 0
+  c.successes = 0
+  c.windowStart = b.cfg.Clock.Now()
+  if to == Open {
+    c.openedAt = c.windowStart
+  }
+  c.stats.Transitions++
+  if b.cfg.OnStateChange != nil {
+    b.cfg.OnStateChange(method, from, to)
+  }
+}
+
+func (b *Breaker) failure(err error) bool {
+  if err == nil || err == io.EOF {
+    return false
+  }
+  code := status.Code(err)
+  for _, c := range b.cfg.FailureCodes {
+    if c == code {
+      return true
+    }
+  }
+  return false
+}
+
+// breakerStream reports the outcome of a stream once, when it ends: when RecvMsg fails,
+// when the single response of a client stream is received, or when the context of the
+// call is done.
+type breakerStream struct {
+  grpc.ClientStream
+  serverStreams bool
+  once          sync.Once
+  done          func(error)
+}
+
+func (s *breakerStream) RecvMsg(m interface{}) error {
+  err := s.ClientStream.RecvMsg(m)
+  if err != nil || !s.serverStreams {
+    s.report(err)
+  }
+  return err
+}
+
+func (s *breakerStream) report(err error) {
+  s.once.Do(func() { s.done(err) })
+}
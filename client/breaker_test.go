@@ -0,0 +1,254 @@
+package client_test
+
+import (
+  "context"
+  "sync/atomic"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/client"
+  "github.com/_dev/grpc-go-example/clock"
+  "github.com/_dev/grpc-go-example/grpctest"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+const computeAverage = "/calculator.CalculatorService/ComputeAverage"
+
+// failing answers every call with Unavailable while set.
+type failing struct {
+  on atomic.Bool
+}
+
+func (f *failing) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  if f.on.Load() {
+    return nil, status.Error(codes.Unavailable, "failing")
+  }
+  return handler(ctx, req)
+}
+
+func (f *failing) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  if f.on.Load() {
+    return status.Error(codes.Unavailable, "failing")
+  }
+  return handler(srv, ss)
+}
+
+// newBreakerCalculator serves the calculator behind f, with a breaker opening on the
+// first failure and closing on the first successful probe.
+func newBreakerCalculator(t *testing.T, f *failing) (calculatorpb.CalculatorServiceClient, *client.Breaker, *clock.Fake) {
+  clk := clock.NewFake(time.Now())
+  breaker := client.NewBreaker(client.BreakerConfig{
+    Window:           time.Minute,
+    MinRequests:      1,
+    FailureRate:      1,
+    FailureCodes:     []codes.Code{codes.Unavailable, codes.DeadlineExceeded},
+    CoolDown:         5 * time.Second,
+    HalfOpenRequests: 1,
+    Clock:            clk,
+  })
+  calc := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{
+    UnaryInterceptors:  []grpc.UnaryServerInterceptor{f.unary},
+    StreamInterceptors: []grpc.StreamServerInterceptor{f.stream},
+  })
+  conn := calc.Dial(t, client.WithBreaker(breaker)...)
+  return calculatorpb.NewCalculatorServiceClient(conn), breaker, clk
+}
+
+func computeAverageOf(ctx context.Context, c calculatorpb.CalculatorServiceClient, numbers ...int32) (*calculatorpb.ComputeAverageResponse, error) {
+  stream, err := c.ComputeAverage(ctx)
+  if err != nil {
+    return nil, err
+  }
+  for _, n := range numbers {
+    if err := stream.Send(&calculatorpb.ComputeAverageRequest{Number: n}); err != nil {
+      break // the error comes with CloseAndRecv.
+    }
+  }
+  return stream.CloseAndRecv()
+}
+
+// waitState polls the state of the circuit of method, set by the breaker once a stream
+// ended.
+func waitState(t *testing.T, b *client.Breaker, method string, want client.BreakerState) {
+  t.Helper()
+  deadline := time.Now().Add(5 * time.Second)
+  for b.State(method) != want {
+    if time.Now().After(deadline) {
+      t.Fatalf("State of %v = %v, want %v", method, b.State(method), want)
+    }
+    time.Sleep(time.Millisecond)
+  }
+}
+
+func TestBreakerUnary(t *testing.T) {
+  f := &failing{}
+  c, breaker, clk := newBreakerCalculator(t, f)
+  ctx := context.Background()
+
+  f.on.Store(true)
+  if _, err := c.Sum(ctx, &calculatorpb.SumRequest{}); status.Code(err) != codes.Unavailable {
+    t.Fatalf("Sum() = %v, want Unavailable", err)
+  }
+  if got := breaker.State(sum); got != client.Open {
+    t.Fatalf("State after a failure = %v, want open", got)
+  }
+
+  // The server recovered but the circuit fails the calls fast until the cool-down ends.
+  f.on.Store(false)
+  if _, err := c.Sum(ctx, &calculatorpb.SumRequest{}); status.Code(err) != codes.Unavailable {
+    t.Fatalf("Sum() on an open circuit = %v, want Unavailable", err)
+  }
+  clk.Advance(5 * time.Second)
+  res, err := c.Sum(ctx, &calculatorpb.SumRequest{FirstNumber: 3, SecondNumber: 4})
+  if err != nil || res.GetSumResult() != 7 {
+    t.Fatalf("Sum() probe = %v, %v, want 7", res, err)
+  }
+  if got := breaker.State(sum); got != client.Closed {
+    t.Fatalf("State after a successful probe = %v, want closed", got)
+  }
+}
+
+func TestBreakerClientStream(t *testing.T) {
+  f := &failing{}
+  c, breaker, clk := newBreakerCalculator(t, f)
+  ctx := context.Background()
+
+  f.on.Store(true)
+  if _, err := computeAverageOf(ctx, c, 1); status.Code(err) != codes.Unavailable {
+    t.Fatalf("ComputeAverage() = %v, want Unavailable", err)
+  }
+  waitState(t, breaker, computeAverage, client.Open)
+
+  // The probe succeeds with CloseAndRecv, RecvMsg never fails.
+  f.on.Store(false)
+  clk.Advance(5 * time.Second)
+  res, err := computeAverageOf(ctx, c, 2, 6)
+  if err != nil || res.GetAverage() != 4 {
+    t.Fatalf("ComputeAverage() probe = %v, %v, want 4", res, err)
+  }
+  waitState(t, breaker, computeAverage, client.Closed)
+  if _, err := computeAverageOf(ctx, c, 1); err != nil {
+    t.Fatalf("ComputeAverage() after the probe = %v", err)
+  }
+}
+
+func TestBreakerStreamDeadline(t *testing.T) {
+  f := &failing{}
+  c, breaker, clk := newBreakerCalculator(t, f)
+  const findMaximum = "/calculator.CalculatorService/FindMaximum"
+
+  f.on.Store(true)
+  stream, err := c.FindMaximum(context.Background())
+  if err != nil {
+    t.Fatal(err)
+  }
+  if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+    t.Fatalf("FindMaximum() = %v, want Unavailable", err)
+  }
+  waitState(t, breaker, findMaximum, client.Open)
+
+  // The probe runs out of time without its messages being read, the circuit opens again
+  // rather than waiting for it forever.
+  f.on.Store(false)
+  clk.Advance(5 * time.Second)
+  ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+  defer cancel()
+  if _, err := c.FindMaximum(ctx); err != nil {
+    t.Fatal(err)
+  }
+  waitState(t, breaker, findMaximum, client.Open)
+}
+
+// pending is a call to Sum through the breaker, answered with the error sent on result.
+type pending struct {
+  result chan error
+  err    chan error
+}
+
+func startSum(b *client.Breaker) *pending {
+  p := &pending{result: make(chan error), err: make(chan error, 1)}
+  started := make(chan struct{})
+  go func() {
+    p.err <- b.UnaryInterceptor(context.Background(), sum, nil, nil, nil,
+      func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+        close(started)
+        return <-p.result
+      })
+  }()
+  select {
+  case <-started:
+  case err := <-p.err:
+    p.err <- err // let through or not, the caller finds out with finish.
+  }
+  return p
+}
+
+// finish answers the call with err, returning what its caller got.
+func (p *pending) finish(err error) error {
+  select {
+  case p.result <- err:
+  case err := <-p.err:
+    return err
+  }
+  return <-p.err
+}
+
+func TestBreakerLateResults(t *testing.T) {
+  clk := clock.NewFake(time.Now())
+  b := client.NewBreaker(client.BreakerConfig{
+    Window:           time.Minute,
+    MinRequests:      1,
+    FailureRate:      0.1,
+    FailureCodes:     []codes.Code{codes.Unavailable},
+    CoolDown:         5 * time.Second,
+    HalfOpenRequests: 1,
+    Clock:            clk,
+  })
+  unavailable := status.Error(codes.Unavailable, "failing")
+
+  // Two calls are still running when a third one opens the circuit.
+  slowOK, slowFailing := startSum(b), startSum(b)
+  if err := startSum(b).finish(unavailable); status.Code(err) != codes.Unavailable {
+    t.Fatalf("Sum() = %v, want Unavailable", err)
+  }
+  if got := b.State(sum); got != client.Open {
+    t.Fatalf("State after a failure = %v, want open", got)
+  }
+
+  // Their results come while the probe runs, neither closes nor opens the circuit.
+  clk.Advance(5 * time.Second)
+  probe := startSum(b)
+  if got := b.State(sum); got != client.HalfOpen {
+    t.Fatalf("State while probing = %v, want half-open", got)
+  }
+  if err := slowOK.finish(nil); err != nil {
+    t.Fatalf("Sum() started while closed = %v", err)
+  }
+  if got := b.State(sum); got != client.HalfOpen {
+    t.Fatalf("State after a late success = %v, want half-open", got)
+  }
+  if err := slowFailing.finish(unavailable); status.Code(err) != codes.Unavailable {
+    t.Fatalf("Sum() started while closed = %v, want Unavailable", err)
+  }
+  if got := b.State(sum); got != client.HalfOpen {
+    t.Fatalf("State after a late failure = %v, want half-open", got)
+  }
+  if err := startSum(b).finish(nil); status.Code(err) != codes.Unavailable {
+    t.Fatalf("Sum() beside the probe = %v, want Unavailable", err)
+  }
+
+  // The probe alone closes it.
+  if err := probe.finish(nil); err != nil {
+    t.Fatalf("Sum() probe = %v", err)
+  }
+  if got := b.State(sum); got != client.Closed {
+    t.Fatalf("State after the probe = %v, want closed", got)
+  }
+  if s := b.Stats()[sum]; s.Requests != 4 || s.Failures != 2 || s.Rejected != 1 {
+    t.Errorf("Stats() = %+v, want 4 requests, 2 failures and 1 rejected", s)
+  }
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+  "context"
+  "time"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// PropagationConfig tells how a server calling another one passes on the deadline of the
+// call it serves, given as the context of the outbound calls:
+//
+//	res, err := calculator.Sum(ctx, req) // ctx of the greet handler.
+type PropagationConfig struct {
+  // Margin is taken off the time left to the incoming call, so that the handler still
+  // has time to answer once the outbound call ended.
+  Margin time.Duration
+
+  // MinBudget is the least time worth starting an outbound call with, the calls left
+  // with less fail at once with DeadlineExceeded instead of being sent.
+  MinBudget time.Duration
+}
+
+// WithDeadlinePropagation installs c on the unary calls and streams of a connection.
+func WithDeadlinePropagation(c PropagationConfig) []grpc.DialOption {
+  return []grpc.DialOption{
+    grpc.WithChainUnaryInterceptor(c.UnaryInterceptor),
+    grpc.WithChainStreamInterceptor(c.StreamInterceptor),
+  }
+}
+
+// UnaryInterceptor runs the calls with the deadline of their context less the margin, the
+// cancellation of the context still ends them.
+func (c PropagationConfig) UnaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+  ctx, cancel, err := c.budget(ctx, method)
+  if err != nil {
+    return err
+  }
+  defer cancel()
+  return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// StreamInterceptor is the stream counterpart of UnaryInterceptor.
+func (c PropagationConfig) StreamInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+  ctx, cancel, err := c.budget(ctx, method)
+  if err != nil {
+    return nil, err
+  }
+  cs, err := streamer(ctx, desc, cc, method, opts...)
+  if err != nil {
+    cancel()
+    return nil, err
+  }
+  // The context of the stream is done once it ended, however it did.
+  go func() {
+    <-cs.Context().Done()
+    cancel()
+  }()
+  return cs, nil
+}
+
+// budget derives the context of an outbound call from ctx, failing when the time left
+// is under the minimum budget. Calls without a deadline are left without one.
+func (c PropagationConfig) budget(ctx context.Context, method string) (context.Context, context.CancelFunc, error) {
+  if err := ctx.Err(); err != nil {
+    return nil, nil, status.FromContextError(err).Err()
+  }
+  deadline, ok := ctx.Deadline()
+  if !ok {
+    return ctx, func() {}, nil
+  }
+  left := time.Until(deadline) - c.Margin
+  if left <= 0 || left < c.MinBudget {
+    return nil, nil, status.Errorf(codes.DeadlineExceeded, "Not calling %v with %v left, under the budget of %v!", method, left.Round(time.Millisecond), c.MinBudget)
+  }
+  ctx, cancel := context.WithTimeout(ctx, left)
+  return ctx, cancel, nil
+}
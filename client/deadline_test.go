@@ -0,0 +1,194 @@
+package client_test
+
+import (
+  "context"
+  "sync/atomic"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/client"
+  "github.com/_dev/grpc-go-example/greet/greetpb"
+  "github.com/_dev/grpc-go-example/grpctest"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// downstream is the calculator called by the greet server, recording the deadline and
+// the end of the calls it gets.
+type downstream struct {
+  block    bool // holds the calls until their context is done.
+  calls    atomic.Int64
+  deadline atomic.Value // time.Time, zero for a call without deadline.
+  ended    chan error   // the context error of the blocked calls.
+}
+
+func (d *downstream) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  d.record(ctx)
+  if d.block {
+    <-ctx.Done()
+    d.ended <- ctx.Err()
+    return nil, status.FromContextError(ctx.Err()).Err()
+  }
+  return handler(ctx, req)
+}
+
+func (d *downstream) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  d.record(ss.Context())
+  return handler(srv, ss)
+}
+
+func (d *downstream) record(ctx context.Context) {
+  d.calls.Add(1)
+  deadline, _ := ctx.Deadline()
+  d.deadline.Store(deadline)
+}
+
+// upstream serves the greetings once it summed on the calculator, and averaged on it for
+// LongGreet, passing on the context of the call.
+type upstream struct {
+  calculator calculatorpb.CalculatorServiceClient
+}
+
+func (u *upstream) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  if _, err := u.calculator.Sum(ctx, &calculatorpb.SumRequest{}); err != nil {
+    return nil, err
+  }
+  return handler(ctx, req)
+}
+
+func (u *upstream) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  if _, err := computeAverageOf(ss.Context(), u.calculator, 1); err != nil {
+    return err
+  }
+  return handler(srv, ss)
+}
+
+// newChain starts a greet server calling a calculator server with c, and returns the
+// greet client.
+func newChain(t *testing.T, c client.PropagationConfig, d *downstream) greetpb.GreetServiceClient {
+  calc := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{
+    UnaryInterceptors:  []grpc.UnaryServerInterceptor{d.unary},
+    StreamInterceptors: []grpc.StreamServerInterceptor{d.stream},
+  })
+  u := &upstream{calculator: calculatorpb.NewCalculatorServiceClient(calc.Dial(t, client.WithDeadlinePropagation(c)...))}
+  g := grpctest.NewGreet(t, grpctest.GreetConfig(), grpctest.Options{
+    UnaryInterceptors:  []grpc.UnaryServerInterceptor{u.unary},
+    StreamInterceptors: []grpc.StreamServerInterceptor{u.stream},
+  })
+  return g.Client
+}
+
+func greet(ctx context.Context, c greetpb.GreetServiceClient) error {
+  _, err := c.Greet(ctx, &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: "Stephane"}})
+  return err
+}
+
+func TestPropagateDeadline(t *testing.T) {
+  d := &downstream{}
+  c := newChain(t, client.PropagationConfig{Margin: 500 * time.Millisecond}, d)
+
+  ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+  defer cancel()
+  incoming, _ := ctx.Deadline()
+  if err := greet(ctx, c); err != nil {
+    t.Fatal(err)
+  }
+
+  // The calculator gets the deadline of the greeting less the margin, give or take the
+  // time spent on the way, deadlines travelling as timeouts.
+  got := d.deadline.Load().(time.Time)
+  if early := incoming.Sub(got); early < 400*time.Millisecond || early > 600*time.Millisecond {
+    t.Fatalf("Downstream deadline is %v before the incoming one, want 500ms", incoming.Sub(got))
+  }
+}
+
+func TestPropagateStreamDeadline(t *testing.T) {
+  d := &downstream{}
+  c := newChain(t, client.PropagationConfig{Margin: time.Second}, d)
+
+  ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+  defer cancel()
+  incoming, _ := ctx.Deadline()
+  stream, err := c.LongGreet(ctx)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if _, err := stream.CloseAndRecv(); err != nil {
+    t.Fatal(err)
+  }
+
+  got := d.deadline.Load().(time.Time)
+  if early := incoming.Sub(got); early < 900*time.Millisecond || early > 1100*time.Millisecond {
+    t.Fatalf("Downstream stream deadline is %v before the incoming one, want 1s", incoming.Sub(got))
+  }
+}
+
+func TestPropagateNoDeadline(t *testing.T) {
+  d := &downstream{}
+  c := newChain(t, client.PropagationConfig{Margin: time.Second, MinBudget: time.Second}, d)
+
+  if err := greet(context.Background(), c); err != nil {
+    t.Fatal(err)
+  }
+  if got := d.deadline.Load().(time.Time); !got.IsZero() {
+    t.Fatalf("Downstream deadline = %v, want none", got)
+  }
+}
+
+func TestPropagateUnderBudget(t *testing.T) {
+  for _, tc := range []struct {
+    name string
+    c    client.PropagationConfig
+  }{
+    {name: "margin", c: client.PropagationConfig{Margin: time.Second}},
+    {name: "min budget", c: client.PropagationConfig{MinBudget: time.Second}},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      d := &downstream{}
+      c := newChain(t, tc.c, d)
+
+      // The greet server gives up at once rather than calling the calculator.
+      ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+      defer cancel()
+      start := time.Now()
+      if err := greet(ctx, c); status.Code(err) != codes.DeadlineExceeded {
+        t.Fatalf("Greet() = %v, want DeadlineExceeded", err)
+      }
+      if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+        t.Fatalf("Greet() failed after %v, want at once", elapsed)
+      }
+      if got := d.calls.Load(); got != 0 {
+        t.Fatalf("Downstream got %v calls, want 0", got)
+      }
+    })
+  }
+}
+
+func TestPropagateCancellation(t *testing.T) {
+  d := &downstream{block: true, ended: make(chan error, 1)}
+  c := newChain(t, client.PropagationConfig{Margin: 100 * time.Millisecond}, d)
+
+  ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+  done := make(chan error, 1)
+  go func() { done <- greet(ctx, c) }()
+  for d.calls.Load() == 0 {
+    time.Sleep(time.Millisecond)
+  }
+
+  // Canceling the greeting cancels the call to the calculator, two servers away.
+  cancel()
+  if err := <-done; status.Code(err) != codes.Canceled {
+    t.Fatalf("Greet() = %v, want Canceled", err)
+  }
+  select {
+  case err := <-d.ended:
+    if err != context.Canceled {
+      t.Fatalf("Downstream call ended with %v, want context.Canceled", err)
+    }
+  case <-time.After(5 * time.Second):
+    t.Fatal("Downstream call still runs after the cancellation")
+  }
+}
@@ -0,0 +1,128 @@
+package client
+
+import (
+  "encoding/json"
+  "fmt"
+  "os"
+  "strings"
+  "time"
+
+  "github.com/_dev/grpc-go-example/logging"
+
+  "google.golang.org/grpc/resolver"
+)
+
+// FileScheme is the scheme of the targets whose endpoints are listed in a JSON file, as in
+// "file:///etc/greet/endpoints.json" holding {"endpoints": ["10.0.0.1:50051", "10.0.0.2:50051"]}.
+// The file is watched and the connections follow its changes.
+const FileScheme = "file"
+
+// FileWatchInterval is how often the endpoints files are checked for changes.
+var FileWatchInterval = time.Second
+
+func init() {
+  resolver.Register(fileBuilder{})
+}
+
+// endpointsFile is the content of an endpoints file.
+type endpointsFile struct {
+  Endpoints []string `json:"endpoints"`
+}
+
+type fileBuilder struct{}
+
+func (fileBuilder) Scheme() string {
+  return FileScheme
+}
+
+func (fileBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+  path := target.URL.Path
+  if path == "" {
+    return nil, fmt.Errorf("no endpoints file in target %q", target.URL.String())
+  }
+  r := &fileResolver{
+    path: path,
+    cc:   cc,
+    now:  make(chan struct{}, 1),
+    done: make(chan struct{}),
+  }
+  // Failing the dial when the file cannot be read at first, later errors keep the last endpoints.
+  if err := r.update(); err != nil {
+    return nil, err
+  }
+  go r.watch()
+  return r, nil
+}
+
+// fileResolver updates its connection with the endpoints of a file, whenever it changes.
+type fileResolver struct {
+  path string
+  cc   resolver.ClientConn
+
+  modTime time.Time
+  size    int64
+
+  now  chan struct{}
+  done chan struct{}
+}
+
+func (r *fileResolver) ResolveNow(resolver.ResolveNowOptions) {
+  select {
+  case r.now <- struct{}{}:
+  default:
+  }
+}
+
+func (r *fileResolver) Close() {
+  close(r.done)
+}
+
+func (r *fileResolver) watch() {
+  ticker := time.NewTicker(FileWatchInterval)
+  defer ticker.Stop()
+  for {
+    select {
+    case <-r.done:
+      return
+    case <-ticker.C:
+    case <-r.now:
+    }
+    if err := r.update(); err != nil {
+      logging.Errorf("Failed to reload the endpoints of %v: %v", r.path, err)
+    }
+  }
+}
+
+// update reads the file again when it changed since the last update.
+func (r *fileResolver) update() error {
+  info, err := os.Stat(r.path)
+  if err != nil {
+    return err
+  }
+  if info.ModTime().Equal(r.modTime) && info.Size() == r.size {
+    return nil
+  }
+  // A bad file is reported once, until it changes again.
+  r.modTime, r.size = info.ModTime(), info.Size()
+
+  b, err := os.ReadFile(r.path)
+  if err != nil {
+    return err
+  }
+  var f endpointsFile
+  if err := json.Unmarshal(b, &f); err != nil {
+    return fmt.Errorf("invalid endpoints file %v: %v", r.path, err)
+  }
+  var addrs []resolver.Address
+  for _, e := range f.Endpoints {
+    if e = strings.TrimSpace(e); e != "" {
+      addrs = append(addrs, resolver.Address{Addr: e})
+    }
+  }
+  if len(addrs) == 0 {
+    return fmt.Errorf("no endpoint in %v", r.path)
+  }
+  logging.Infof("Loaded %v endpoints from %v.", len(addrs), r.path)
+
+  return r.cc.UpdateState(resolver.State{Addresses: addrs})
+}
@@ -0,0 +1,212 @@
+package client
+
+import (
+  "context"
+  "reflect"
+  "sync/atomic"
+  "time"
+
+  "github.com/_dev/grpc-go-example/clock"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/status"
+  "google.golang.org/protobuf/proto"
+  "google.golang.org/protobuf/protoadapt"
+)
+
+// HedgeConfig tells which unary methods are hedged, and how.
+type HedgeConfig struct {
+  // Methods are the full method names hedged, as in "/greet.GreetService/Greet",
+  // they must be idempotent since the server may well run all the attempts.
+  Methods []string
+
+  // Delay is how long an attempt may run before the next one is sent, with at most
+  // MaxHedges attempts sent after the first.
+  Delay     time.Duration
+  MaxHedges int
+
+  // NonFatalCodes make an attempt fail without failing the call, sending the next
+  // hedge at once, the other codes end the call.
+  NonFatalCodes []codes.Code
+
+  // Clock times the delays, the real clock when nil.
+  Clock clock.Clock
+}
+
+// HedgeStats counts the hedged calls.
+type HedgeStats struct {
+  Calls     int64 // calls made to a hedged method.
+  Hedged    int64 // calls which sent at least one hedge.
+  Hedges    int64 // hedges sent.
+  HedgeWins int64 // calls answered by a hedge rather than by the first attempt.
+}
+
+// Hedger sends the calls to the hedged methods again when they are slow to answer,
+// taking the first success and canceling the other attempts.
+type Hedger struct {
+  cfg     HedgeConfig
+  methods map[string]bool
+
+  calls     int64
+  hedged    int64
+  hedges    int64
+  hedgeWins int64
+}
+
+// NewHedger creates a hedger, see HedgeConfig.
+func NewHedger(cfg HedgeConfig) *Hedger {
+  cfg.Clock = clock.Or(cfg.Clock)
+  h := &Hedger{cfg: cfg, methods: make(map[string]bool)}
+  for _, m := range cfg.Methods {
+    h.methods[m] = true
+  }
+  return h
+}
+
+// WithHedging installs the hedger on the unary calls of a connection.
+func WithHedging(h *Hedger) []grpc.DialOption {
+  return []grpc.DialOption{grpc.WithChainUnaryInterceptor(h.UnaryInterceptor)}
+}
+
+// Stats returns a snapshot of the counters of the hedger.
+func (h *Hedger) Stats() HedgeStats {
+  return HedgeStats{
+    Calls:     atomic.LoadInt64(&h.calls),
+    Hedged:    atomic.LoadInt64(&h.hedged),
+    Hedges:    atomic.LoadInt64(&h.hedges),
+    HedgeWins: atomic.LoadInt64(&h.hedgeWins),
+  }
+}
+
+type attempt struct {
+  n       int
+  reply   interface{}
+  header  metadata.MD
+  trailer metadata.MD
+  err     error
+}
+
+// UnaryInterceptor hedges the calls to the configured methods.
+func (h *Hedger) UnaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+  if !h.methods[method] || h.cfg.MaxHedges < 1 {
+    return invoker(ctx, method, req, reply, cc, opts...)
+  }
+  atomic.AddInt64(&h.calls, 1)
+
+  // The attempts run side by side, each gets its own reply, header and trailer and
+  // those of the winner are handed back.
+  var headerAddr, trailerAddr *metadata.MD
+  var callOpts []grpc.CallOption
+  for _, o := range opts {
+    switch o := o.(type) {
+    case grpc.HeaderCallOption:
+      headerAddr = o.HeaderAddr
+    case grpc.TrailerCallOption:
+      trailerAddr = o.TrailerAddr
+    default:
+      callOpts = append(callOpts, o)
+    }
+  }
+
+  ctx, cancel := context.WithCancel(ctx)
+  // Canceling the attempts still running once the call is over.
+  defer cancel()
+
+  results := make(chan *attempt, h.cfg.MaxHedges+1)
+  send := func(n int) {
+    a := &attempt{n: n, reply: newReply(reply)}
+    go func() {
+      a.err = invoker(ctx, method, req, a.reply, cc, append(callOpts, grpc.Header(&a.header), grpc.Trailer(&a.trailer))...)
+      results <- a
+    }()
+  }
+
+  send(0)
+  sent, pending := 1, 1
+  timer := h.cfg.Clock.NewTimer(h.cfg.Delay)
+  defer timer.Stop()
+  var last *attempt
+  for pending > 0 {
+    select {
+    case <-timer.C():
+      if sent <= h.cfg.MaxHedges {
+        h.hedge(sent)
+        send(sent)
+        sent++
+        pending++
+        timer.Reset(h.cfg.Delay)
+      }
+      continue
+    case last = <-results:
+      pending--
+    }
+
+    if last.err == nil {
+      if last.n > 0 {
+        atomic.AddInt64(&h.hedgeWins, 1)
+      }
+      break
+    }
+    if !h.nonFatal(status.Code(last.err)) {
+      break
+    }
+    // Not waiting for the delay, the attempt failed already.
+    if sent <= h.cfg.MaxHedges {
+      h.hedge(sent)
+      send(sent)
+      sent++
+      pending++
+      if !timer.Stop() {
+        <-timer.C()
+      }
+      timer.Reset(h.cfg.Delay)
+    }
+  }
+
+  if headerAddr != nil {
+    *headerAddr = last.header
+  }
+  if trailerAddr != nil {
+    *trailerAddr = last.trailer
+  }
+  if last.err != nil {
+    return last.err
+  }
+  proto.Reset(messageV2(reply))
+  proto.Merge(messageV2(reply), messageV2(last.reply))
+  return nil
+}
+
+func (h *Hedger) hedge(n int) {
+  if n == 1 {
+    atomic.AddInt64(&h.hedged, 1)
+  }
+  atomic.AddInt64(&h.hedges, 1)
+}
+
+func (h *Hedger) nonFatal(code codes.Code) bool {
+  for _, c := range h.cfg.NonFatalCodes {
+    if c == code {
+      return true
+    }
+  }
+  return false
+}
+
+// newReply returns an empty message of the type of reply.
+func newReply(reply interface{}) interface{} {
+  return reflect.New(reflect.TypeOf(reply).Elem()).Interface()
+}
+
+// messageV2 also accepts the messages generated by the older protoc-gen-go.
+func messageV2(m interface{}) proto.Message {
+  switch m := m.(type) {
+  case proto.Message:
+    return m
+  case protoadapt.MessageV1:
+    return protoadapt.MessageV2Of(m)
+  }
+  return nil
+}
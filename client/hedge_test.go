@@ -0,0 +1,208 @@
+package client_test
+
+import (
+  "context"
+  "sync/atomic"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/client"
+  "github.com/_dev/grpc-go-example/clock"
+  "github.com/_dev/grpc-go-example/grpctest"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+const sum = "/calculator.CalculatorService/Sum"
+
+// held holds each attempt, numbered as they arrive, until the test releases it, answering
+// it or failing it with the error released.
+type held struct {
+  attempts atomic.Int64
+  arrived  chan int
+  release  []chan error
+  canceled chan int // attempts whose context was canceled while held.
+}
+
+func newHeld(attempts int) *held {
+  h := &held{arrived: make(chan int, attempts), canceled: make(chan int, attempts)}
+  for i := 0; i < attempts; i++ {
+    h.release = append(h.release, make(chan error, 1))
+  }
+  return h
+}
+
+func (h *held) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  n := int(h.attempts.Add(1)) - 1
+  h.arrived <- n
+  select {
+  case err := <-h.release[n]:
+    if err != nil {
+      return nil, err
+    }
+    return handler(ctx, req)
+  case <-ctx.Done():
+    h.canceled <- n
+    return nil, ctx.Err()
+  }
+}
+
+// expect fails t unless attempt n arrives next.
+func (h *held) expect(t *testing.T, n int) {
+  t.Helper()
+  select {
+  case got := <-h.arrived:
+    if got != n {
+      t.Fatalf("Attempt %v arrived, want %v", got, n)
+    }
+  case <-time.After(5 * time.Second):
+    t.Fatalf("Attempt %v did not arrive", n)
+  }
+}
+
+// expectNone fails t if an attempt arrives shortly.
+func (h *held) expectNone(t *testing.T) {
+  t.Helper()
+  select {
+  case got := <-h.arrived:
+    t.Fatalf("Attempt %v arrived, want none", got)
+  case <-time.After(20 * time.Millisecond):
+  }
+}
+
+// expectCanceled fails t unless the attempts of want are canceled, in any order.
+func (h *held) expectCanceled(t *testing.T, want ...int) {
+  t.Helper()
+  pending := make(map[int]bool)
+  for _, n := range want {
+    pending[n] = true
+  }
+  for len(pending) > 0 {
+    select {
+    case got := <-h.canceled:
+      if !pending[got] {
+        t.Fatalf("Attempt %v was canceled, want %v", got, want)
+      }
+      delete(pending, got)
+    case <-time.After(5 * time.Second):
+      t.Fatalf("Attempts %v were not canceled", want)
+    }
+  }
+}
+
+// newHedgedCalculator serves the calculator behind h, hedging Sum after a second, at most
+// twice, and at once after an Unavailable attempt.
+func newHedgedCalculator(t *testing.T, h *held) (calculatorpb.CalculatorServiceClient, *client.Hedger, *clock.Fake) {
+  clk := clock.NewFake(time.Now())
+  hedger := client.NewHedger(client.HedgeConfig{
+    Methods:       []string{sum},
+    Delay:         time.Second,
+    MaxHedges:     2,
+    NonFatalCodes: []codes.Code{codes.Unavailable},
+    Clock:         clk,
+  })
+  calc := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{
+    UnaryInterceptors: []grpc.UnaryServerInterceptor{h.unary},
+  })
+  return calculatorpb.NewCalculatorServiceClient(calc.Dial(t, client.WithHedging(hedger)...)), hedger, clk
+}
+
+func TestHedgeWins(t *testing.T) {
+  h := newHeld(3)
+  c, hedger, clk := newHedgedCalculator(t, h)
+
+  done := sumAsync(c)
+  h.expect(t, 0)
+  clk.BlockUntil(1)
+  clk.Advance(time.Second - time.Millisecond)
+  h.expectNone(t)
+  clk.Advance(time.Millisecond)
+  h.expect(t, 1)
+
+  // The hedge answers first, the slow attempt is canceled.
+  h.release[1] <- nil
+  if r := <-done; r.err != nil || r.res.GetSumResult() != 7 {
+    t.Fatalf("Sum() = %v, %v, want 7", r.res, r.err)
+  }
+  h.expectCanceled(t, 0)
+  if got, want := hedger.Stats(), (client.HedgeStats{Calls: 1, Hedged: 1, Hedges: 1, HedgeWins: 1}); got != want {
+    t.Fatalf("Stats() = %+v, want %+v", got, want)
+  }
+}
+
+func TestHedgeFirstWins(t *testing.T) {
+  h := newHeld(3)
+  c, hedger, clk := newHedgedCalculator(t, h)
+
+  done := sumAsync(c)
+  h.expect(t, 0)
+  clk.BlockUntil(1)
+  clk.Advance(time.Second)
+  h.expect(t, 1)
+
+  // The first attempt answers first, the hedge is canceled and did not win.
+  h.release[0] <- nil
+  if r := <-done; r.err != nil || r.res.GetSumResult() != 7 {
+    t.Fatalf("Sum() = %v, %v, want 7", r.res, r.err)
+  }
+  h.expectCanceled(t, 1)
+  if got, want := hedger.Stats(), (client.HedgeStats{Calls: 1, Hedged: 1, Hedges: 1}); got != want {
+    t.Fatalf("Stats() = %+v, want %+v", got, want)
+  }
+}
+
+func TestHedgeCap(t *testing.T) {
+  h := newHeld(3)
+  c, hedger, clk := newHedgedCalculator(t, h)
+
+  done := sumAsync(c)
+  h.expect(t, 0)
+  for n := 1; n <= 2; n++ {
+    clk.BlockUntil(1)
+    clk.Advance(time.Second)
+    h.expect(t, n)
+  }
+  // No more than MaxHedges hedges are sent.
+  clk.BlockUntil(1)
+  clk.Advance(time.Second)
+  h.expectNone(t)
+
+  h.release[2] <- nil
+  if r := <-done; r.err != nil || r.res.GetSumResult() != 7 {
+    t.Fatalf("Sum() = %v, %v, want 7", r.res, r.err)
+  }
+  h.expectCanceled(t, 0, 1)
+  if got, want := hedger.Stats(), (client.HedgeStats{Calls: 1, Hedged: 1, Hedges: 2, HedgeWins: 1}); got != want {
+    t.Fatalf("Stats() = %+v, want %+v", got, want)
+  }
+}
+
+func TestHedgeFailures(t *testing.T) {
+  h := newHeld(3)
+  c, hedger, _ := newHedgedCalculator(t, h)
+
+  // A non fatal failure sends the next hedge at once, without the clock advancing.
+  done := sumAsync(c)
+  h.expect(t, 0)
+  h.release[0] <- status.Error(codes.Unavailable, "flaky")
+  h.expect(t, 1)
+  h.release[1] <- nil
+  if r := <-done; r.err != nil || r.res.GetSumResult() != 7 {
+    t.Fatalf("Sum() = %v, %v, want 7", r.res, r.err)
+  }
+
+  // A fatal one ends the call.
+  done = sumAsync(c)
+  h.expect(t, 2)
+  h.release[2] <- status.Error(codes.InvalidArgument, "bad")
+  if r := <-done; status.Code(r.err) != codes.InvalidArgument {
+    t.Fatalf("Sum() = %v, want InvalidArgument", r.err)
+  }
+  h.expectNone(t)
+  if got, want := hedger.Stats(), (client.HedgeStats{Calls: 2, Hedged: 1, Hedges: 1, HedgeWins: 1}); got != want {
+    t.Fatalf("Stats() = %+v, want %+v", got, want)
+  }
+}
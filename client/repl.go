@@ -0,0 +1,234 @@
+package client
+
+import (
+  "bufio"
+  "fmt"
+  "io"
+  "os"
+  "path/filepath"
+  "strconv"
+  "strings"
+  "sync"
+)
+
+// MaxHistory bounds the lines kept by the history of a REPL.
+const MaxHistory = 1000
+
+// REPL drives a bidirectional stream from the lines typed by the user: each line is sent
+// as a message while the responses are printed as they arrive. Besides the messages, it
+// understands:
+//
+//	:history   lists the lines sent, numbered
+//	!!         sends the last line again
+//	!n         sends the nth line again
+//
+// The end of the input (Ctrl-D) half-closes the stream, the REPL then prints the last
+// responses until the server ends the stream.
+type REPL struct {
+  // Prompt is shown before each line when reading from a terminal.
+  Prompt string
+
+  // Out receives the responses and the messages to the user.
+  Out io.Writer
+
+  // HistoryFile keeps the history across the sessions, none when empty.
+  HistoryFile string
+
+  // Send sends the message read from a line. It returns io.EOF once the stream is broken,
+  // any other error is an invalid line, told to the user.
+  Send func(line string) error
+
+  // Recv receives the next response as text, and io.EOF when the server ended the stream.
+  Recv func() (string, error)
+
+  CloseSend func() error
+
+  mu        sync.Mutex // guards Out.
+  prompting bool
+  history   []string
+}
+
+// HistoryFile returns the file named name in the home directory, or "" when there is none.
+func HistoryFile(name string) string {
+  home, err := os.UserHomeDir()
+  if err != nil {
+    return ""
+  }
+  return filepath.Join(home, name)
+}
+
+// Run reads the lines of in until its end or the end of the stream. It returns the error
+// the server ended the stream with, nil when it ended it cleanly.
+func (r *REPL) Run(in io.Reader) error {
+  r.prompting = r.Prompt != "" && isTerminal(in)
+  r.loadHistory()
+
+  quit := make(chan struct{})
+  defer close(quit)
+  lines := make(chan string)
+  go func() {
+    scanner := bufio.NewScanner(in)
+    for scanner.Scan() {
+      select {
+      case lines <- scanner.Text():
+      case <-quit:
+        return
+      }
+    }
+    close(lines)
+  }()
+
+  done := make(chan error, 1)
+  go func() {
+    for {
+      res, err := r.Recv()
+      if err == io.EOF {
+        done <- nil
+        return
+      }
+      if err != nil {
+        done <- err
+        return
+      }
+      r.print("%v\n", res)
+    }
+  }()
+
+  r.print("")
+  for {
+    select {
+    case err := <-done:
+      r.endLine()
+      return err
+    case line, ok := <-lines:
+      if !ok {
+        // Ctrl-D, telling the server there is no more to send...
+        r.endLine()
+        r.CloseSend()
+        return <-done
+      }
+      if broken := r.handle(line); broken {
+        return <-done
+      }
+      r.print("")
+    }
+  }
+}
+
+// handle sends a line, reporting whether the stream is broken.
+func (r *REPL) handle(line string) (broken bool) {
+  line = strings.TrimSpace(line)
+  switch {
+  case line == "":
+    return false
+  case line == ":history":
+    for i, l := range r.history {
+      r.write("%5d  %v\n", i+1, l)
+    }
+    return false
+  case strings.HasPrefix(line, "!"):
+    expanded, err := r.expand(line)
+    if err != nil {
+      r.write("%v\n", err)
+      return false
+    }
+    line = expanded
+    r.write("%v\n", line)
+  }
+
+  r.remember(line)
+  err := r.Send(line)
+  if err == io.EOF {
+    return true // the error is told by Recv.
+  }
+  if err != nil {
+    r.write("Invalid input: %v\n", err)
+  }
+  return false
+}
+
+// expand returns the line of the history referred by !! or !n.
+func (r *REPL) expand(line string) (string, error) {
+  if len(r.history) == 0 {
+    return "", fmt.Errorf("%v: the history is empty", line)
+  }
+  if line == "!!" {
+    return r.history[len(r.history)-1], nil
+  }
+  n, err := strconv.Atoi(line[1:])
+  if err != nil || n < 1 || n > len(r.history) {
+    return "", fmt.Errorf("%v: no such line in the history, expecting !! or !1 to !%d", line, len(r.history))
+  }
+  return r.history[n-1], nil
+}
+
+func (r *REPL) loadHistory() {
+  if r.HistoryFile == "" {
+    return
+  }
+  b, err := os.ReadFile(r.HistoryFile)
+  if err != nil {
+    return // no history yet.
+  }
+  for _, line := range strings.Split(string(b), "\n") {
+    if line = strings.TrimSpace(line); line != "" {
+      r.history = append(r.history, line)
+    }
+  }
+  if len(r.history) > MaxHistory {
+    r.history = r.history[len(r.history)-MaxHistory:]
+  }
+}
+
+func (r *REPL) remember(line string) {
+  r.history = append(r.history, line)
+  if len(r.history) > MaxHistory {
+    r.history = r.history[1:]
+  }
+  if r.HistoryFile == "" {
+    return
+  }
+  f, err := os.OpenFile(r.HistoryFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+  if err != nil {
+    r.write("Could not save the history, disabling it: %v\n", err)
+    r.HistoryFile = ""
+    return
+  }
+  defer f.Close()
+  fmt.Fprintln(f, line)
+}
+
+// print writes a response, then shows the prompt again.
+func (r *REPL) print(format string, v ...interface{}) {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+  if r.prompting && format != "" {
+    fmt.Fprint(r.Out, "\r\x1b[K") // over the prompt.
+  }
+  fmt.Fprintf(r.Out, format, v...)
+  if r.prompting {
+    fmt.Fprint(r.Out, r.Prompt)
+  }
+}
+
+func (r *REPL) write(format string, v ...interface{}) {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+  fmt.Fprintf(r.Out, format, v...)
+}
+
+// endLine moves past the prompt.
+func (r *REPL) endLine() {
+  if r.prompting {
+    r.write("\n")
+  }
+}
+
+func isTerminal(in io.Reader) bool {
+  f, ok := in.(*os.File)
+  if !ok {
+    return false
+  }
+  info, err := f.Stat()
+  return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,234 @@
+// Package client gathers the dial options making the greet and calculator clients
+// resilient: retries, circuit breaking, hedging and load balancing; the propagation of
+// deadlines from a server to the servers it calls; and the REPL driving their
+// bidirectional streams by hand.
+package client
+
+import (
+  "context"
+  "fmt"
+  "math"
+  "math/rand"
+  "strconv"
+  "strings"
+  "time"
+
+  "github.com/_dev/grpc-go-example/clock"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/status"
+)
+
+// RetryPolicy tells when and how often a failed call is attempted again.
+type RetryPolicy struct {
+  // MaxAttempts counts the first attempt, the service config caps it to 5.
+  MaxAttempts int
+
+  // The nth retry waits InitialBackoff*BackoffMultiplier^(n-1), at most MaxBackoff,
+  // spread by up to Jitter (a fraction of it) either way.
+  InitialBackoff    time.Duration
+  MaxBackoff        time.Duration
+  BackoffMultiplier float64
+  Jitter            float64
+
+  // RetryableCodes are the status codes worth retrying.
+  RetryableCodes []codes.Code
+}
+
+// DefaultRetryPolicy retries the transient failures up to 4 times.
+var DefaultRetryPolicy = RetryPolicy{
+  MaxAttempts:       5,
+  InitialBackoff:    100 * time.Millisecond,
+  MaxBackoff:        5 * time.Second,
+  BackoffMultiplier: 2,
+  Jitter:            0.2,
+  RetryableCodes:    []codes.Code{codes.Unavailable, codes.ResourceExhausted},
+}
+
+// RetryConfig holds a policy per full method name, as in "/greet.GreetService/Greet",
+// and the Default policy for the other methods.
+type RetryConfig struct {
+  Default RetryPolicy
+  Methods map[string]RetryPolicy
+
+  // Clock times the backoffs of UnaryInterceptor, the real clock when nil.
+  Clock clock.Clock
+}
+
+func (c RetryConfig) policy(method string) RetryPolicy {
+  if p, ok := c.Methods[method]; ok {
+    return p
+  }
+  return c.Default
+}
+
+type methodName struct {
+  Service string `json:"service,omitempty"`
+  Method  string `json:"method,omitempty"`
+}
+
+type retryPolicy struct {
+  MaxAttempts          int      `json:"maxAttempts"`
+  InitialBackoff       string   `json:"initialBackoff"`
+  MaxBackoff           string   `json:"maxBackoff"`
+  BackoffMultiplier    float64  `json:"backoffMultiplier"`
+  RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type methodConfig struct {
+  Name        []methodName `json:"name"`
+  RetryPolicy retryPolicy  `json:"retryPolicy"`
+}
+
+func (c RetryConfig) methodConfigs() ([]methodConfig, error) {
+  type name = methodName
+
+  render := func(names []name, p RetryPolicy) (methodConfig, error) {
+    if p.MaxAttempts < 2 || p.InitialBackoff <= 0 || p.MaxBackoff <= 0 || p.BackoffMultiplier <= 0 || len(p.RetryableCodes) == 0 {
+      return methodConfig{}, fmt.Errorf("retry policy %+v cannot be expressed as a service config", p)
+    }
+    retryable := make([]string, len(p.RetryableCodes))
+    for i, code := range p.RetryableCodes {
+      retryable[i] = codeNames[code]
+    }
+    return methodConfig{
+      Name: names,
+      RetryPolicy: retryPolicy{
+        MaxAttempts:          p.MaxAttempts,
+        InitialBackoff:       durationString(p.InitialBackoff),
+        MaxBackoff:           durationString(p.MaxBackoff),
+        BackoffMultiplier:    p.BackoffMultiplier,
+        RetryableStatusCodes: retryable,
+      },
+    }, nil
+  }
+
+  var configs []methodConfig
+  for method, p := range c.Methods {
+    parts := strings.Split(strings.TrimPrefix(method, "/"), "/")
+    if len(parts) != 2 {
+      return nil, fmt.Errorf("invalid method name %q", method)
+    }
+    mc, err := render([]name{{Service: parts[0], Method: parts[1]}}, p)
+    if err != nil {
+      return nil, err
+    }
+    configs = append(configs, mc)
+  }
+  // An empty name applies to every method without a config of its own.
+  mc, err := render([]name{{}}, c.Default)
+  if err != nil {
+    return nil, err
+  }
+  return append(configs, mc), nil
+}
+
+// WithServiceConfigRetries enables the retries of c through the gRPC service config.
+func WithServiceConfigRetries(c RetryConfig) (grpc.DialOption, error) {
+  return WithServiceConfig(&c, nil)
+}
+
+// WithRetryInterceptor enables the retries of c for unary calls through an interceptor,
+// as a fallback where service config retries are not available. It disables the latter.
+func WithRetryInterceptor(c RetryConfig) []grpc.DialOption {
+  return []grpc.DialOption{
+    grpc.WithDisableRetry(),
+    grpc.WithChainUnaryInterceptor(c.UnaryInterceptor),
+  }
+}
+
+// UnaryInterceptor attempts the calls again according to the policy of their method,
+// waiting for the time hinted by the server in its retry-after or grpc-retry-pushback-ms
+// trailers when there is one.
+func (c RetryConfig) UnaryInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+  p := c.policy(method)
+  for attempt := 1; ; attempt++ {
+    var trailer metadata.MD
+    err := invoker(ctx, method, req, reply, cc, append(opts, grpc.Trailer(&trailer))...)
+    if err == nil || attempt >= p.MaxAttempts || !p.retryable(status.Code(err)) {
+      return err
+    }
+
+    wait, ok := pushback(trailer)
+    if !ok {
+      return err // the server asked not to retry.
+    }
+    if wait == 0 {
+      wait = p.backoff(attempt)
+    }
+    timer := clock.Or(c.Clock).NewTimer(wait)
+    select {
+    case <-timer.C():
+    case <-ctx.Done():
+      timer.Stop()
+      return err
+    }
+  }
+}
+
+func (p RetryPolicy) retryable(code codes.Code) bool {
+  for _, c := range p.RetryableCodes {
+    if c == code {
+      return true
+    }
+  }
+  return false
+}
+
+// backoff returns the wait before the given retry, counting from 1.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+  wait := float64(p.InitialBackoff) * math.Pow(p.BackoffMultiplier, float64(retry-1))
+  if wait > float64(p.MaxBackoff) {
+    wait = float64(p.MaxBackoff)
+  }
+  wait += wait * p.Jitter * (2*rand.Float64() - 1)
+  return time.Duration(wait)
+}
+
+// pushback reads the server hint, a zero wait when there is none; ok is false when the
+// server asked not to retry with a negative grpc-retry-pushback-ms.
+func pushback(trailer metadata.MD) (wait time.Duration, ok bool) {
+  if values := trailer.Get("grpc-retry-pushback-ms"); len(values) > 0 {
+    ms, err := strconv.ParseInt(values[0], 10, 64)
+    if err == nil {
+      if ms < 0 {
+        return 0, false
+      }
+      return time.Duration(ms) * time.Millisecond, true
+    }
+  }
+  if values := trailer.Get("retry-after"); len(values) > 0 {
+    seconds, err := strconv.ParseFloat(values[0], 64)
+    if err == nil && seconds >= 0 {
+      return time.Duration(seconds * float64(time.Second)), true
+    }
+  }
+  return 0, true
+}
+
+// codeNames spell the codes as the service config does.
+var codeNames = map[codes.Code]string{
+  codes.OK:                 "OK",
+  codes.Canceled:           "CANCELLED",
+  codes.Unknown:            "UNKNOWN",
+  codes.InvalidArgument:    "INVALID_ARGUMENT",
+  codes.DeadlineExceeded:   "DEADLINE_EXCEEDED",
+  codes.NotFound:           "NOT_FOUND",
+  codes.AlreadyExists:      "ALREADY_EXISTS",
+  codes.PermissionDenied:   "PERMISSION_DENIED",
+  codes.ResourceExhausted:  "RESOURCE_EXHAUSTED",
+  codes.FailedPrecondition: "FAILED_PRECONDITION",
+  codes.Aborted:            "ABORTED",
+  codes.OutOfRange:         "OUT_OF_RANGE",
+  codes.Unimplemented:      "UNIMPLEMENTED",
+  codes.Internal:           "INTERNAL",
+  codes.Unavailable:        "UNAVAILABLE",
+  codes.DataLoss:           "DATA_LOSS",
+  codes.Unauthenticated:    "UNAUTHENTICATED",
+}
+
+func durationString(d time.Duration) string {
+  return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
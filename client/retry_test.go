@@ -0,0 +1,241 @@
+package client_test
+
+import (
+  "context"
+  "sync/atomic"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/client"
+  "github.com/_dev/grpc-go-example/clock"
+  "github.com/_dev/grpc-go-example/grpctest"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/status"
+)
+
+// flaky fails the first calls it sees with code, sending trailer along.
+type flaky struct {
+  failures int64
+  code     codes.Code
+  trailer  metadata.MD
+  attempts atomic.Int64
+}
+
+func (f *flaky) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  if f.attempts.Add(1) <= f.failures {
+    grpc.SetTrailer(ctx, f.trailer)
+    return nil, status.Error(f.code, "flaky")
+  }
+  return handler(ctx, req)
+}
+
+func (f *flaky) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  if f.attempts.Add(1) <= f.failures {
+    ss.SetTrailer(f.trailer)
+    return status.Error(f.code, "flaky")
+  }
+  return handler(srv, ss)
+}
+
+func newFlakyCalculator(t *testing.T, f *flaky, opts ...grpc.DialOption) calculatorpb.CalculatorServiceClient {
+  calc := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{
+    UnaryInterceptors:  []grpc.UnaryServerInterceptor{f.unary},
+    StreamInterceptors: []grpc.StreamServerInterceptor{f.stream},
+  })
+  return calculatorpb.NewCalculatorServiceClient(calc.Dial(t, opts...))
+}
+
+// testRetryPolicy waits 1s then 2s between its 3 attempts, without jitter.
+var testRetryPolicy = client.RetryPolicy{
+  MaxAttempts:       3,
+  InitialBackoff:    time.Second,
+  MaxBackoff:        time.Minute,
+  BackoffMultiplier: 2,
+  RetryableCodes:    []codes.Code{codes.Unavailable},
+}
+
+type sumResult struct {
+  res *calculatorpb.SumResponse
+  err error
+}
+
+// sumAsync calls Sum from another goroutine, for the test to drive the clock meanwhile.
+func sumAsync(c calculatorpb.CalculatorServiceClient) <-chan sumResult {
+  done := make(chan sumResult, 1)
+  go func() {
+    res, err := c.Sum(context.Background(), &calculatorpb.SumRequest{FirstNumber: 3, SecondNumber: 4})
+    done <- sumResult{res, err}
+  }()
+  return done
+}
+
+func TestRetryInterceptorBackoff(t *testing.T) {
+  f := &flaky{failures: 2, code: codes.Unavailable}
+  clk := clock.NewFake(time.Now())
+  c := newFlakyCalculator(t, f, client.WithRetryInterceptor(client.RetryConfig{Default: testRetryPolicy, Clock: clk})...)
+
+  done := sumAsync(c)
+  for _, backoff := range []time.Duration{time.Second, 2 * time.Second} {
+    clk.BlockUntil(1)
+    attempts := f.attempts.Load()
+    clk.Advance(backoff - time.Millisecond)
+    if got := f.attempts.Load(); got != attempts {
+      t.Fatalf("Attempted %v times before the backoff of %v ended, want %v", got, backoff, attempts)
+    }
+    clk.Advance(time.Millisecond)
+  }
+  r := <-done
+  if r.err != nil || r.res.GetSumResult() != 7 {
+    t.Fatalf("Sum() = %v, %v, want 7", r.res, r.err)
+  }
+  if got := f.attempts.Load(); got != 3 {
+    t.Fatalf("Attempted %v times, want 3", got)
+  }
+}
+
+func TestRetryInterceptorGivesUp(t *testing.T) {
+  for _, tc := range []struct {
+    name     string
+    flaky    *flaky
+    attempts int64
+  }{
+    {
+      name:     "max attempts",
+      flaky:    &flaky{failures: 10, code: codes.Unavailable},
+      attempts: 3,
+    },
+    {
+      name:     "not retryable",
+      flaky:    &flaky{failures: 10, code: codes.InvalidArgument},
+      attempts: 1,
+    },
+    {
+      name:     "negative pushback",
+      flaky:    &flaky{failures: 10, code: codes.Unavailable, trailer: metadata.Pairs("grpc-retry-pushback-ms", "-1")},
+      attempts: 1,
+    },
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      // The backoffs end at once.
+      p := testRetryPolicy
+      p.InitialBackoff = time.Nanosecond
+      c := newFlakyCalculator(t, tc.flaky, client.WithRetryInterceptor(client.RetryConfig{Default: p})...)
+
+      _, err := c.Sum(context.Background(), &calculatorpb.SumRequest{})
+      if status.Code(err) != tc.flaky.code {
+        t.Fatalf("Sum() = %v, want %v", err, tc.flaky.code)
+      }
+      if got := tc.flaky.attempts.Load(); got != tc.attempts {
+        t.Fatalf("Attempted %v times, want %v", got, tc.attempts)
+      }
+    })
+  }
+}
+
+func TestRetryInterceptorPushback(t *testing.T) {
+  for _, tc := range []struct {
+    name    string
+    trailer metadata.MD
+    wait    time.Duration
+  }{
+    {name: "grpc-retry-pushback-ms", trailer: metadata.Pairs("grpc-retry-pushback-ms", "5000"), wait: 5 * time.Second},
+    {name: "retry-after", trailer: metadata.Pairs("retry-after", "0.5"), wait: 500 * time.Millisecond},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      f := &flaky{failures: 1, code: codes.Unavailable, trailer: tc.trailer}
+      clk := clock.NewFake(time.Now())
+      c := newFlakyCalculator(t, f, client.WithRetryInterceptor(client.RetryConfig{Default: testRetryPolicy, Clock: clk})...)
+
+      // The hint of the server replaces the backoff of the policy.
+      done := sumAsync(c)
+      clk.BlockUntil(1)
+      clk.Advance(tc.wait - time.Millisecond)
+      if got := f.attempts.Load(); got != 1 {
+        t.Fatalf("Attempted %v times before the pushback ended, want 1", got)
+      }
+      clk.Advance(time.Millisecond)
+      if r := <-done; r.err != nil {
+        t.Fatalf("Sum() = %v", r.err)
+      }
+    })
+  }
+}
+
+func TestRetryInterceptorContextDone(t *testing.T) {
+  f := &flaky{failures: 10, code: codes.Unavailable}
+  clk := clock.NewFake(time.Now())
+  c := newFlakyCalculator(t, f, client.WithRetryInterceptor(client.RetryConfig{Default: testRetryPolicy, Clock: clk})...)
+
+  // A canceled call stops waiting for its next attempt.
+  ctx, cancel := context.WithCancel(context.Background())
+  go func() {
+    clk.BlockUntil(1)
+    cancel()
+  }()
+  if _, err := c.Sum(ctx, &calculatorpb.SumRequest{}); status.Code(err) != codes.Unavailable {
+    t.Fatalf("Sum() = %v, want the Unavailable of the last attempt", err)
+  }
+  if got := f.attempts.Load(); got != 1 {
+    t.Fatalf("Attempted %v times, want 1", got)
+  }
+}
+
+func TestServiceConfigRetries(t *testing.T) {
+  p := testRetryPolicy
+  p.InitialBackoff = time.Millisecond
+  opt, err := client.WithServiceConfigRetries(client.RetryConfig{
+    Default: p,
+    Methods: map[string]client.RetryPolicy{
+      "/calculator.CalculatorService/PrimeNumberDecomposition": {
+        MaxAttempts:       2,
+        InitialBackoff:    time.Millisecond,
+        MaxBackoff:        time.Millisecond,
+        BackoffMultiplier: 1,
+        RetryableCodes:    []codes.Code{codes.Unavailable},
+      },
+    },
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  t.Run("unary", func(t *testing.T) {
+    f := &flaky{failures: 2, code: codes.Unavailable}
+    c := newFlakyCalculator(t, f, opt)
+    res, err := c.Sum(context.Background(), &calculatorpb.SumRequest{FirstNumber: 3, SecondNumber: 4})
+    if err != nil || res.GetSumResult() != 7 {
+      t.Fatalf("Sum() = %v, %v, want 7", res, err)
+    }
+    if got := f.attempts.Load(); got != 3 {
+      t.Fatalf("Attempted %v times, want 3", got)
+    }
+  })
+
+  t.Run("stream", func(t *testing.T) {
+    // The method policy allows a single retry, until the first response.
+    f := &flaky{failures: 2, code: codes.Unavailable}
+    c := newFlakyCalculator(t, f, opt)
+    stream, err := c.PrimeNumberDecomposition(context.Background(), &calculatorpb.PrimeNumberDecompositionRequest{Number: 6})
+    if err != nil {
+      t.Fatal(err)
+    }
+    if _, err := stream.Recv(); status.Code(err) != codes.Unavailable {
+      t.Fatalf("PrimeNumberDecomposition() = %v, want Unavailable", err)
+    }
+    if got := f.attempts.Load(); got != 2 {
+      t.Fatalf("Attempted %v times, want 2", got)
+    }
+
+    stream, err = c.PrimeNumberDecomposition(context.Background(), &calculatorpb.PrimeNumberDecompositionRequest{Number: 6})
+    if err != nil {
+      t.Fatal(err)
+    }
+    if res, err := stream.Recv(); err != nil || res.GetPrimeFactor() != 2 {
+      t.Fatalf("PrimeNumberDecomposition() = %v, %v, want 2", res, err)
+    }
+  })
+}
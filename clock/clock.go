@@ -0,0 +1,72 @@
+// Package clock abstracts the passing of time, so that the services and the clients can
+// run on the real clock and the tests on a Fake one, advanced by hand.
+package clock
+
+import (
+  "time"
+)
+
+// Clock tells the time and waits.
+type Clock interface {
+  Now() time.Time
+  Since(t time.Time) time.Duration
+  Sleep(d time.Duration)
+  After(d time.Duration) <-chan time.Time
+  NewTimer(d time.Duration) Timer
+  NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a time.Timer of a Clock.
+type Timer interface {
+  C() <-chan time.Time
+  Stop() bool
+  Reset(d time.Duration) bool
+}
+
+// Ticker is a time.Ticker of a Clock.
+type Ticker interface {
+  C() <-chan time.Time
+  Stop()
+}
+
+// Real is the clock of package time.
+var Real Clock = realClock{}
+
+// Or returns c, or Real when c is nil.
+func Or(c Clock) Clock {
+  if c == nil {
+    return Real
+  }
+  return c
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) Since(t time.Time) time.Duration        { return time.Since(t) }
+func (realClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (realClock) NewTimer(d time.Duration) Timer {
+  return realTimer{time.NewTimer(d)}
+}
+
+func (realClock) NewTicker(d time.Duration) Ticker {
+  return realTicker{time.NewTicker(d)}
+}
+
+type realTimer struct {
+  *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time {
+  return t.Timer.C
+}
+
+type realTicker struct {
+  *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+  return t.Ticker.C
+}
@@ -0,0 +1,187 @@
+package clock
+
+import (
+  "sync"
+  "time"
+)
+
+// Fake is a clock whose time only passes when told to by Advance, firing in order the
+// timers, tickers and sleeps due on the way. A test pacing a stream by the second thus
+// runs in microseconds:
+//
+//	clk := clock.NewFake(time.Now())
+//	go stream() // sleeps for a second between the messages.
+//	clk.BlockUntil(1)
+//	clk.Advance(time.Second)
+type Fake struct {
+  mu      sync.Mutex
+  changed *sync.Cond // broadcast when the waiters change.
+  now     time.Time
+  waiters []*waiter
+}
+
+// waiter is a timer, a ticker or a sleep of a Fake clock.
+type waiter struct {
+  f      *Fake
+  when   time.Time
+  period time.Duration // of a ticker, 0 for the others.
+  c      chan time.Time
+}
+
+// NewFake returns a fake clock telling now until advanced.
+func NewFake(now time.Time) *Fake {
+  f := &Fake{now: now}
+  f.changed = sync.NewCond(&f.mu)
+  return f
+}
+
+func (f *Fake) Now() time.Time {
+  f.mu.Lock()
+  defer f.mu.Unlock()
+  return f.now
+}
+
+func (f *Fake) Since(t time.Time) time.Duration {
+  return f.Now().Sub(t)
+}
+
+func (f *Fake) Sleep(d time.Duration) {
+  <-f.After(d)
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+  return f.NewTimer(d).C()
+}
+
+func (f *Fake) NewTimer(d time.Duration) Timer {
+  w := &waiter{f: f, c: make(chan time.Time, 1)}
+  f.mu.Lock()
+  defer f.mu.Unlock()
+  f.schedule(w, d)
+  return fakeTimer{w}
+}
+
+// NewTicker panics when d is not positive, as time.NewTicker does.
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+  if d <= 0 {
+    panic("clock: non-positive interval for NewTicker")
+  }
+  w := &waiter{f: f, period: d, c: make(chan time.Time, 1)}
+  f.mu.Lock()
+  defer f.mu.Unlock()
+  f.schedule(w, d)
+  return fakeTicker{w}
+}
+
+// Advance moves the time forward by d, firing the timers, tickers and sleeps due on the
+// way in order. As with package time, a tick is dropped when the previous one was not
+// received yet.
+func (f *Fake) Advance(d time.Duration) {
+  f.mu.Lock()
+  defer f.mu.Unlock()
+  end := f.now.Add(d)
+  for {
+    var next *waiter
+    for _, w := range f.waiters {
+      if !w.when.After(end) && (next == nil || w.when.Before(next.when)) {
+        next = w
+      }
+    }
+    if next == nil {
+      break
+    }
+    f.now = next.when
+    f.fire(next)
+  }
+  f.now = end
+  f.changed.Broadcast()
+}
+
+// Waiters returns how many timers, tickers and sleeps wait on the clock.
+func (f *Fake) Waiters() int {
+  f.mu.Lock()
+  defer f.mu.Unlock()
+  return len(f.waiters)
+}
+
+// BlockUntil waits for at least n timers, tickers and sleeps to wait on the clock, so
+// that a test advances the clock once the code under test waits on it.
+func (f *Fake) BlockUntil(n int) {
+  f.mu.Lock()
+  defer f.mu.Unlock()
+  for len(f.waiters) < n {
+    f.changed.Wait()
+  }
+}
+
+// schedule arms w to fire after d, at once when d is not positive. f.mu must be held.
+func (f *Fake) schedule(w *waiter, d time.Duration) {
+  w.when = f.now.Add(d)
+  if d <= 0 && w.period == 0 {
+    f.fire(w)
+    return
+  }
+  f.waiters = append(f.waiters, w)
+  f.changed.Broadcast()
+}
+
+// fire sends the time on w, rearming it when it is a ticker. f.mu must be held.
+func (f *Fake) fire(w *waiter) {
+  select {
+  case w.c <- f.now:
+  default:
+  }
+  if w.period > 0 {
+    w.when = w.when.Add(w.period)
+    return
+  }
+  f.remove(w)
+}
+
+// remove disarms w, returning whether it was armed. f.mu must be held.
+func (f *Fake) remove(w *waiter) bool {
+  for i, other := range f.waiters {
+    if other == w {
+      f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+      f.changed.Broadcast()
+      return true
+    }
+  }
+  return false
+}
+
+type fakeTimer struct {
+  w *waiter
+}
+
+func (t fakeTimer) C() <-chan time.Time {
+  return t.w.c
+}
+
+func (t fakeTimer) Stop() bool {
+  t.w.f.mu.Lock()
+  defer t.w.f.mu.Unlock()
+  return t.w.f.remove(t.w)
+}
+
+func (t fakeTimer) Reset(d time.Duration) bool {
+  t.w.f.mu.Lock()
+  defer t.w.f.mu.Unlock()
+  active := t.w.f.remove(t.w)
+  t.w.f.schedule(t.w, d)
+  return active
+}
+
+type fakeTicker struct {
+  w *waiter
+}
+
+func (t fakeTicker) C() <-chan time.Time {
+  return t.w.c
+}
+
+func (t fakeTicker) Stop() {
+  t.w.f.mu.Lock()
+  defer t.w.f.mu.Unlock()
+  t.w.f.remove(t.w)
+}
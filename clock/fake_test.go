@@ -0,0 +1,155 @@
+package clock
+
+import (
+  "testing"
+  "time"
+)
+
+var epoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fired returns the time sent on c, or false when there is none.
+func fired(c <-chan time.Time) (time.Time, bool) {
+  select {
+  case t := <-c:
+    return t, true
+  default:
+    return time.Time{}, false
+  }
+}
+
+func TestFakeNow(t *testing.T) {
+  f := NewFake(epoch)
+  start := f.Now()
+  f.Advance(90 * time.Second)
+  if got := f.Now(); !got.Equal(epoch.Add(90 * time.Second)) {
+    t.Fatalf("Now() = %v, want %v", got, epoch.Add(90*time.Second))
+  }
+  if got := f.Since(start); got != 90*time.Second {
+    t.Fatalf("Since() = %v, want 1m30s", got)
+  }
+}
+
+func TestFakeTimer(t *testing.T) {
+  f := NewFake(epoch)
+  timer := f.NewTimer(time.Second)
+
+  f.Advance(999 * time.Millisecond)
+  if _, ok := fired(timer.C()); ok {
+    t.Fatal("Timer fired before its time")
+  }
+  f.Advance(time.Millisecond)
+  if got, ok := fired(timer.C()); !ok || !got.Equal(epoch.Add(time.Second)) {
+    t.Fatalf("Timer fired %v, %v, want at %v", got, ok, epoch.Add(time.Second))
+  }
+  if f.Waiters() != 0 {
+    t.Fatalf("Waiters() = %v after the timer fired, want 0", f.Waiters())
+  }
+
+  // A stopped timer never fires, a reset one fires anew.
+  if timer.Stop() {
+    t.Fatal("Stop() of a fired timer = true, want false")
+  }
+  if timer.Reset(time.Second) {
+    t.Fatal("Reset() of a fired timer = true, want false")
+  }
+  if !timer.Stop() {
+    t.Fatal("Stop() of an armed timer = false, want true")
+  }
+  f.Advance(time.Hour)
+  if _, ok := fired(timer.C()); ok {
+    t.Fatal("Stopped timer fired")
+  }
+}
+
+func TestFakeTimerNotPositive(t *testing.T) {
+  f := NewFake(epoch)
+  for _, d := range []time.Duration{0, -time.Second} {
+    if _, ok := fired(f.NewTimer(d).C()); !ok {
+      t.Errorf("Timer of %v did not fire at once", d)
+    }
+  }
+  if f.Waiters() != 0 {
+    t.Fatalf("Waiters() = %v, want 0", f.Waiters())
+  }
+}
+
+func TestFakeAdvanceInOrder(t *testing.T) {
+  f := NewFake(epoch)
+  late := f.NewTimer(3 * time.Second)
+  early := f.NewTimer(time.Second)
+
+  // Each timer fires at its own time, even when a single Advance goes past both.
+  f.Advance(time.Minute)
+  if got, _ := fired(early.C()); !got.Equal(epoch.Add(time.Second)) {
+    t.Errorf("Early timer fired at %v, want %v", got, epoch.Add(time.Second))
+  }
+  if got, _ := fired(late.C()); !got.Equal(epoch.Add(3 * time.Second)) {
+    t.Errorf("Late timer fired at %v, want %v", got, epoch.Add(3*time.Second))
+  }
+  if got := f.Now(); !got.Equal(epoch.Add(time.Minute)) {
+    t.Errorf("Now() = %v, want %v", got, epoch.Add(time.Minute))
+  }
+}
+
+func TestFakeTicker(t *testing.T) {
+  f := NewFake(epoch)
+  ticker := f.NewTicker(time.Second)
+
+  for i := 1; i <= 3; i++ {
+    f.Advance(time.Second)
+    if got, ok := fired(ticker.C()); !ok || !got.Equal(epoch.Add(time.Duration(i)*time.Second)) {
+      t.Fatalf("Tick %v = %v, %v, want %v", i, got, ok, epoch.Add(time.Duration(i)*time.Second))
+    }
+  }
+
+  // The ticks not received are dropped but the first one.
+  f.Advance(5 * time.Second)
+  if got, _ := fired(ticker.C()); !got.Equal(epoch.Add(4 * time.Second)) {
+    t.Fatalf("Tick kept = %v, want %v", got, epoch.Add(4*time.Second))
+  }
+  if _, ok := fired(ticker.C()); ok {
+    t.Fatal("Ticker kept more than one tick")
+  }
+
+  ticker.Stop()
+  f.Advance(time.Minute)
+  if _, ok := fired(ticker.C()); ok {
+    t.Fatal("Stopped ticker ticked")
+  }
+}
+
+func TestFakeTickerNotPositive(t *testing.T) {
+  defer func() {
+    if recover() == nil {
+      t.Fatal("NewTicker(0) did not panic")
+    }
+  }()
+  NewFake(epoch).NewTicker(0)
+}
+
+func TestFakeSleep(t *testing.T) {
+  f := NewFake(epoch)
+  done := make(chan struct{})
+  go func() {
+    f.Sleep(time.Second)
+    close(done)
+  }()
+
+  f.BlockUntil(1)
+  f.Advance(time.Second)
+  select {
+  case <-done:
+  case <-time.After(5 * time.Second):
+    t.Fatal("Sleep() did not return once the time passed")
+  }
+}
+
+func TestOr(t *testing.T) {
+  if Or(nil) != Real {
+    t.Error("Or(nil) is not the real clock")
+  }
+  f := NewFake(epoch)
+  if Or(f) != f {
+    t.Error("Or(f) is not f")
+  }
+}
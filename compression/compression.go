@@ -0,0 +1,154 @@
+// Package compression registers the gzip and zstd compressors with gRPC, counting how
+// much they save, and picks the compressor of the calls by method, leaving the small
+// unary messages uncompressed.
+package compression
+
+import (
+  "context"
+  "fmt"
+  "strings"
+
+  "github.com/_dev/grpc-go-example/logging"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/encoding"
+  "google.golang.org/grpc/encoding/gzip"
+  "google.golang.org/protobuf/proto"
+  "google.golang.org/protobuf/protoadapt"
+)
+
+const (
+  // Gzip and Zstd name the compressors.
+  Gzip = gzip.Name
+  Zstd = "zstd"
+
+  // None sends the messages uncompressed.
+  None = encoding.Identity
+
+  // DefaultMethod is the rule name applying to the methods without a rule of their own.
+  DefaultMethod = "*"
+)
+
+// ParseMethods reads the compressors of the methods written as "method=compressor",
+// separated by commas, as in "/greet.GreetService/LongGreet=zstd,*=gzip".
+func ParseMethods(s string) (map[string]string, error) {
+  methods := make(map[string]string)
+  for _, part := range strings.Split(s, ",") {
+    part = strings.TrimSpace(part)
+    if part == "" {
+      continue
+    }
+    i := strings.LastIndex(part, "=")
+    if i <= 0 {
+      return nil, fmt.Errorf("invalid compression rule %q, expected method=compressor", part)
+    }
+    name := part[i+1:]
+    if _, ok := compressors[name]; !ok && name != None {
+      return nil, fmt.Errorf("unknown compressor in rule %q, expected one of %v or %v", part, strings.Join(Names(), ", "), None)
+    }
+    methods[part[:i]] = name
+  }
+  return methods, nil
+}
+
+// Policy picks the compressor of the messages of each method.
+type Policy struct {
+  // Methods are the compressors by full method name, the DefaultMethod one applies to
+  // the other methods. Without any, the calls are left as they are: sent uncompressed by
+  // the clients and answered by the servers with the compressor of the call.
+  Methods map[string]string
+
+  // MinSize is the size, in bytes, under which unary messages are sent uncompressed. The
+  // compressor of a stream is picked before its first message, it applies to them all.
+  MinSize int
+}
+
+// compressor returns the compressor of the messages of method, given their size or -1
+// for a stream, and whether the policy has one.
+func (p Policy) compressor(method string, size int) (string, bool) {
+  name, ok := p.Methods[method]
+  if !ok {
+    name, ok = p.Methods[DefaultMethod]
+  }
+  if !ok {
+    return "", false
+  }
+  if size >= 0 && size < p.MinSize {
+    return None, true
+  }
+  return name, true
+}
+
+// WithCompression installs p on the unary calls and streams of a connection. A compressor
+// given to a call with grpc.UseCompressor is kept.
+func WithCompression(p Policy) []grpc.DialOption {
+  return []grpc.DialOption{
+    grpc.WithChainUnaryInterceptor(p.UnaryClientInterceptor),
+    grpc.WithChainStreamInterceptor(p.StreamClientInterceptor),
+  }
+}
+
+// UnaryClientInterceptor compresses the requests of the calls as the policy tells.
+func (p Policy) UnaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+  if name, ok := p.compressor(method, size(req)); ok && !hasCompressor(opts) {
+    opts = append(opts, grpc.UseCompressor(name))
+  }
+  return invoker(ctx, method, req, reply, cc, opts...)
+}
+
+// StreamClientInterceptor compresses the messages of the streams as the policy tells.
+func (p Policy) StreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+  if name, ok := p.compressor(method, -1); ok && !hasCompressor(opts) {
+    opts = append(opts, grpc.UseCompressor(name))
+  }
+  return streamer(ctx, desc, cc, method, opts...)
+}
+
+// UnaryServerInterceptor compresses the responses as the policy tells, when the client
+// accepts the compressor.
+func (p Policy) UnaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  resp, err := handler(ctx, req)
+  if err == nil {
+    if name, ok := p.compressor(info.FullMethod, size(resp)); ok {
+      setSendCompressor(ctx, info.FullMethod, name)
+    }
+  }
+  return resp, err
+}
+
+// StreamServerInterceptor is the stream counterpart of UnaryServerInterceptor.
+func (p Policy) StreamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  if name, ok := p.compressor(info.FullMethod, -1); ok {
+    setSendCompressor(ss.Context(), info.FullMethod, name)
+  }
+  return handler(srv, ss)
+}
+
+// setSendCompressor falls back on the compressor of the call when the client does not
+// accept name.
+func setSendCompressor(ctx context.Context, method, name string) {
+  if err := grpc.SetSendCompressor(ctx, name); err != nil {
+    logging.Debugf("Not compressing the responses of %v with %v: %v", method, name, err)
+  }
+}
+
+func hasCompressor(opts []grpc.CallOption) bool {
+  for _, opt := range opts {
+    if _, ok := opt.(grpc.CompressorCallOption); ok {
+      return true
+    }
+  }
+  return false
+}
+
+// size returns the encoded size of m, also accepting the messages generated by the older
+// protoc-gen-go, or -1 when m is not a message.
+func size(m interface{}) int {
+  switch m := m.(type) {
+  case proto.Message:
+    return proto.Size(m)
+  case protoadapt.MessageV1:
+    return proto.Size(protoadapt.MessageV2Of(m))
+  }
+  return -1
+}
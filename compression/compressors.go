@@ -0,0 +1,209 @@
+package compression
+
+import (
+  "io"
+  "sort"
+  "sync"
+  "sync/atomic"
+
+  "github.com/klauspost/compress/zstd"
+  "google.golang.org/grpc/encoding"
+  "google.golang.org/grpc/encoding/gzip"
+)
+
+// compressors are the counted compressors, by name.
+var compressors = map[string]*counted{}
+
+func init() {
+  register(encoding.GetCompressor(gzip.Name))
+  register(&zstdCompressor{})
+}
+
+// register replaces the compressor registered with gRPC under the name of c by c counted.
+func register(c encoding.Compressor) {
+  counted := &counted{Compressor: c}
+  compressors[c.Name()] = counted
+  encoding.RegisterCompressor(counted)
+}
+
+// Names returns the names of the compressors, sorted.
+func Names() []string {
+  names := make([]string, 0, len(compressors))
+  for name := range compressors {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  return names
+}
+
+// Counts are the messages gone through a compressor one way, with their bytes before
+// (Raw) and after (Wire) compression.
+type Counts struct {
+  Messages  int64
+  RawBytes  int64
+  WireBytes int64
+  Ratio     float64 // RawBytes over WireBytes, 0 until a message went through.
+}
+
+// Stats are the messages compressed, sent, and decompressed, received, by a compressor.
+type Stats struct {
+  Compressed   Counts
+  Decompressed Counts
+}
+
+// AllStats returns a snapshot of the counts of the compressors, by name.
+func AllStats() map[string]Stats {
+  stats := make(map[string]Stats, len(compressors))
+  for name, c := range compressors {
+    stats[name] = Stats{
+      Compressed:   c.compressed.snapshot(),
+      Decompressed: c.decompressed.snapshot(),
+    }
+  }
+  return stats
+}
+
+type counter struct {
+  messages int64
+  raw      int64
+  wire     int64
+}
+
+func (c *counter) snapshot() Counts {
+  counts := Counts{
+    Messages:  atomic.LoadInt64(&c.messages),
+    RawBytes:  atomic.LoadInt64(&c.raw),
+    WireBytes: atomic.LoadInt64(&c.wire),
+  }
+  if counts.WireBytes > 0 {
+    counts.Ratio = float64(counts.RawBytes) / float64(counts.WireBytes)
+  }
+  return counts
+}
+
+// counted counts the bytes going through a compressor as they flow.
+type counted struct {
+  encoding.Compressor
+  compressed   counter
+  decompressed counter
+}
+
+func (c *counted) Compress(w io.Writer) (io.WriteCloser, error) {
+  zw, err := c.Compressor.Compress(&countingWriter{Writer: w, n: &c.compressed.wire})
+  if err != nil {
+    return nil, err
+  }
+  atomic.AddInt64(&c.compressed.messages, 1)
+  return &countingWriteCloser{WriteCloser: zw, n: &c.compressed.raw}, nil
+}
+
+func (c *counted) Decompress(r io.Reader) (io.Reader, error) {
+  zr, err := c.Compressor.Decompress(&countingReader{Reader: r, n: &c.decompressed.wire})
+  if err != nil {
+    return nil, err
+  }
+  atomic.AddInt64(&c.decompressed.messages, 1)
+  return &countingReader{Reader: zr, n: &c.decompressed.raw}, nil
+}
+
+type countingWriter struct {
+  io.Writer
+  n *int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+  n, err := w.Writer.Write(p)
+  atomic.AddInt64(w.n, int64(n))
+  return n, err
+}
+
+type countingWriteCloser struct {
+  io.WriteCloser
+  n *int64
+}
+
+func (w *countingWriteCloser) Write(p []byte) (int, error) {
+  n, err := w.WriteCloser.Write(p)
+  atomic.AddInt64(w.n, int64(n))
+  return n, err
+}
+
+type countingReader struct {
+  io.Reader
+  n *int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+  n, err := r.Reader.Read(p)
+  atomic.AddInt64(r.n, int64(n))
+  return n, err
+}
+
+// zstdCompressor reuses its encoders and decoders, which are costly to create.
+type zstdCompressor struct {
+  encoders sync.Pool
+  decoders sync.Pool
+}
+
+func (*zstdCompressor) Name() string {
+  return Zstd
+}
+
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+  enc, ok := c.encoders.Get().(*zstd.Encoder)
+  if !ok {
+    var err error
+    enc, err = zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+    if err != nil {
+      return nil, err
+    }
+  } else {
+    enc.Reset(w)
+  }
+  return &zstdWriter{Encoder: enc, pool: &c.encoders}, nil
+}
+
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+  dec, ok := c.decoders.Get().(*zstd.Decoder)
+  if !ok {
+    var err error
+    dec, err = zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+    if err != nil {
+      return nil, err
+    }
+  } else if err := dec.Reset(r); err != nil {
+    c.decoders.Put(dec)
+    return nil, err
+  }
+  return &zstdReader{Decoder: dec, pool: &c.decoders}, nil
+}
+
+// zstdWriter returns its encoder to the pool once closed.
+type zstdWriter struct {
+  *zstd.Encoder
+  pool *sync.Pool
+}
+
+func (w *zstdWriter) Close() error {
+  err := w.Encoder.Close()
+  w.pool.Put(w.Encoder)
+  return err
+}
+
+// zstdReader returns its decoder to the pool once the message was read whole.
+type zstdReader struct {
+  *zstd.Decoder
+  pool *sync.Pool
+}
+
+func (r *zstdReader) Read(p []byte) (int, error) {
+  if r.Decoder == nil {
+    return 0, io.EOF
+  }
+  n, err := r.Decoder.Read(p)
+  if err == io.EOF {
+    r.pool.Put(r.Decoder)
+    r.Decoder = nil
+  }
+  return n, err
+}
@@ -0,0 +1,108 @@
+package compression
+
+import (
+  "bytes"
+  "fmt"
+  "io"
+  "strings"
+  "sync"
+  "testing"
+)
+
+// roundTrip compresses then decompresses message with c.
+func roundTrip(c interface {
+  Compress(w io.Writer) (io.WriteCloser, error)
+  Decompress(r io.Reader) (io.Reader, error)
+}, message string) (wire int, got string, err error) {
+  var buf bytes.Buffer
+  w, err := c.Compress(&buf)
+  if err != nil {
+    return 0, "", err
+  }
+  if _, err := io.WriteString(w, message); err != nil {
+    return 0, "", err
+  }
+  if err := w.Close(); err != nil {
+    return 0, "", err
+  }
+  wire = buf.Len()
+  r, err := c.Decompress(&buf)
+  if err != nil {
+    return 0, "", err
+  }
+  b, err := io.ReadAll(r)
+  return wire, string(b), err
+}
+
+func TestZstdPool(t *testing.T) {
+  c := &zstdCompressor{}
+  // The pooled encoders and decoders, reset from a message to the next, keep nothing
+  // of the previous ones, also when used concurrently.
+  var wg sync.WaitGroup
+  for g := 0; g < 4; g++ {
+    wg.Add(1)
+    go func(g int) {
+      defer wg.Done()
+      for i := 0; i < 50; i++ {
+        message := strings.Repeat(fmt.Sprintf("message %v of %v ", i, g), i+1)
+        if _, got, err := roundTrip(c, message); err != nil || got != message {
+          t.Errorf("Round trip of %q = %q, %v", message, got, err)
+          return
+        }
+      }
+    }(g)
+  }
+  wg.Wait()
+
+  // An encoder closed is handed to the next message, the pool dropping some at times.
+  reused := false
+  for i := 0; i < 100 && !reused; i++ {
+    w, err := c.Compress(io.Discard)
+    if err != nil {
+      t.Fatal(err)
+    }
+    enc := w.(*zstdWriter).Encoder
+    w.Close()
+    w, err = c.Compress(io.Discard)
+    if err != nil {
+      t.Fatal(err)
+    }
+    reused = w.(*zstdWriter).Encoder == enc
+    w.Close()
+  }
+  if !reused {
+    t.Fatal("Compress() never reused the encoder of the previous message")
+  }
+}
+
+func TestCounted(t *testing.T) {
+  c := &counted{Compressor: &zstdCompressor{}}
+  message := strings.Repeat("Hello Felipe! ", 1000)
+  wire, got, err := roundTrip(c, message)
+  if err != nil || got != message {
+    t.Fatalf("Round trip = %q, %v", got, err)
+  }
+
+  want := Counts{Messages: 1, RawBytes: int64(len(message)), WireBytes: int64(wire), Ratio: float64(len(message)) / float64(wire)}
+  if got := c.compressed.snapshot(); got != want {
+    t.Errorf("Compressed = %+v, want %+v", got, want)
+  }
+  if got := c.decompressed.snapshot(); got != want {
+    t.Errorf("Decompressed = %+v, want %+v", got, want)
+  }
+  if want.Ratio < 10 {
+    t.Errorf("Ratio = %v, want a repeated message compressed at least 10 times", want.Ratio)
+  }
+}
+
+func TestParseMethods(t *testing.T) {
+  methods, err := ParseMethods("/greet.GreetService/LongGreet=zstd, *=gzip, /greet.GreetService/Greet=identity")
+  if err != nil || len(methods) != 3 || methods["/greet.GreetService/LongGreet"] != Zstd || methods[DefaultMethod] != Gzip || methods["/greet.GreetService/Greet"] != None {
+    t.Fatalf("ParseMethods() = %v, %v", methods, err)
+  }
+  for _, s := range []string{"zstd", "=zstd", "*=lz4"} {
+    if _, err := ParseMethods(s); err == nil {
+      t.Errorf("ParseMethods(%q) = nil error, want one", s)
+    }
+  }
+}
@@ -0,0 +1,140 @@
+package compression_test
+
+import (
+  "context"
+  "io"
+  "reflect"
+  "strings"
+  "testing"
+
+  "github.com/_dev/grpc-go-example/compression"
+  "github.com/_dev/grpc-go-example/greet/greetpb"
+  "github.com/_dev/grpc-go-example/grpctest"
+
+  "google.golang.org/grpc"
+)
+
+// newCompressedGreet serves the greet service with p, to a client with p.
+func newCompressedGreet(t *testing.T, p compression.Policy) greetpb.GreetServiceClient {
+  g := grpctest.NewGreet(t, grpctest.GreetConfig(), grpctest.Options{
+    UnaryInterceptors:  []grpc.UnaryServerInterceptor{p.UnaryServerInterceptor},
+    StreamInterceptors: []grpc.StreamServerInterceptor{p.StreamServerInterceptor},
+  })
+  return greetpb.NewGreetServiceClient(g.Dial(t, compression.WithCompression(p)...))
+}
+
+// delta returns the messages each compressor compressed and decompressed while f ran.
+func delta(t *testing.T, f func()) map[string][2]int64 {
+  t.Helper()
+  before := compression.AllStats()
+  f()
+  moved := make(map[string][2]int64)
+  for name, after := range compression.AllStats() {
+    compressed := after.Compressed.Messages - before[name].Compressed.Messages
+    decompressed := after.Decompressed.Messages - before[name].Decompressed.Messages
+    if compressed != 0 || decompressed != 0 {
+      moved[name] = [2]int64{compressed, decompressed}
+    }
+  }
+  return moved
+}
+
+func greet(t *testing.T, c greetpb.GreetServiceClient, name string) {
+  t.Helper()
+  res, err := c.Greet(context.Background(), &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: name}})
+  if err != nil || !strings.Contains(res.GetResult(), name) {
+    t.Fatalf("Greet() = %v, %v, want the greeting of the name", res, err)
+  }
+}
+
+// greetMany receives the greetings of GreetManyTimes, returning how many.
+func greetMany(t *testing.T, c greetpb.GreetServiceClient, name string) int64 {
+  t.Helper()
+  stream, err := c.GreetManyTimes(context.Background(), &greetpb.GreetManyTimesRequest{Greeting: &greetpb.Greeting{FirstName: name}})
+  if err != nil {
+    t.Fatal(err)
+  }
+  var n int64
+  for {
+    res, err := stream.Recv()
+    if err == io.EOF {
+      return n
+    }
+    if err != nil || !strings.Contains(res.GetResult(), name) {
+      t.Fatalf("GreetManyTimes() = %v, %v, want the greetings of the name", res, err)
+    }
+    n++
+  }
+}
+
+func TestRoundTrip(t *testing.T) {
+  name := strings.Repeat("Felipe", 1000)
+  for _, compressor := range compression.Names() {
+    t.Run(compressor, func(t *testing.T) {
+      c := newCompressedGreet(t, compression.Policy{Methods: map[string]string{compression.DefaultMethod: compressor}})
+      before := compression.AllStats()[compressor]
+
+      // The request and the response are compressed then decompressed, in this process.
+      moved := delta(t, func() { greet(t, c, name) })
+      if want := map[string][2]int64{compressor: {2, 2}}; moved[compressor] != want[compressor] || len(moved) != 1 {
+        t.Fatalf("Messages compressed and decompressed = %v, want %v", moved, want)
+      }
+      after := compression.AllStats()[compressor]
+      raw := after.Compressed.RawBytes - before.Compressed.RawBytes
+      wire := after.Compressed.WireBytes - before.Compressed.WireBytes
+      if raw < int64(2*len(name)) || wire*10 > raw {
+        t.Fatalf("Compressed %v bytes into %v, want the repeated name compressed at least 10 times", raw, wire)
+      }
+      if after.Compressed.Ratio <= 1 || after.Decompressed.Ratio <= 1 {
+        t.Fatalf("Ratio = %v and %v, want over 1", after.Compressed.Ratio, after.Decompressed.Ratio)
+      }
+    })
+  }
+}
+
+func TestMinSize(t *testing.T) {
+  c := newCompressedGreet(t, compression.Policy{Methods: map[string]string{compression.DefaultMethod: compression.Gzip}, MinSize: 1024})
+
+  // The small unary messages are sent as they are, the large ones compressed.
+  if moved := delta(t, func() { greet(t, c, "Felipe") }); len(moved) != 0 {
+    t.Fatalf("Messages compressed under MinSize = %v, want none", moved)
+  }
+  if moved := delta(t, func() { greet(t, c, strings.Repeat("Felipe", 1000)) }); moved[compression.Gzip] != [2]int64{2, 2} {
+    t.Fatalf("Messages compressed over MinSize = %v, want 2 each way with gzip", moved)
+  }
+  // The messages of the streams are all compressed, whatever their size.
+  var n int64
+  moved := delta(t, func() { n = greetMany(t, c, "Felipe") })
+  if want := [2]int64{n + 1, n + 1}; moved[compression.Gzip] != want {
+    t.Fatalf("Messages compressed on a stream = %v, want %v each way with gzip", moved, want)
+  }
+}
+
+func TestMethods(t *testing.T) {
+  c := newCompressedGreet(t, compression.Policy{Methods: map[string]string{
+    "/greet.GreetService/Greet": compression.Zstd,
+    compression.DefaultMethod:   compression.Gzip,
+  }})
+  name := strings.Repeat("Felipe", 1000)
+
+  if moved := delta(t, func() { greet(t, c, name) }); len(moved) != 1 || moved[compression.Zstd] != [2]int64{2, 2} {
+    t.Fatalf("Messages compressed by Greet = %v, want 2 each way with zstd", moved)
+  }
+  var n int64
+  moved := delta(t, func() { n = greetMany(t, c, name) })
+  if want := [2]int64{n + 1, n + 1}; len(moved) != 1 || moved[compression.Gzip] != want {
+    t.Fatalf("Messages compressed by GreetManyTimes = %v, want %v each way with gzip", moved, want)
+  }
+
+  // A compressor given to the call is kept for the request, the response following the
+  // policy.
+  moved = delta(t, func() {
+    _, err := c.Greet(context.Background(), &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: name}}, grpc.UseCompressor(compression.Gzip))
+    if err != nil {
+      t.Fatal(err)
+    }
+  })
+  if want := map[string][2]int64{compression.Gzip: {1, 1}, compression.Zstd: {1, 1}}; !reflect.DeepEqual(moved, want) {
+    t.Fatalf("Messages compressed by Greet with gzip = %v, want %v", moved, want)
+  }
+}
@@ -0,0 +1,295 @@
+// Package config loads the typed configuration of the servers from, by increasing
+// precedence, its defaults, a YAML or TOML file, environment variables and flags.
+//
+// The fields of a configuration struct are named by their config tag, with nested
+// structs making sections: the field tagged "max_age" of the section tagged "history"
+// is history.max_age in the file, the -history-max-age flag and the
+// PREFIX_HISTORY_MAX_AGE environment variable. Fields are strings, bools, ints, floats
+// or durations, and their usage tag documents the flag. See Reloader for the reload tag.
+package config
+
+import (
+  "flag"
+  "fmt"
+  "io"
+  "os"
+  "path/filepath"
+  "reflect"
+  "strconv"
+  "strings"
+  "time"
+
+  "github.com/pelletier/go-toml/v2"
+  "gopkg.in/yaml.v3"
+)
+
+// Validator is a configuration checking its values once loaded.
+type Validator interface {
+  Validate() error
+}
+
+// Source remembers where a configuration was loaded from, to load it again.
+type Source struct {
+  envPrefix string
+  path      string
+  flags     map[string]string // the flags set on the command line.
+  defaults  reflect.Value
+}
+
+// Load parses args, the command line without the program name, and fills cfg, a
+// pointer to a struct holding the defaults. The file is named by the -config flag or
+// the PREFIX_CONFIG variable. With -print-config, the configuration is printed and
+// the program exits, as -h does.
+func Load(cfg Validator, envPrefix string, args []string) (*Source, error) {
+  v := reflect.ValueOf(cfg).Elem()
+  src := &Source{
+    envPrefix: envPrefix,
+    flags:     make(map[string]string),
+    defaults:  reflect.New(v.Type()).Elem(),
+  }
+  src.defaults.Set(v)
+
+  fs := flag.NewFlagSet(filepath.Base(os.Args[0]), flag.ExitOnError)
+  path := fs.String("config", "", "YAML or TOML configuration file, also read from "+envPrefix+"_CONFIG")
+  printConfig := fs.Bool("print-config", false, "print the configuration and exit")
+  walk(v, nil, func(path []string, f reflect.Value, field reflect.StructField) error {
+    fs.Var(value{f}, flagName(path), field.Tag.Get("usage"))
+    return nil
+  })
+  fs.Parse(args)
+  fs.Visit(func(f *flag.Flag) {
+    if f.Name != "config" && f.Name != "print-config" {
+      src.flags[f.Name] = f.Value.String()
+    }
+  })
+
+  src.path = *path
+  if src.path == "" {
+    src.path = os.Getenv(envPrefix + "_CONFIG")
+  }
+  if err := src.Load(cfg); err != nil {
+    return nil, err
+  }
+
+  if *printConfig {
+    if err := Print(os.Stdout, cfg); err != nil {
+      return nil, err
+    }
+    os.Exit(0)
+  }
+  return src, nil
+}
+
+// Path returns the configuration file, empty when there is none.
+func (src *Source) Path() string {
+  return src.path
+}
+
+// Load fills cfg from the defaults, the file, the environment and the flags, in that
+// order, and validates it.
+func (src *Source) Load(cfg Validator) error {
+  v := reflect.ValueOf(cfg).Elem()
+  v.Set(src.defaults)
+
+  if src.path != "" {
+    if err := loadFile(v, src.path); err != nil {
+      return err
+    }
+  }
+
+  err := walk(v, nil, func(path []string, f reflect.Value, _ reflect.StructField) error {
+    name := src.envPrefix + "_" + strings.ToUpper(strings.Join(path, "_"))
+    if s, ok := os.LookupEnv(name); ok {
+      if err := set(f, s); err != nil {
+        return fmt.Errorf("invalid %v: %v", name, err)
+      }
+    }
+    if s, ok := src.flags[flagName(path)]; ok {
+      return set(f, s)
+    }
+    return nil
+  })
+  if err != nil {
+    return err
+  }
+  return cfg.Validate()
+}
+
+// Print writes cfg as YAML, in the form read from the configuration files.
+func Print(w io.Writer, cfg interface{}) error {
+  b, err := yaml.Marshal(node(reflect.ValueOf(cfg).Elem()))
+  if err != nil {
+    return err
+  }
+  _, err = w.Write(b)
+  return err
+}
+
+func loadFile(v reflect.Value, path string) error {
+  b, err := os.ReadFile(path)
+  if err != nil {
+    return err
+  }
+  var m map[string]interface{}
+  switch strings.ToLower(filepath.Ext(path)) {
+  case ".yaml", ".yml":
+    err = yaml.Unmarshal(b, &m)
+  case ".toml":
+    err = toml.Unmarshal(b, &m)
+  default:
+    return fmt.Errorf("unknown configuration format %v, expecting .yaml, .yml or .toml", path)
+  }
+  if err != nil {
+    return fmt.Errorf("invalid configuration file %v: %v", path, err)
+  }
+  if err := apply(v, m, nil); err != nil {
+    return fmt.Errorf("invalid configuration file %v: %v", path, err)
+  }
+  return nil
+}
+
+// apply sets the fields of v from the values of a section of a file.
+func apply(v reflect.Value, m map[string]interface{}, path []string) error {
+  for key, val := range m {
+    f, ok := field(v, key)
+    if !ok {
+      return fmt.Errorf("unknown setting %v", strings.Join(append(path, key), "."))
+    }
+    name := strings.Join(append(path, key), ".")
+    if f.Kind() == reflect.Struct && f.Type() != durationType {
+      section, ok := val.(map[string]interface{})
+      if !ok {
+        return fmt.Errorf("%v is a section", name)
+      }
+      if err := apply(f, section, append(path, key)); err != nil {
+        return err
+      }
+      continue
+    }
+    if err := set(f, fmt.Sprint(val)); err != nil {
+      return fmt.Errorf("invalid %v: %v", name, err)
+    }
+  }
+  return nil
+}
+
+func field(v reflect.Value, key string) (reflect.Value, bool) {
+  for i := 0; i < v.NumField(); i++ {
+    if v.Type().Field(i).Tag.Get("config") == key {
+      return v.Field(i), true
+    }
+  }
+  return reflect.Value{}, false
+}
+
+// walk calls fn with the leaf fields of v having a config tag, and their path.
+func walk(v reflect.Value, path []string, fn func(path []string, f reflect.Value, field reflect.StructField) error) error {
+  for i := 0; i < v.NumField(); i++ {
+    sf := v.Type().Field(i)
+    key := sf.Tag.Get("config")
+    if key == "" {
+      continue
+    }
+    p := append(append([]string(nil), path...), key)
+    f := v.Field(i)
+    var err error
+    if f.Kind() == reflect.Struct {
+      err = walk(f, p, fn)
+    } else {
+      err = fn(p, f, sf)
+    }
+    if err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+func flagName(path []string) string {
+  return strings.ReplaceAll(strings.Join(path, "-"), "_", "-")
+}
+
+func node(v reflect.Value) *yaml.Node {
+  n := &yaml.Node{Kind: yaml.MappingNode}
+  for i := 0; i < v.NumField(); i++ {
+    key := v.Type().Field(i).Tag.Get("config")
+    if key == "" {
+      continue
+    }
+    f := v.Field(i)
+    val := &yaml.Node{Kind: yaml.ScalarNode, Value: format(f)}
+    if f.Kind() == reflect.Struct {
+      val = node(f)
+    } else if f.Kind() == reflect.String {
+      val.Style = yaml.DoubleQuotedStyle
+    }
+    n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, val)
+  }
+  return n
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func set(f reflect.Value, s string) error {
+  if f.Type() == durationType {
+    d, err := time.ParseDuration(s)
+    if err != nil {
+      return err
+    }
+    f.SetInt(int64(d))
+    return nil
+  }
+
+  switch f.Kind() {
+  case reflect.String:
+    f.SetString(s)
+  case reflect.Bool:
+    b, err := strconv.ParseBool(s)
+    if err != nil {
+      return err
+    }
+    f.SetBool(b)
+  case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+    i, err := strconv.ParseInt(s, 10, f.Type().Bits())
+    if err != nil {
+      return err
+    }
+    f.SetInt(i)
+  case reflect.Float32, reflect.Float64:
+    x, err := strconv.ParseFloat(s, f.Type().Bits())
+    if err != nil {
+      return err
+    }
+    f.SetFloat(x)
+  default:
+    return fmt.Errorf("unsupported setting type %v", f.Type())
+  }
+  return nil
+}
+
+func format(f reflect.Value) string {
+  if f.Type() == durationType {
+    return time.Duration(f.Int()).String()
+  }
+  return fmt.Sprint(f.Interface())
+}
+
+// value binds a flag to a field.
+type value struct {
+  f reflect.Value
+}
+
+func (v value) String() string {
+  if !v.f.IsValid() {
+    return ""
+  }
+  return format(v.f)
+}
+
+func (v value) Set(s string) error {
+  return set(v.f, s)
+}
+
+func (v value) IsBoolFlag() bool {
+  return v.f.IsValid() && v.f.Kind() == reflect.Bool
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+  "bytes"
+  "path/filepath"
+  "strings"
+  "testing"
+  "time"
+)
+
+func TestLoad(t *testing.T) {
+  for _, tc := range []struct {
+    name  string
+    file  string // named config.<ext>, the extension before the first line.
+    env   map[string]string
+    args  []string
+    want  func(c *testConfig)
+    err   string
+    noArg bool // the file named by LOADTEST_CONFIG rather than -config.
+  }{
+    {
+      name: "defaults",
+      want: func(c *testConfig) {},
+    },
+    {
+      name: "yaml file",
+      file: "yaml\naddr: 127.0.0.1:1\ntimeout: 2s\nlimits:\n  rate: 2.5\nhistory:\n  enabled: false\n",
+      want: func(c *testConfig) {
+        c.Addr, c.Timeout, c.Limits.Rate, c.History.Enabled = "127.0.0.1:1", 2*time.Second, 2.5, false
+      },
+    },
+    {
+      name: "toml file",
+      file: "toml\naddr = \"127.0.0.1:1\"\ntimeout = \"2s\"\n[limits]\nburst = 4\n",
+      want: func(c *testConfig) {
+        c.Addr, c.Timeout, c.Limits.Burst = "127.0.0.1:1", 2*time.Second, 4
+      },
+    },
+    {
+      name:  "file of the environment",
+      file:  "yml\nlog_level: debug\n",
+      noArg: true,
+      want:  func(c *testConfig) { c.LogLevel = "debug" },
+    },
+    {
+      name: "environment over file",
+      file: "yaml\naddr: 127.0.0.1:1\nlimits:\n  rate: 2\n",
+      env:  map[string]string{"LOADTEST_ADDR": "127.0.0.1:2", "LOADTEST_LIMITS_RATE": "3"},
+      want: func(c *testConfig) { c.Addr, c.Limits.Rate = "127.0.0.1:2", 3 },
+    },
+    {
+      name: "flags over environment",
+      file: "yaml\naddr: 127.0.0.1:1\ntimeout: 2s\n",
+      env:  map[string]string{"LOADTEST_ADDR": "127.0.0.1:2", "LOADTEST_TIMEOUT": "3s", "LOADTEST_HISTORY_DB": "env.db"},
+      args: []string{"-addr", "127.0.0.1:3", "-limits-rate=4", "-history-enabled=false"},
+      want: func(c *testConfig) {
+        c.Addr, c.Timeout, c.History.DB, c.Limits.Rate, c.History.Enabled = "127.0.0.1:3", 3*time.Second, "env.db", 4, false
+      },
+    },
+    {
+      name: "unknown setting",
+      file: "yaml\nlimits:\n  rates: 2\n",
+      err:  "unknown setting limits.rates",
+    },
+    {
+      name: "setting for a section",
+      file: "yaml\nlimits: 2\n",
+      err:  "limits is a section",
+    },
+    {
+      name: "invalid file value",
+      file: "yaml\ntimeout: soon\n",
+      err:  "invalid timeout",
+    },
+    {
+      name: "invalid environment value",
+      env:  map[string]string{"LOADTEST_LIMITS_BURST": "many"},
+      err:  "invalid LOADTEST_LIMITS_BURST",
+    },
+    {
+      name: "unknown format",
+      file: "json\n{}\n",
+      err:  "unknown configuration format",
+    },
+    {
+      name: "validation",
+      args: []string{"-log-level", "loud"},
+      err:  `invalid log_level "loud"`,
+    },
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      for name, value := range tc.env {
+        t.Setenv(name, value)
+      }
+      args := tc.args
+      if tc.file != "" {
+        ext, content, _ := strings.Cut(tc.file, "\n")
+        path := filepath.Join(t.TempDir(), "config."+ext)
+        writeFile(t, path, content)
+        if tc.noArg {
+          t.Setenv("LOADTEST_CONFIG", path)
+        } else {
+          args = append([]string{"-config", path}, args...)
+        }
+      }
+
+      cfg := defaultTestConfig()
+      _, err := Load(&cfg, "LOADTEST", args)
+      if tc.err != "" {
+        if err == nil || !strings.Contains(err.Error(), tc.err) {
+          t.Fatalf("Load() = %v, want an error with %q", err, tc.err)
+        }
+        return
+      }
+      if err != nil {
+        t.Fatalf("Load() = %v", err)
+      }
+      want := defaultTestConfig()
+      tc.want(&want)
+      if cfg != want {
+        t.Fatalf("Load() = %+v, want %+v", cfg, want)
+      }
+    })
+  }
+}
+
+func TestPrint(t *testing.T) {
+  // The printed configuration loads back the same.
+  cfg := defaultTestConfig()
+  cfg.Limits.Rate = 2.5
+  var b bytes.Buffer
+  if err := Print(&b, &cfg); err != nil {
+    t.Fatal(err)
+  }
+  path := filepath.Join(t.TempDir(), "config.yaml")
+  writeFile(t, path, b.String())
+  var loaded testConfig
+  if _, err := Load(&loaded, "PRINTTEST", []string{"-config", path}); err != nil || loaded != cfg {
+    t.Fatalf("Load() of the printed configuration = %+v, %v, want %+v", loaded, err, cfg)
+  }
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+  "os"
+  "os/signal"
+  "reflect"
+  "strings"
+  "sync"
+  "syscall"
+  "time"
+
+  "github.com/_dev/grpc-go-example/logging"
+)
+
+// WatchInterval is how often the configuration files are checked for changes.
+var WatchInterval = 5 * time.Second
+
+// Reloader loads a configuration again, when asked, on SIGHUP or when its file changes.
+//
+// Only the settings tagged reload:"live", or within a section so tagged, change while
+// the server runs: the others keep their running value until a restart and are reported
+// as pending. A configuration failing to load or to validate is rejected, the running
+// one being kept.
+type Reloader struct {
+  src   *Source
+  apply func(Validator) error
+
+  mu      sync.Mutex
+  current Validator
+}
+
+// NewReloader reloads from src the configuration current, handing the new ones to apply,
+// which may still reject them.
+func NewReloader(src *Source, current Validator, apply func(Validator) error) *Reloader {
+  return &Reloader{src: src, apply: apply, current: current}
+}
+
+// Current returns the running configuration.
+func (r *Reloader) Current() Validator {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+  return r.current
+}
+
+// Reload loads and applies the configuration, returning it with the names of the changed
+// settings pending a restart.
+func (r *Reloader) Reload() (Validator, []string, error) {
+  r.mu.Lock()
+  defer r.mu.Unlock()
+
+  next := reflect.New(reflect.TypeOf(r.current).Elem()).Interface().(Validator)
+  if err := r.src.Load(next); err != nil {
+    return nil, nil, err
+  }
+  var pending []string
+  keep(reflect.ValueOf(r.current).Elem(), reflect.ValueOf(next).Elem(), nil, false, &pending)
+  if err := r.apply(next); err != nil {
+    return nil, nil, err
+  }
+  r.current = next
+  return next, pending, nil
+}
+
+// Watch reloads the configuration on SIGHUP and when its file changes, the outcome is logged.
+func (r *Reloader) Watch() {
+  hup := make(chan os.Signal, 1)
+  signal.Notify(hup, syscall.SIGHUP)
+
+  var modTime time.Time
+  if info, err := os.Stat(r.src.Path()); err == nil {
+    modTime = info.ModTime()
+  }
+  ticker := time.NewTicker(WatchInterval)
+  defer ticker.Stop()
+  for {
+    select {
+    case <-hup:
+    case <-ticker.C:
+      if r.src.Path() == "" {
+        continue
+      }
+      info, err := os.Stat(r.src.Path())
+      if err != nil || info.ModTime().Equal(modTime) {
+        continue
+      }
+      modTime = info.ModTime()
+    }
+
+    if _, pending, err := r.Reload(); err != nil {
+      logging.Errorf("Failed to reload the configuration, keeping the running one: %v", err)
+    } else if len(pending) > 0 {
+      logging.Infof("Reloaded the configuration, %v will apply after a restart.", strings.Join(pending, ", "))
+    } else {
+      logging.Infof("Reloaded the configuration.")
+    }
+  }
+}
+
+// keep copies the settings which are not live from cur to next, collecting the names of
+// those which changed.
+func keep(cur, next reflect.Value, path []string, live bool, pending *[]string) {
+  for i := 0; i < cur.NumField(); i++ {
+    sf := cur.Type().Field(i)
+    key := sf.Tag.Get("config")
+    if key == "" {
+      continue
+    }
+    p := append(append([]string(nil), path...), key)
+    isLive := live || sf.Tag.Get("reload") == "live"
+    if cur.Field(i).Kind() == reflect.Struct {
+      keep(cur.Field(i), next.Field(i), p, isLive, pending)
+      continue
+    }
+    if isLive || cur.Field(i).Interface() == next.Field(i).Interface() {
+      continue
+    }
+    next.Field(i).Set(cur.Field(i))
+    *pending = append(*pending, strings.Join(p, "."))
+  }
+}
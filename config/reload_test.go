@@ -0,0 +1,159 @@
+package config
+
+import (
+  "errors"
+  "fmt"
+  "os"
+  "path/filepath"
+  "reflect"
+  "testing"
+  "time"
+)
+
+// testConfig has live settings, alone and within a section, and settings pending a restart.
+type testConfig struct {
+  Addr     string        `config:"addr" usage:"address"`
+  LogLevel string        `config:"log_level" usage:"level" reload:"live"`
+  Timeout  time.Duration `config:"timeout" usage:"timeout"`
+  Limits   testLimits    `config:"limits" reload:"live"`
+  History  testHistory   `config:"history"`
+}
+
+type testLimits struct {
+  Rate  float64 `config:"rate" usage:"rate"`
+  Burst int     `config:"burst" usage:"burst"`
+}
+
+type testHistory struct {
+  DB      string `config:"db" usage:"database"`
+  Enabled bool   `config:"enabled" usage:"enabled"`
+}
+
+func defaultTestConfig() testConfig {
+  return testConfig{
+    Addr:     "0.0.0.0:50051",
+    LogLevel: "info",
+    Timeout:  time.Second,
+    Limits:   testLimits{Rate: 1, Burst: 1},
+    History:  testHistory{DB: "history.db", Enabled: true},
+  }
+}
+
+func (c *testConfig) Validate() error {
+  switch {
+  case c.Addr == "":
+    return errors.New("addr is required")
+  case c.LogLevel != "debug" && c.LogLevel != "info" && c.LogLevel != "error":
+    return fmt.Errorf("invalid log_level %q", c.LogLevel)
+  case c.Limits.Rate < 0:
+    return errors.New("limits.rate cannot be negative")
+  }
+  return nil
+}
+
+func writeFile(t *testing.T, path, content string) {
+  t.Helper()
+  if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+    t.Fatal(err)
+  }
+}
+
+// newTestReloader loads the configuration from the file content, counting the
+// configurations applied and rejecting them while reject is set.
+func newTestReloader(t *testing.T, content string) (r *Reloader, path string, applied *int, reject *bool) {
+  t.Helper()
+  path = filepath.Join(t.TempDir(), "config.yaml")
+  writeFile(t, path, content)
+  cfg := defaultTestConfig()
+  src, err := Load(&cfg, "RELOADTEST", []string{"-config", path})
+  if err != nil {
+    t.Fatal(err)
+  }
+  applied, reject = new(int), new(bool)
+  r = NewReloader(src, &cfg, func(v Validator) error {
+    if *reject {
+      return errors.New("rejected")
+    }
+    *applied++
+    return nil
+  })
+  return r, path, applied, reject
+}
+
+func TestReload(t *testing.T) {
+  r, path, applied, _ := newTestReloader(t, "log_level: info\n")
+  writeFile(t, path, "log_level: debug\nlimits:\n  rate: 5\n")
+
+  next, pending, err := r.Reload()
+  if err != nil {
+    t.Fatal(err)
+  }
+  want := defaultTestConfig()
+  want.LogLevel = "debug"
+  want.Limits.Rate = 5
+  if got := *next.(*testConfig); got != want || len(pending) != 0 {
+    t.Fatalf("Reload() = %+v, %v, want %+v without pending settings", got, pending, want)
+  }
+  if *applied != 1 || r.Current() != next {
+    t.Fatalf("Reload() applied %v configurations, current %+v, want the new one applied", *applied, r.Current())
+  }
+}
+
+func TestReloadInvalid(t *testing.T) {
+  for _, tc := range []struct {
+    name    string
+    content string
+    reject  bool
+  }{
+    {name: "syntax", content: "log_level: [debug\n"},
+    {name: "unknown setting", content: "log_levels: debug\n"},
+    {name: "invalid value", content: "timeout: soon\n"},
+    {name: "validation", content: "log_level: loud\n"},
+    {name: "rejected", content: "log_level: debug\n", reject: true},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      r, path, applied, reject := newTestReloader(t, "log_level: error\n")
+      current := r.Current()
+      writeFile(t, path, tc.content)
+      *reject = tc.reject
+
+      // The running configuration is kept, as it was.
+      if _, _, err := r.Reload(); err == nil {
+        t.Fatal("Reload() = nil error, want one")
+      }
+      if r.Current() != current || current.(*testConfig).LogLevel != "error" || *applied != 0 {
+        t.Fatalf("Current() = %+v after a failed reload, want the running one", r.Current())
+      }
+    })
+  }
+}
+
+func TestReloadPending(t *testing.T) {
+  r, path, applied, _ := newTestReloader(t, "addr: 127.0.0.1:1\n")
+  writeFile(t, path, "addr: 127.0.0.1:2\nlog_level: debug\ntimeout: 2s\nhistory:\n  db: other.db\nlimits:\n  burst: 3\n")
+
+  // Only the live settings change, the others keep their running value and are reported.
+  next, pending, err := r.Reload()
+  if err != nil {
+    t.Fatal(err)
+  }
+  want := defaultTestConfig()
+  want.Addr = "127.0.0.1:1"
+  want.LogLevel = "debug"
+  want.Limits.Burst = 3
+  if got := *next.(*testConfig); got != want {
+    t.Fatalf("Reload() = %+v, want %+v", got, want)
+  }
+  if want := []string{"addr", "timeout", "history.db"}; !reflect.DeepEqual(pending, want) {
+    t.Fatalf("Reload() pending = %v, want %v", pending, want)
+  }
+  if *applied != 1 {
+    t.Fatalf("Reload() applied %v configurations, want 1", *applied)
+  }
+
+  // They stay pending until the restart.
+  _, pending, err = r.Reload()
+  if err != nil || !reflect.DeepEqual(pending, []string{"addr", "timeout", "history.db"}) {
+    t.Fatalf("Reload() again pending = %v, %v, want the same settings", pending, err)
+  }
+}
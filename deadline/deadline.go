@@ -0,0 +1,104 @@
+// Package deadline bounds the deadlines of the calls received by a server: the calls
+// arriving without a deadline are given a default one, the others are cut to a maximum.
+package deadline
+
+import (
+  "context"
+  "time"
+
+  "github.com/_dev/grpc-go-example/logging"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/status"
+)
+
+// Bounds are the deadlines given to the calls, zero values mean no bound.
+type Bounds struct {
+  // Default is the deadline of the calls arriving without one, Max when not set or
+  // longer.
+  Default time.Duration
+
+  // Max caps the deadline of every call.
+  Max time.Duration
+}
+
+// bound returns the context of a call with the bounds applied.
+func (b Bounds) bound(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+  if d, ok := ctx.Deadline(); ok {
+    if left := time.Until(d); b.Max > 0 && left > b.Max {
+      logging.Debugf("Shortening the deadline of %v from %v to %v.", method, left, b.Max)
+      return context.WithTimeout(ctx, b.Max)
+    }
+    return ctx, func() {}
+  }
+
+  timeout := b.Default
+  if timeout <= 0 || (b.Max > 0 && timeout > b.Max) {
+    timeout = b.Max
+  }
+  if timeout <= 0 {
+    return ctx, func() {}
+  }
+  logging.Debugf("Giving %v a deadline of %v.", method, timeout)
+  return context.WithTimeout(ctx, timeout)
+}
+
+// Limiter applies its Bounds to the calls of a server, one for the unary calls and one
+// for the streams, which usually live longer.
+type Limiter struct {
+  unary  Bounds
+  stream Bounds
+}
+
+// New creates a limiter bounding the unary calls by unary and the streams by stream.
+func New(unary, stream Bounds) *Limiter {
+  return &Limiter{unary: unary, stream: stream}
+}
+
+// UnaryInterceptor runs the calls with their deadline bounded.
+func (l *Limiter) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  ctx, cancel := l.unary.bound(ctx, info.FullMethod)
+  defer cancel()
+  return handler(ctx, req)
+}
+
+// StreamInterceptor runs the streams with their deadline bounded.
+func (l *Limiter) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  ctx, cancel := l.stream.bound(ss.Context(), info.FullMethod)
+  defer cancel()
+  if ctx == ss.Context() {
+    return handler(srv, ss)
+  }
+  return handler(srv, &boundedStream{ServerStream: ss, ctx: ctx})
+}
+
+// boundedStream is a stream whose deadline is shorter than the one of the transport, its
+// messages are given up on once the deadline is exceeded.
+type boundedStream struct {
+  grpc.ServerStream
+  ctx context.Context
+}
+
+func (s *boundedStream) Context() context.Context {
+  return s.ctx
+}
+
+// RecvMsg fails once the deadline is exceeded. A receive waiting past the deadline returns
+// with the next message or the end of the stream, the message then being dropped.
+func (s *boundedStream) RecvMsg(m interface{}) error {
+  if err := s.ctx.Err(); err != nil {
+    return status.FromContextError(err).Err()
+  }
+  err := s.ServerStream.RecvMsg(m)
+  if ctxErr := s.ctx.Err(); ctxErr != nil {
+    return status.FromContextError(ctxErr).Err()
+  }
+  return err
+}
+
+func (s *boundedStream) SendMsg(m interface{}) error {
+  if err := s.ctx.Err(); err != nil {
+    return status.FromContextError(err).Err()
+  }
+  return s.ServerStream.SendMsg(m)
+}
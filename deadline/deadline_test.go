@@ -0,0 +1,124 @@
+package deadline_test
+
+import (
+  "context"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/deadline"
+  "github.com/_dev/grpc-go-example/grpctest"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// seen records the time left to the calls once bounded, -1 for none.
+type seen struct {
+  left chan time.Duration
+}
+
+func newSeen() *seen {
+  return &seen{left: make(chan time.Duration, 1)}
+}
+
+func (s *seen) record(ctx context.Context) {
+  left := time.Duration(-1)
+  if d, ok := ctx.Deadline(); ok {
+    left = time.Until(d)
+  }
+  s.left <- left
+}
+
+func (s *seen) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  s.record(ctx)
+  return handler(ctx, req)
+}
+
+func (s *seen) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  s.record(ss.Context())
+  return handler(srv, ss)
+}
+
+func newBoundedCalculator(t *testing.T, l *deadline.Limiter, s *seen) calculatorpb.CalculatorServiceClient {
+  calc := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{
+    UnaryInterceptors:  []grpc.UnaryServerInterceptor{l.UnaryInterceptor, s.unary},
+    StreamInterceptors: []grpc.StreamServerInterceptor{l.StreamInterceptor, s.stream},
+  })
+  return calc.Client
+}
+
+// within tells whether left is want, give or take the time the call took to arrive.
+func within(left, want time.Duration) bool {
+  if want < 0 {
+    return left < 0
+  }
+  return left <= want && left > want-5*time.Second
+}
+
+func TestBounds(t *testing.T) {
+  for _, tc := range []struct {
+    name     string
+    bounds   deadline.Bounds
+    deadline time.Duration // of the client, 0 for none.
+    want     time.Duration // left to the server, -1 for no deadline.
+  }{
+    {name: "default", bounds: deadline.Bounds{Default: time.Minute, Max: 2 * time.Minute}, want: time.Minute},
+    {name: "default over max", bounds: deadline.Bounds{Default: 3 * time.Minute, Max: 2 * time.Minute}, want: 2 * time.Minute},
+    {name: "max without default", bounds: deadline.Bounds{Max: 2 * time.Minute}, want: 2 * time.Minute},
+    {name: "over max", bounds: deadline.Bounds{Default: time.Minute, Max: 2 * time.Minute}, deadline: time.Hour, want: 2 * time.Minute},
+    {name: "under max", bounds: deadline.Bounds{Default: time.Minute, Max: 2 * time.Minute}, deadline: 90 * time.Second, want: 90 * time.Second},
+    {name: "no max", bounds: deadline.Bounds{Default: time.Minute}, deadline: time.Hour, want: time.Hour},
+    {name: "no bounds", want: -1},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      // The unary calls and the streams have their own bounds.
+      s := newSeen()
+      c := newBoundedCalculator(t, deadline.New(tc.bounds, tc.bounds), s)
+      ctx := context.Background()
+      if tc.deadline > 0 {
+        var cancel context.CancelFunc
+        ctx, cancel = context.WithTimeout(ctx, tc.deadline)
+        defer cancel()
+      }
+
+      if _, err := c.Sum(ctx, &calculatorpb.SumRequest{}); err != nil {
+        t.Fatal(err)
+      }
+      if left := <-s.left; !within(left, tc.want) {
+        t.Errorf("Sum() ran with %v left, want %v", left, tc.want)
+      }
+      stream, err := c.PrimeNumberDecomposition(ctx, &calculatorpb.PrimeNumberDecompositionRequest{Number: 2})
+      if err != nil {
+        t.Fatal(err)
+      }
+      for _, err = stream.Recv(); err == nil; _, err = stream.Recv() {
+      }
+      if left := <-s.left; !within(left, tc.want) {
+        t.Errorf("PrimeNumberDecomposition() ran with %v left, want %v", left, tc.want)
+      }
+    })
+  }
+}
+
+func TestStreamDeadlineExceeded(t *testing.T) {
+  s := newSeen()
+  c := newBoundedCalculator(t, deadline.New(deadline.Bounds{}, deadline.Bounds{Default: 50 * time.Millisecond}), s)
+
+  // The client has no deadline, the server gives up on the stream once its own is
+  // exceeded, dropping the message received past it.
+  stream, err := c.ComputeAverage(context.Background())
+  if err != nil {
+    t.Fatal(err)
+  }
+  if err := stream.Send(&calculatorpb.ComputeAverageRequest{Number: 1}); err != nil {
+    t.Fatal(err)
+  }
+  <-s.left
+  time.Sleep(100 * time.Millisecond)
+  stream.Send(&calculatorpb.ComputeAverageRequest{Number: 2})
+  if _, err := stream.CloseAndRecv(); status.Code(err) != codes.DeadlineExceeded {
+    t.Fatalf("ComputeAverage() past the deadline of the server = %v, want DeadlineExceeded", err)
+  }
+}
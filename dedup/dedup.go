@@ -0,0 +1,132 @@
+// Package dedup collapses concurrent identical calls into a single execution whose
+// results, possibly many as for a server stream, are fanned out to every caller.
+package dedup
+
+import (
+  "context"
+  "sync"
+)
+
+// Group deduplicates the calls sharing a key, the zero value is ready to use.
+type Group struct {
+  mu    sync.Mutex
+  calls map[string]*call
+}
+
+type call struct {
+  mu      sync.Mutex
+  changed chan struct{} // closed, then replaced, whenever a result is added or the call ends.
+  results []interface{}
+  done    bool
+  err     error
+
+  waiters int // guarded by Group.mu.
+  cancel  context.CancelFunc
+}
+
+// Do runs fn once for all the concurrent callers of key. Every result fn emits is
+// handed, in order, to deliver for each caller, including the results emitted before
+// that caller joined. fn runs on its own context, canceled only when every caller
+// has given up, so the caller that started it can leave without affecting the others.
+//
+// Do returns the error of fn, the error of deliver, or the error of ctx, whichever
+// ends the call for this caller first, and whether the execution was shared with an
+// earlier caller.
+func (g *Group) Do(ctx context.Context, key string, fn func(ctx context.Context, emit func(interface{})) error, deliver func(interface{}) error) (shared bool, err error) {
+  g.mu.Lock()
+  if g.calls == nil {
+    g.calls = make(map[string]*call)
+  }
+  c, shared := g.calls[key]
+  if !shared {
+    c = g.start(key, fn)
+  }
+  c.waiters++
+  g.mu.Unlock()
+  defer g.leave(key, c)
+
+  next := 0
+  for {
+    c.mu.Lock()
+    results := c.results[next:]
+    done, err, changed := c.done, c.err, c.changed
+    c.mu.Unlock()
+
+    for _, result := range results {
+      if err := deliver(result); err != nil {
+        return shared, err
+      }
+      next++
+    }
+    if done {
+      return shared, err
+    }
+
+    select {
+    case <-changed:
+    case <-ctx.Done():
+      return shared, ctx.Err()
+    }
+  }
+}
+
+// start runs fn in the background, g.mu must be held.
+func (g *Group) start(key string, fn func(ctx context.Context, emit func(interface{})) error) *call {
+  ctx, cancel := context.WithCancel(context.Background())
+  c := &call{
+    changed: make(chan struct{}),
+    cancel:  cancel,
+  }
+  g.calls[key] = c
+
+  emit := func(result interface{}) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    c.results = append(c.results, result)
+    close(c.changed)
+    c.changed = make(chan struct{})
+  }
+
+  go func() {
+    err := fn(ctx, emit)
+
+    // Later callers start over rather than joining a finished call.
+    g.mu.Lock()
+    if g.calls[key] == c {
+      delete(g.calls, key)
+    }
+    g.mu.Unlock()
+
+    c.mu.Lock()
+    c.done = true
+    c.err = err
+    close(c.changed)
+    c.mu.Unlock()
+    cancel()
+  }()
+  return c
+}
+
+// leave cancels the call when its last caller is gone.
+func (g *Group) leave(key string, c *call) {
+  g.mu.Lock()
+  defer g.mu.Unlock()
+  c.waiters--
+  if c.waiters == 0 {
+    c.cancel()
+    if g.calls[key] == c {
+      delete(g.calls, key)
+    }
+  }
+}
+
+// Waiters returns how many callers wait on the call of key, so that a test acts once
+// the callers it starts have joined.
+func (g *Group) Waiters(key string) int {
+  g.mu.Lock()
+  defer g.mu.Unlock()
+  if c, ok := g.calls[key]; ok {
+    return c.waiters
+  }
+  return 0
+}
@@ -0,0 +1,132 @@
+package dedup
+
+import (
+  "context"
+  "errors"
+  "reflect"
+  "testing"
+  "time"
+)
+
+// waitFor waits for n callers to wait on key.
+func waitFor(t *testing.T, g *Group, key string, n int) {
+  t.Helper()
+  for start := time.Now(); g.Waiters(key) != n; time.Sleep(time.Millisecond) {
+    if time.Since(start) > 10*time.Second {
+      t.Fatalf("Waiters(%q) = %v, want %v", key, g.Waiters(key), n)
+    }
+  }
+}
+
+// steps emits the values sent on next, until next is closed or ctx is done.
+type steps struct {
+  runs     int
+  next     chan int
+  canceled chan struct{} // closed once fn saw its context done.
+}
+
+func newSteps() *steps {
+  return &steps{next: make(chan int), canceled: make(chan struct{})}
+}
+
+func (s *steps) fn(ctx context.Context, emit func(interface{})) error {
+  s.runs++
+  for {
+    select {
+    case v, ok := <-s.next:
+      if !ok {
+        return nil
+      }
+      emit(v)
+    case <-ctx.Done():
+      close(s.canceled)
+      return ctx.Err()
+    }
+  }
+}
+
+type result struct {
+  got    []int
+  shared bool
+  err    error
+}
+
+func do(g *Group, ctx context.Context, key string, s *steps) chan result {
+  results := make(chan result, 1)
+  go func() {
+    var r result
+    r.shared, r.err = g.Do(ctx, key, s.fn, func(v interface{}) error {
+      r.got = append(r.got, v.(int))
+      return nil
+    })
+    results <- r
+  }()
+  return results
+}
+
+func TestLeaderCancels(t *testing.T) {
+  var g Group
+  s := newSteps()
+  leaderCtx, cancel := context.WithCancel(context.Background())
+  leader := do(&g, leaderCtx, "key", s)
+  waitFor(t, &g, "key", 1)
+  s.next <- 1
+
+  follower := do(&g, context.Background(), "key", s)
+  waitFor(t, &g, "key", 2)
+  cancel()
+  if r := <-leader; !errors.Is(r.err, context.Canceled) || r.shared {
+    t.Fatalf("Do() of the leader = %v, %v, want Canceled and not shared", r.shared, r.err)
+  }
+
+  // The follower gets the results emitted before it joined, and those after the leader left.
+  s.next <- 2
+  s.next <- 3
+  close(s.next)
+  r := <-follower
+  if r.err != nil || !r.shared || !reflect.DeepEqual(r.got, []int{1, 2, 3}) {
+    t.Fatalf("Do() of the follower = %v, %v, %v, want [1 2 3] shared", r.got, r.shared, r.err)
+  }
+  if s.runs != 1 {
+    t.Fatalf("fn ran %v times, want 1", s.runs)
+  }
+  if g.Waiters("key") != 0 {
+    t.Fatalf("Waiters() = %v after the call, want 0", g.Waiters("key"))
+  }
+}
+
+func TestLastWaiterCancels(t *testing.T) {
+  var g Group
+  s := newSteps()
+  leaderCtx, cancelLeader := context.WithCancel(context.Background())
+  followerCtx, cancelFollower := context.WithCancel(context.Background())
+  leader := do(&g, leaderCtx, "key", s)
+  waitFor(t, &g, "key", 1)
+  follower := do(&g, followerCtx, "key", s)
+  waitFor(t, &g, "key", 2)
+
+  // fn goes on while a caller waits, and is canceled once none does.
+  cancelLeader()
+  <-leader
+  select {
+  case <-s.canceled:
+    t.Fatal("fn was canceled while the follower waited")
+  case <-time.After(10 * time.Millisecond):
+  }
+  cancelFollower()
+  if r := <-follower; !errors.Is(r.err, context.Canceled) {
+    t.Fatalf("Do() of the follower = %v, want Canceled", r.err)
+  }
+  select {
+  case <-s.canceled:
+  case <-time.After(10 * time.Second):
+    t.Fatal("fn was not canceled once the last caller left")
+  }
+
+  // A later caller starts over.
+  s2 := newSteps()
+  close(s2.next)
+  if r := <-do(&g, context.Background(), "key", s2); r.err != nil || r.shared || s2.runs != 1 {
+    t.Fatalf("Do() after the cancellation = %v, %v, want a new run", r.shared, r.err)
+  }
+}
@@ -1,4 +1,10 @@
 #!/bin/bash
+#
+# Generates the messages with protoc-gen-go and the services with protoc-gen-go-grpc:
+#
+#   go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.36.6
+#   go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.5.1
 
-protoc greet/greetpb/greet.proto --go_out=plugins=grpc:.
-protoc calculator/calculatorpb/calculator.proto --go_out=plugins=grpc:.
+protoc greet/greetpb/greet.proto --go_out=paths=source_relative:. --go-grpc_out=paths=source_relative:.
+protoc calculator/calculatorpb/calculator.proto --go_out=paths=source_relative:. --go-grpc_out=paths=source_relative:.
+protoc admin/adminpb/admin.proto --go_out=paths=source_relative:. --go-grpc_out=paths=source_relative:.
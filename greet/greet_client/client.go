@@ -2,22 +2,92 @@ package main
 
 import (
   "context"
+  "flag"
   "fmt"
   "io"
   "log"
+  "os"
+  "strings"
   "time"
 
+  "github.com/_dev/grpc-go-example/client"
+  "github.com/_dev/grpc-go-example/compression"
   "github.com/_dev/grpc-go-example/greet/greetpb"
+  "github.com/_dev/grpc-go-example/idempotency"
 
   "google.golang.org/grpc"
   "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/metadata"
   "google.golang.org/grpc/status"
 )
 
 func main() {
+  backends := flag.String("backends", "localhost:50051", "servers as addresses separated by commas, or a target such as dns:///host:port or file:///path/endpoints.json")
+  balancer := flag.String("balancer", "round-robin", "how the calls are spread over the servers: round-robin or least-request")
+  healthCheck := flag.Bool("health-check", true, "leave out the servers whose health service does not report serving")
+  retryInterceptor := flag.Bool("retry-interceptor", false, "retry through an interceptor instead of the gRPC service config")
+  hedgeDelay := flag.Duration("hedge-delay", 0, "hedge the Greet calls not answered within this delay, 0 disables hedging")
+  maxHedges := flag.Int("max-hedges", 2, "most hedges sent per call")
+  interactive := flag.Bool("interactive", false, "greet the names typed on stdin through GreetEveryone instead of running the examples")
+  historyFile := flag.String("history-file", client.HistoryFile(".greet_history"), "file keeping the names typed in interactive mode, none when empty")
+  compressionRules := flag.String("compression", "", "compressors of the calls as method=compressor separated by commas, * for the other methods: gzip, zstd or identity; none by default")
+  compressionMinSize := flag.Int("compression-min-size", 1024, "size in bytes under which the unary requests are sent uncompressed")
+  flag.Parse()
+
   log.Println("Client running...")
 
-  cc, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
+  // Retrying the transient failures, through the service config unless told otherwise...
+  var opts []grpc.DialOption
+  retries := &client.RetryConfig{Default: client.DefaultRetryPolicy}
+  if *retryInterceptor {
+    opts = client.WithRetryInterceptor(*retries)
+    retries = nil
+  }
+
+  // Balancing the calls over the healthy backends...
+  policy, ok := client.Policies[*balancer]
+  if !ok {
+    log.Fatalf("Unknown balancing policy: %v", *balancer)
+  }
+  serviceConfig, err := client.WithServiceConfig(retries, &client.BalanceConfig{Policy: policy, HealthCheck: *healthCheck})
+  if err != nil {
+    log.Fatalf("Invalid service config: %v", err)
+  }
+  target, targetOpts, err := client.Target(*backends)
+  if err != nil {
+    log.Fatalf("Invalid backends: %v", err)
+  }
+  opts = append(opts, serviceConfig, grpc.WithInsecure())
+  opts = append(opts, targetOpts...)
+
+  // Hedging the latency-sensitive calls, each hedge being retried on its own...
+  var hedger *client.Hedger
+  if *hedgeDelay > 0 {
+    hedger = client.NewHedger(client.HedgeConfig{
+      Methods:       []string{"/greet.GreetService/Greet"},
+      Delay:         *hedgeDelay,
+      MaxHedges:     *maxHedges,
+      NonFatalCodes: []codes.Code{codes.Unavailable},
+    })
+    opts = append(client.WithHedging(hedger), opts...)
+  }
+
+  // Failing fast while the server keeps failing, the breaker comes first so that it sees
+  // the outcome of the retried calls...
+  breakerConfig := client.DefaultBreakerConfig
+  breakerConfig.OnStateChange = func(method string, from, to client.BreakerState) {
+    log.Printf("Circuit of %v went from %v to %v.", method, from, to)
+  }
+  opts = append(client.WithBreaker(client.NewBreaker(breakerConfig)), opts...)
+
+  // Compressing the requests over the size threshold, the server answers in kind...
+  compressors, err := compression.ParseMethods(*compressionRules)
+  if err != nil {
+    log.Fatalf("Invalid compression: %v", err)
+  }
+  opts = append(opts, compression.WithCompression(compression.Policy{Methods: compressors, MinSize: *compressionMinSize})...)
+
+  cc, err := grpc.Dial(target, opts...)
   if err != nil {
     log.Fatalf("Could not connect: %v", err)
   }
@@ -29,10 +99,19 @@ func main() {
   c := greetpb.NewGreetServiceClient(cc)
   //fmt.Println("Created client: %f", c)
 
+  if *interactive {
+    doInteractiveStreaming(c, *historyFile)
+    return
+  }
+
   log.Println(">>")
   doUnary(c)
   log.Println("<<")
 
+  log.Println(">>")
+  doUnaryIdempotent(c)
+  log.Println("<<")
+
   log.Println(">>")
   doServerStreaming(c)
   log.Println("<<")
@@ -50,6 +129,13 @@ func main() {
   doUnaryWithDeadline(c, 1*time.Second) // should timeout
   log.Println("<<")
 
+  if hedger != nil {
+    log.Printf("Hedging stats: %+v", hedger.Stats())
+  }
+  if len(compressors) > 0 {
+    log.Printf("Compression stats: %+v", compression.AllStats())
+  }
+
   log.Println("Client stoped.")
 }
 
@@ -73,6 +159,38 @@ func doUnary(c greetpb.GreetServiceClient) {
   log.Println("UNARY - Completed.")
 }
 
+// Unary API retried with an idempotency key
+func doUnaryIdempotent(c greetpb.GreetServiceClient) {
+  log.Println("UNARY IDEMPOTENT - Starting...")
+
+  req := &greetpb.GreetRequest{
+    Greeting: &greetpb.Greeting{
+      FirstName: "Felipe",
+      LastName:  "Sulzbach",
+    },
+  }
+  ctx := metadata.AppendToOutgoingContext(context.Background(), idempotency.Header, "greet-felipe-0001")
+
+  // The second call is answered with the stored response of the first one.
+  for i := 0; i < 2; i++ {
+    var header metadata.MD
+    resp, err := c.Greet(ctx, req, grpc.Header(&header))
+    if err != nil {
+      log.Fatalf("Error while calling Greet RPC: %v", err)
+    }
+    log.Printf("Greet Response: %v (replayed: %v)", resp.Result, len(header.Get(idempotency.ReplayedHeader)) > 0)
+  }
+
+  // Reusing the key for another request is refused.
+  req.Greeting.FirstName = "Stephane"
+  _, err := c.Greet(ctx, req)
+  if statusErr, ok := status.FromError(err); ok && statusErr.Code() == codes.FailedPrecondition {
+    log.Printf("Error message from server: %v - %v.", statusErr.Code(), statusErr.Message())
+  }
+
+  log.Println("UNARY IDEMPOTENT - Completed.")
+}
+
 // Server Streaming API
 func doServerStreaming(c greetpb.GreetServiceClient) {
   log.Println("SERVER STREAMING - Starting...")
@@ -235,6 +353,42 @@ func doBidirectionalStreaming(c greetpb.GreetServiceClient) {
   log.Println("BIDIRECTIONAL STREAMING - Completed.")
 }
 
+// Bidirectional Streaming API, typed by hand
+func doInteractiveStreaming(c greetpb.GreetServiceClient, historyFile string) {
+  log.Println("INTERACTIVE STREAMING - Starting, type a name per line and Ctrl-D to end...")
+
+  stream, err := c.GreetEveryone(context.Background())
+  if err != nil {
+    log.Fatalf("Error while creating stream: %v", err)
+  }
+
+  repl := &client.REPL{
+    Prompt:      "greet> ",
+    Out:         os.Stdout,
+    HistoryFile: historyFile,
+    Send: func(line string) error {
+      fields := strings.SplitN(line, " ", 2)
+      greeting := &greetpb.Greeting{FirstName: fields[0]}
+      if len(fields) == 2 {
+        greeting.LastName = strings.TrimSpace(fields[1])
+      }
+      return stream.Send(&greetpb.GreetEveryoneRequest{Greeting: greeting})
+    },
+    Recv: func() (string, error) {
+      res, err := stream.Recv()
+      return res.GetResult(), err
+    },
+    CloseSend: stream.CloseSend,
+  }
+  if err := repl.Run(os.Stdin); err != nil {
+    s := status.Convert(err)
+    log.Printf("The server ended the stream with %v: %v", s.Code(), s.Message())
+    return
+  }
+
+  log.Println("INTERACTIVE STREAMING - Completed.")
+}
+
 func doUnaryWithDeadline(c greetpb.GreetServiceClient, timeout time.Duration) {
   fmt.Println("Starting to do a UnaryWithDeadline RPC...")
   req := &greetpb.GreetWithDeadlineRequest{
@@ -1,150 +1,160 @@
 package main
 
 import (
-  "bytes"
-  "context"
-  "fmt"
-  "io"
+  "expvar"
   "log"
   "net"
-  "strconv"
-  "time"
-
+  "net/http"
+  "os"
+
+  "github.com/_dev/grpc-go-example/admin"
+  "github.com/_dev/grpc-go-example/admin/adminpb"
+  "github.com/_dev/grpc-go-example/admission"
+  "github.com/_dev/grpc-go-example/clock"
+  "github.com/_dev/grpc-go-example/compression"
+  "github.com/_dev/grpc-go-example/config"
+  "github.com/_dev/grpc-go-example/deadline"
   "github.com/_dev/grpc-go-example/greet/greetpb"
+  "github.com/_dev/grpc-go-example/greet/greetservice"
+  "github.com/_dev/grpc-go-example/idempotency"
+  "github.com/_dev/grpc-go-example/logging"
+  "github.com/_dev/grpc-go-example/ratelimit"
 
   "google.golang.org/grpc"
-  "google.golang.org/grpc/codes"
-  "google.golang.org/grpc/status"
+  "google.golang.org/grpc/health"
+  healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
-type server struct{}
-
-// Unary API
-func (*server) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
-  log.Printf("Greet - function was invoked with %v.", req)
-
-  firstName := req.GetGreeting().GetFirstName()
-  var result bytes.Buffer
-  result.WriteString("Hello ")
-  result.WriteString(firstName)
-  result.WriteString("!")
+// maxIdempotencyKeys bounds the responses kept for replay.
+const maxIdempotencyKeys = 10000
 
-  response := &greetpb.GreetResponse{
-    Result: result.String(),
+func main() {
+  // Loading the configuration, flags over environment over file over defaults...
+  cfg := greetservice.DefaultConfig()
+  src, err := config.Load(&cfg, "GREET", os.Args[1:])
+  if err != nil {
+    log.Fatalf("Invalid configuration: %v", err)
   }
 
-  log.Println("Greet - returned.")
-  return response, nil
-}
+  log.Println("SERVER - Starting...")
 
-// Server Streaming API
-func (*server) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreetService_GreetManyTimesServer) error {
-  log.Printf("GreetManyTimes - function was invoked with %v.", req)
-
-  firstName := req.GetGreeting().GetFirstName()
-  for i := 0; i < 10; i++ {
-    var result bytes.Buffer
-    result.WriteString("Hello ")
-    result.WriteString(firstName)
-    result.WriteString(" number ")
-    result.WriteString(strconv.Itoa(i))
-    result.WriteString("!")
-
-    response := &greetpb.GreetManyTimesResponse{
-      Result: result.String(),
-    }
-    stream.Send(response)
-    time.Sleep(1000 * time.Millisecond) // wating 1 second.
+  // Creating the port of GRPC server...
+  list, err := net.Listen("tcp", cfg.Addr)
+  if err != nil {
+    log.Fatalf("Failed to listen: %v", err)
   }
 
-  log.Println("GreetManyTimes - returned.")
-  return nil
-}
-
-func (*server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
-  log.Println("LongGreet - function was invoked with streaming.")
-
-  var result bytes.Buffer
-  for { // Runs in a loop to consume the entire stream.
-    request, err := stream.Recv()
-    if err == io.EOF {
-      log.Println("LongGreet - returned.")
-      return stream.SendAndClose(&greetpb.LongGreetResponse{
-        Result: result.String(),
-      })
-    }
-    if err != nil {
-      log.Fatalf("Error while reading stream: %v", err)
-    }
-
-    firstName := request.GetGreeting().GetFirstName()
-    result.WriteString("Hello ")
-    result.WriteString(firstName)
-    result.WriteString("! ")
+  // Serving the metrics...
+  if cfg.MetricsAddr != "" {
+    go func() {
+      if err := http.ListenAndServe(cfg.MetricsAddr, nil); err != nil {
+        log.Printf("Failed to serve metrics: %v", err)
+      }
+    }()
   }
-}
 
-func (*server) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer) error {
-  log.Println("GreetEveryone - function was invoked with streaming.")
-
-  var result bytes.Buffer
-  for { // Runs in a loop to consume the entire stream.
-    req, err := stream.Recv()
-    if err == io.EOF {
-      return nil
-    }
-    if err != nil {
-      log.Fatalf("Error while reading stream: %v", err)
-      return err
-    }
-
-    firstName := req.GetGreeting().GetFirstName()
-    result.WriteString("Hello ")
-    result.WriteString(firstName)
-    result.WriteString("! ")
-
-    err = stream.Send(&greetpb.GreetEveryoneResponse{
-      Result: result.String(),
-    })
-    if err != nil {
-      log.Fatalf("Error while sending stream: %v", err)
-      return err
-    }
+  // Answering the calls of the disabled methods...
+  srv, err := greetservice.NewServer(cfg, clock.Real)
+  if err != nil {
+    log.Fatalf("Invalid configuration: %v", err)
   }
-}
-
-func (*server) GreetWithDeadline(ctx context.Context, req *greetpb.GreetWithDeadlineRequest) (*greetpb.GreetWithDeadlineResponse, error) {
-  fmt.Printf("GreetWithDeadline function was invoked with %v\n", req)
-  for i := 0; i < 3; i++ {
-    if ctx.Err() == context.Canceled {
-      // the client canceled the request
-      fmt.Println("The client canceled the request!")
-      return nil, status.Error(codes.Canceled, "the client canceled the request")
+  unaryInterceptors := srv.UnaryInterceptors()
+  streamInterceptors := srv.StreamInterceptors()
+
+  // Limiting the rate of calls of each client, the rules may change on reload...
+  keyFunc := ratelimit.KeyFuncs[cfg.RateLimitKey]
+  limiter := ratelimit.NewLimiter(keyFunc, nil, clock.Real)
+  defer limiter.Close()
+  unaryInterceptors = append(unaryInterceptors, limiter.UnaryInterceptor)
+  streamInterceptors = append(streamInterceptors, limiter.StreamInterceptor)
+
+  // Bounding the deadlines of the calls, giving one to those arriving without...
+  deadlines := deadline.New(
+    deadline.Bounds{Default: cfg.Deadlines.Default, Max: cfg.Deadlines.Max},
+    deadline.Bounds{Default: cfg.Deadlines.StreamDefault, Max: cfg.Deadlines.StreamMax},
+  )
+  unaryInterceptors = append(unaryInterceptors, deadlines.UnaryInterceptor)
+  streamInterceptors = append(streamInterceptors, deadlines.StreamInterceptor)
+
+  // Compressing the responses over the size threshold...
+  compressors, _ := compression.ParseMethods(cfg.Compression.Methods)
+  compressionPolicy := compression.Policy{Methods: compressors, MinSize: cfg.Compression.MinSize}
+  unaryInterceptors = append(unaryInterceptors, compressionPolicy.UnaryServerInterceptor)
+  streamInterceptors = append(streamInterceptors, compressionPolicy.StreamServerInterceptor)
+  expvar.Publish("greet_compression", expvar.Func(func() interface{} {
+    return compression.AllStats()
+  }))
+
+  // Applying the live settings, now and on each reload...
+  apply := func(c config.Validator) error {
+    cfg := c.(*greetservice.Config)
+    if err := srv.Apply(*cfg); err != nil {
+      return err
     }
-    time.Sleep(1 * time.Second)
+    level, _ := logging.ParseLevel(cfg.LogLevel)
+    rules, _ := ratelimit.ParseRules(cfg.RateLimit)
+    logging.SetLevel(level)
+    limiter.SetRules(rules)
+    return nil
   }
-  firstName := req.GetGreeting().GetFirstName()
-  result := "Hello " + firstName
-  res := &greetpb.GreetWithDeadlineResponse{
-    Result: result,
+  if err := apply(&cfg); err != nil {
+    log.Fatalf("Invalid configuration: %v", err)
   }
-  return res, nil
-}
-
-func main() {
-  log.Println("SERVER - Starting...")
-
-  // Creating the port of GRPC server...
-  list, err := net.Listen("tcp", "0.0.0.0:50051")
-  if err != nil {
-    log.Fatalf("Failed to listen: %v", err)
+  reloader := config.NewReloader(src, &cfg, apply)
+  go reloader.Watch()
+
+  // Admitting the streams while there is capacity for them...
+  limits, _ := admission.ParseLimits(cfg.StreamLimits)
+  admissionController := admission.New(admission.Config{
+    MaxStreamsPerMethod: limits,
+    MaxStreamsPerClient: cfg.MaxClientStreams,
+    ClientKey:           keyFunc,
+    TargetLatency:       cfg.Shed.TargetLatency,
+    MaxStreams:          cfg.Shed.MaxStreams,
+    Clock:               clock.Real,
+  })
+  expvar.Publish("greet_admission", expvar.Func(func() interface{} {
+    return admissionController.Stats()
+  }))
+
+  // Replaying the calls retried by a client with the same idempotency key...
+  if cfg.IdempotencyWindow > 0 {
+    idempotent := idempotency.NewStore(cfg.IdempotencyWindow, maxIdempotencyKeys, ratelimit.DefaultKey,
+      "/greet.GreetService/Greet",
+      "/greet.GreetService/GreetWithDeadline",
+    )
+    unaryInterceptors = append(unaryInterceptors, idempotent.UnaryInterceptor)
   }
 
   // Creating GRPC server...
-  s := grpc.NewServer()
+  s := grpc.NewServer(
+    grpc.ChainUnaryInterceptor(unaryInterceptors...),
+    grpc.ChainStreamInterceptor(streamInterceptors...),
+    admissionController.ServerOption(),
+  )
 
   // Registring de GreetService in GRPC server...
-  greetpb.RegisterGreetServiceServer(s, &server{})
+  greetpb.RegisterGreetServiceServer(s, srv)
+
+  // Registring the health service, watched by the clients balancing over several servers...
+  healthServer := health.NewServer()
+  healthServer.SetServingStatus("greet.GreetService", healthpb.HealthCheckResponse_SERVING)
+  healthpb.RegisterHealthServer(s, healthServer)
+
+  // Serving the AdminService, reloading the configuration, apart from the clients...
+  if cfg.AdminAddr != "" {
+    adminList, err := net.Listen("tcp", cfg.AdminAddr)
+    if err != nil {
+      log.Fatalf("Failed to listen: %v", err)
+    }
+    adminServer := grpc.NewServer()
+    adminpb.RegisterAdminServiceServer(adminServer, admin.NewServer(reloader))
+    go func() {
+      if err := adminServer.Serve(adminList); err != nil {
+        log.Printf("Failed to serve the AdminService: %v", err)
+      }
+    }()
+  }
 
   log.Println("SERVER - Running...")
 
@@ -0,0 +1,212 @@
+package greetservice
+
+import (
+  "errors"
+  "fmt"
+  "io"
+  "strings"
+  "text/template"
+  "time"
+
+  "github.com/_dev/grpc-go-example/admission"
+  "github.com/_dev/grpc-go-example/compression"
+  "github.com/_dev/grpc-go-example/greet/greetpb"
+  "github.com/_dev/grpc-go-example/logging"
+  "github.com/_dev/grpc-go-example/ratelimit"
+
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// Config is the configuration of the greet server, loaded by package config from
+// greet.yaml or greet.toml, the GREET_* environment variables and the flags.
+type Config struct {
+  LogLevel          string            `config:"log_level" reload:"live" usage:"least severity logged: debug, info or error"`
+  Addr              string            `config:"addr" usage:"address the server listens on"`
+  MetricsAddr       string            `config:"metrics_addr" usage:"address serving the metrics at /debug/vars, empty to disable"`
+  AdminAddr         string            `config:"admin_addr" usage:"address of the AdminService, reloading the configuration without authentication: keep it on the loopback or a private network, empty to disable"`
+  RateLimit         string            `config:"rate_limit" reload:"live" usage:"rate limits as method=rate:burst separated by commas, * for the other methods"`
+  RateLimitKey      string            `config:"rate_limit_key" usage:"how clients are told apart by the rate limits: default, ip, api-key or principal"`
+  StreamLimits      string            `config:"stream_limits" usage:"concurrent stream limits as method=streams separated by commas, * for the other methods"`
+  MaxClientStreams  int               `config:"max_client_streams" usage:"concurrent streams allowed to each client, 0 for no limit"`
+  Shed              ShedConfig        `config:"shed"`
+  IdempotencyWindow time.Duration     `config:"idempotency_window" usage:"how long the response of a call with an idempotency key is replayed, 0 to disable"`
+  Deadlines         DeadlinesConfig   `config:"deadlines"`
+  Compression       CompressionConfig `config:"compression"`
+  DisabledMethods   string            `config:"disabled_methods" reload:"live" usage:"full method names answered with Unimplemented, separated by commas"`
+  Templates         Templates         `config:"templates" reload:"live"`
+  GreetManyTimes    ManyTimesConfig   `config:"greet_many_times" reload:"live"`
+  GreetWithDeadline DeadlineConfig    `config:"greet_with_deadline" reload:"live"`
+}
+
+// Templates are the text/template of the greetings of each RPC, given the FirstName and
+// LastName of the greeting, and its Number for GreetManyTimes.
+type Templates struct {
+  Greet             string `config:"greet" usage:"template of the Greet greeting"`
+  GreetManyTimes    string `config:"greet_many_times" usage:"template of each GreetManyTimes greeting"`
+  LongGreet         string `config:"long_greet" usage:"template of each greeting aggregated by LongGreet"`
+  GreetEveryone     string `config:"greet_everyone" usage:"template of each greeting aggregated by GreetEveryone"`
+  GreetWithDeadline string `config:"greet_with_deadline" usage:"template of the GreetWithDeadline greeting"`
+}
+
+type ShedConfig struct {
+  TargetLatency time.Duration `config:"target_latency" usage:"answer latency over which new streams are shed, 0 to disable the load shedding"`
+  MaxStreams    int           `config:"max_streams" usage:"concurrent streams admitted by the load shedding when the latency is fine"`
+}
+
+// DeadlinesConfig bounds the deadlines of the calls received, see package deadline.
+type DeadlinesConfig struct {
+  Default       time.Duration `config:"default" usage:"deadline of the unary calls arriving without one, at most the max"`
+  Max           time.Duration `config:"max" usage:"longest deadline of the unary calls, 0 for no limit"`
+  StreamDefault time.Duration `config:"stream_default" usage:"deadline of the streams arriving without one, at most the stream max"`
+  StreamMax     time.Duration `config:"stream_max" usage:"longest deadline of the streams, 0 for no limit"`
+}
+
+// CompressionConfig picks the compressor of the responses, see package compression.
+type CompressionConfig struct {
+  Methods string `config:"methods" usage:"compressors of the responses as method=compressor separated by commas, * for the other methods: gzip, zstd or identity; the one of the call when none applies"`
+  MinSize int    `config:"min_size" usage:"size in bytes under which the unary responses are sent uncompressed"`
+}
+
+type ManyTimesConfig struct {
+  Count    int           `config:"count" usage:"how many greetings GreetManyTimes sends"`
+  Interval time.Duration `config:"interval" usage:"pause between the greetings of GreetManyTimes"`
+}
+
+type DeadlineConfig struct {
+  Steps    int           `config:"steps" usage:"how many steps of work GreetWithDeadline does before answering"`
+  StepTime time.Duration `config:"step_time" usage:"duration of each step of GreetWithDeadline"`
+}
+
+func DefaultConfig() Config {
+  return Config{
+    LogLevel:          "info",
+    Addr:              "0.0.0.0:50051",
+    AdminAddr:         "127.0.0.1:50062",
+    RateLimit:         "/greet.GreetService/GreetManyTimes=1:5",
+    RateLimitKey:      "default",
+    Shed:              ShedConfig{MaxStreams: 1000},
+    IdempotencyWindow: 10 * time.Minute,
+    Deadlines:         DeadlinesConfig{Default: 30 * time.Second, Max: 2 * time.Minute},
+    Compression:       CompressionConfig{MinSize: 1024},
+    Templates: Templates{
+      Greet:             "Hello {{.FirstName}}!",
+      GreetManyTimes:    "Hello {{.FirstName}} number {{.Number}}!",
+      LongGreet:         "Hello {{.FirstName}}! ",
+      GreetEveryone:     "Hello {{.FirstName}}! ",
+      GreetWithDeadline: "Hello {{.FirstName}}",
+    },
+    GreetManyTimes:    ManyTimesConfig{Count: 10, Interval: time.Second},
+    GreetWithDeadline: DeadlineConfig{Steps: 3, StepTime: time.Second},
+  }
+}
+
+func (c *Config) Validate() error {
+  if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+    return err
+  }
+  if c.Addr == "" {
+    return errors.New("addr is required")
+  }
+  if c.AdminAddr == c.Addr {
+    return errors.New("admin_addr must differ from addr, the AdminService is not served to the clients")
+  }
+  if _, err := newSettings(*c); err != nil {
+    return err
+  }
+  if _, err := ratelimit.ParseRules(c.RateLimit); err != nil {
+    return fmt.Errorf("invalid rate_limit: %v", err)
+  }
+  if _, ok := ratelimit.KeyFuncs[c.RateLimitKey]; !ok {
+    return fmt.Errorf("unknown rate_limit_key %q", c.RateLimitKey)
+  }
+  if _, err := admission.ParseLimits(c.StreamLimits); err != nil {
+    return fmt.Errorf("invalid stream_limits: %v", err)
+  }
+  if c.MaxClientStreams < 0 || c.Shed.MaxStreams < 0 {
+    return errors.New("stream limits cannot be negative")
+  }
+  if c.Shed.TargetLatency < 0 || c.IdempotencyWindow < 0 {
+    return errors.New("durations cannot be negative")
+  }
+  if _, err := compression.ParseMethods(c.Compression.Methods); err != nil {
+    return fmt.Errorf("invalid compression.methods: %v", err)
+  }
+  if c.Compression.MinSize < 0 {
+    return errors.New("compression.min_size cannot be negative")
+  }
+  if d := c.Deadlines; d.Default < 0 || d.Max < 0 || d.StreamDefault < 0 || d.StreamMax < 0 {
+    return errors.New("deadlines cannot be negative")
+  }
+  if c.GreetManyTimes.Count < 1 || c.GreetManyTimes.Interval < 0 {
+    return errors.New("greet_many_times needs a positive count and an interval not negative")
+  }
+  if c.GreetWithDeadline.Steps < 1 || c.GreetWithDeadline.StepTime < 0 {
+    return errors.New("greet_with_deadline needs positive steps and a step time not negative")
+  }
+  return nil
+}
+
+// settings is the running configuration of the server, with its templates parsed. It is
+// replaced whole when the configuration is reloaded.
+type settings struct {
+  cfg       Config
+  templates *template.Template
+  disabled  map[string]bool
+}
+
+// greetingData is given to the templates of the greetings.
+type greetingData struct {
+  FirstName string
+  LastName  string
+  Number    int
+}
+
+func newSettings(cfg Config) (*settings, error) {
+  st := &settings{
+    cfg:       cfg,
+    templates: template.New("templates").Option("missingkey=error"),
+    disabled:  make(map[string]bool),
+  }
+  for name, text := range map[string]string{
+    "greet":               cfg.Templates.Greet,
+    "greet_many_times":    cfg.Templates.GreetManyTimes,
+    "long_greet":          cfg.Templates.LongGreet,
+    "greet_everyone":      cfg.Templates.GreetEveryone,
+    "greet_with_deadline": cfg.Templates.GreetWithDeadline,
+  } {
+    t, err := st.templates.New(name).Parse(text)
+    if err != nil {
+      return nil, fmt.Errorf("invalid templates.%v: %v", name, err)
+    }
+    // Trying the template, a reference to an unknown field only fails when executed.
+    if err := t.Execute(io.Discard, greetingData{}); err != nil {
+      return nil, fmt.Errorf("invalid templates.%v: %v", name, err)
+    }
+  }
+
+  for _, method := range strings.Split(cfg.DisabledMethods, ",") {
+    if method = strings.TrimSpace(method); method == "" {
+      continue
+    }
+    if !strings.HasPrefix(method, "/") || strings.Count(method, "/") != 2 {
+      return nil, fmt.Errorf("invalid disabled method %q, expecting /package.Service/Method", method)
+    }
+    st.disabled[method] = true
+  }
+  return st, nil
+}
+
+// greeting renders the template name for greeting.
+func (st *settings) greeting(name string, greeting *greetpb.Greeting, number int) (string, error) {
+  var b strings.Builder
+  err := st.templates.ExecuteTemplate(&b, name, greetingData{
+    FirstName: greeting.GetFirstName(),
+    LastName:  greeting.GetLastName(),
+    Number:    number,
+  })
+  if err != nil {
+    return "", status.Errorf(codes.Internal, "Failed to render the greeting: %v", err)
+  }
+  return b.String(), nil
+}
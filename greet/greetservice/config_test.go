@@ -0,0 +1,48 @@
+package greetservice
+
+import (
+  "strings"
+  "testing"
+)
+
+func TestValidate(t *testing.T) {
+  for _, tc := range []struct {
+    name   string
+    change func(c *Config)
+    err    string // empty for a valid configuration.
+  }{
+    {name: "defaults", change: func(c *Config) {}},
+    {name: "no admin", change: func(c *Config) { c.AdminAddr = "" }},
+    {name: "log level", change: func(c *Config) { c.LogLevel = "loud" }, err: "loud"},
+    {name: "no addr", change: func(c *Config) { c.Addr = "" }, err: "addr is required"},
+    {name: "admin on addr", change: func(c *Config) { c.AdminAddr = c.Addr }, err: "admin_addr must differ from addr"},
+    {name: "template syntax", change: func(c *Config) { c.Templates.Greet = "Hello {{.FirstName" }, err: "invalid templates.greet"},
+    {name: "template field", change: func(c *Config) { c.Templates.LongGreet = "Hello {{.Name}}" }, err: "invalid templates.long_greet"},
+    {name: "disabled method", change: func(c *Config) { c.DisabledMethods = "Greet" }, err: "invalid disabled method"},
+    {name: "rate limit", change: func(c *Config) { c.RateLimit = "/greet.GreetService/Greet" }, err: "invalid rate_limit"},
+    {name: "rate limit key", change: func(c *Config) { c.RateLimitKey = "name" }, err: "unknown rate_limit_key"},
+    {name: "stream limits", change: func(c *Config) { c.StreamLimits = "*=none" }, err: "invalid stream_limits"},
+    {name: "max streams", change: func(c *Config) { c.Shed.MaxStreams = -1 }, err: "stream limits cannot be negative"},
+    {name: "idempotency window", change: func(c *Config) { c.IdempotencyWindow = -1 }, err: "durations cannot be negative"},
+    {name: "compression methods", change: func(c *Config) { c.Compression.Methods = "*=lz4" }, err: "invalid compression.methods"},
+    {name: "compression min size", change: func(c *Config) { c.Compression.MinSize = -1 }, err: "compression.min_size"},
+    {name: "deadlines", change: func(c *Config) { c.Deadlines.Default = -1 }, err: "deadlines cannot be negative"},
+    {name: "greet many times", change: func(c *Config) { c.GreetManyTimes.Count = 0 }, err: "greet_many_times"},
+    {name: "greet with deadline", change: func(c *Config) { c.GreetWithDeadline.StepTime = -1 }, err: "greet_with_deadline"},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      cfg := DefaultConfig()
+      tc.change(&cfg)
+      err := cfg.Validate()
+      if tc.err == "" {
+        if err != nil {
+          t.Fatalf("Validate() = %v", err)
+        }
+        return
+      }
+      if err == nil || !strings.Contains(err.Error(), tc.err) {
+        t.Fatalf("Validate() = %v, want an error with %q", err, tc.err)
+      }
+    })
+  }
+}
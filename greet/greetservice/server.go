@@ -0,0 +1,213 @@
+// Package greetservice implements the GreetService, run by the greet server and started
+// in-process by package grpctest.
+package greetservice
+
+import (
+  "bytes"
+  "context"
+  "io"
+  "sync/atomic"
+  "time"
+
+  "github.com/_dev/grpc-go-example/clock"
+  "github.com/_dev/grpc-go-example/greet/greetpb"
+  "github.com/_dev/grpc-go-example/logging"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+// Server answers the calls of the GreetService.
+type Server struct {
+  greetpb.UnimplementedGreetServiceServer
+  live  atomic.Value // *settings, replaced whole when the configuration is reloaded.
+  clock clock.Clock
+}
+
+// NewServer returns a server running with the live settings of cfg, pacing its greetings
+// by clk, the real clock when nil.
+func NewServer(cfg Config, clk clock.Clock) (*Server, error) {
+  s := &Server{clock: clock.Or(clk)}
+  if err := s.Apply(cfg); err != nil {
+    return nil, err
+  }
+  return s, nil
+}
+
+// Apply replaces the live settings of the server, as when the configuration is reloaded.
+func (s *Server) Apply(cfg Config) error {
+  st, err := newSettings(cfg)
+  if err != nil {
+    return err
+  }
+  s.live.Store(st)
+  return nil
+}
+
+// UnaryInterceptors are to be installed on the gRPC server running s, answering the calls
+// of the disabled methods.
+func (s *Server) UnaryInterceptors() []grpc.UnaryServerInterceptor {
+  return []grpc.UnaryServerInterceptor{s.unaryToggle}
+}
+
+// StreamInterceptors are the stream counterparts of UnaryInterceptors.
+func (s *Server) StreamInterceptors() []grpc.StreamServerInterceptor {
+  return []grpc.StreamServerInterceptor{s.streamToggle}
+}
+
+func (s *Server) settings() *settings {
+  return s.live.Load().(*settings)
+}
+
+// Unary API
+func (s *Server) Greet(ctx context.Context, req *greetpb.GreetRequest) (*greetpb.GreetResponse, error) {
+  logging.Infof("Greet - function was invoked with %v.", req)
+
+  result, err := s.settings().greeting("greet", req.GetGreeting(), 0)
+  if err != nil {
+    return nil, err
+  }
+
+  response := &greetpb.GreetResponse{
+    Result: result,
+  }
+
+  logging.Infof("Greet - returned.")
+  return response, nil
+}
+
+// Server Streaming API
+func (s *Server) GreetManyTimes(req *greetpb.GreetManyTimesRequest, stream greetpb.GreetService_GreetManyTimesServer) error {
+  logging.Infof("GreetManyTimes - function was invoked with %v.", req)
+
+  st := s.settings()
+  for i := 0; i < st.cfg.GreetManyTimes.Count; i++ {
+    result, err := st.greeting("greet_many_times", req.GetGreeting(), i)
+    if err != nil {
+      return err
+    }
+
+    response := &greetpb.GreetManyTimesResponse{
+      Result: result,
+    }
+    if err := stream.Send(response); err != nil {
+      logging.Errorf("Error while sending stream: %v", err)
+      return err
+    }
+    // wating between the greetings, unless the client gives up.
+    if err := s.pause(stream.Context(), st.cfg.GreetManyTimes.Interval); err != nil {
+      logging.Infof("GreetManyTimes - %v.", err)
+      return err
+    }
+  }
+
+  logging.Infof("GreetManyTimes - returned.")
+  return nil
+}
+
+func (s *Server) LongGreet(stream greetpb.GreetService_LongGreetServer) error {
+  logging.Infof("LongGreet - function was invoked with streaming.")
+
+  st := s.settings()
+  var result bytes.Buffer
+  for { // Runs in a loop to consume the entire stream.
+    request, err := stream.Recv()
+    if err == io.EOF {
+      logging.Infof("LongGreet - returned.")
+      return stream.SendAndClose(&greetpb.LongGreetResponse{
+        Result: result.String(),
+      })
+    }
+    if err != nil {
+      logging.Errorf("Error while reading stream: %v", err)
+      return err
+    }
+
+    greeting, err := st.greeting("long_greet", request.GetGreeting(), 0)
+    if err != nil {
+      return err
+    }
+    result.WriteString(greeting)
+  }
+}
+
+func (s *Server) GreetEveryone(stream greetpb.GreetService_GreetEveryoneServer) error {
+  logging.Infof("GreetEveryone - function was invoked with streaming.")
+
+  st := s.settings()
+  var result bytes.Buffer
+  for { // Runs in a loop to consume the entire stream.
+    req, err := stream.Recv()
+    if err == io.EOF {
+      return nil
+    }
+    if err != nil {
+      logging.Errorf("Error while reading stream: %v", err)
+      return err
+    }
+
+    greeting, err := st.greeting("greet_everyone", req.GetGreeting(), 0)
+    if err != nil {
+      return err
+    }
+    result.WriteString(greeting)
+
+    err = stream.Send(&greetpb.GreetEveryoneResponse{
+      Result: result.String(),
+    })
+    if err != nil {
+      logging.Errorf("Error while sending stream: %v", err)
+      return err
+    }
+  }
+}
+
+func (s *Server) GreetWithDeadline(ctx context.Context, req *greetpb.GreetWithDeadlineRequest) (*greetpb.GreetWithDeadlineResponse, error) {
+  logging.Infof("GreetWithDeadline function was invoked with %v", req)
+  st := s.settings()
+  for i := 0; i < st.cfg.GreetWithDeadline.Steps; i++ {
+    // the client may cancel the request or its deadline be exceeded while working.
+    if err := s.pause(ctx, st.cfg.GreetWithDeadline.StepTime); err != nil {
+      logging.Infof("GreetWithDeadline - %v.", err)
+      return nil, err
+    }
+  }
+  result, err := st.greeting("greet_with_deadline", req.GetGreeting(), 0)
+  if err != nil {
+    return nil, err
+  }
+  res := &greetpb.GreetWithDeadlineResponse{
+    Result: result,
+  }
+  return res, nil
+}
+
+// pause waits for d on the clock of the server, it returns the matching status when ctx
+// is done first.
+func (s *Server) pause(ctx context.Context, d time.Duration) error {
+  timer := s.clock.NewTimer(d)
+  defer timer.Stop()
+  select {
+  case <-timer.C():
+    return nil
+  case <-ctx.Done():
+    return status.FromContextError(ctx.Err()).Err()
+  }
+}
+
+// unaryToggle answers the calls of the disabled methods with Unimplemented.
+func (s *Server) unaryToggle(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  if s.settings().disabled[info.FullMethod] {
+    return nil, status.Errorf(codes.Unimplemented, "%v is disabled!", info.FullMethod)
+  }
+  return handler(ctx, req)
+}
+
+// streamToggle answers the streams of the disabled methods with Unimplemented.
+func (s *Server) streamToggle(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  if s.settings().disabled[info.FullMethod] {
+    return status.Errorf(codes.Unimplemented, "%v is disabled!", info.FullMethod)
+  }
+  return handler(srv, ss)
+}
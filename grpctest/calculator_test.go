@@ -0,0 +1,721 @@
+package grpctest_test
+
+import (
+  "context"
+  "io"
+  "math"
+  "path/filepath"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/clock"
+  "github.com/_dev/grpc-go-example/grpctest"
+
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+  "google.golang.org/protobuf/proto"
+)
+
+func matrix(rows ...[]float64) *calculatorpb.Matrix {
+  m := &calculatorpb.Matrix{}
+  for _, row := range rows {
+    m.Rows = append(m.Rows, &calculatorpb.Vector{Values: row})
+  }
+  return m
+}
+
+func complexNumber(real, imaginary float64) *calculatorpb.Complex {
+  return &calculatorpb.Complex{Real: real, Imaginary: imaginary}
+}
+
+func TestCalculatorUnary(t *testing.T) {
+  c := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{}).Client
+
+  for _, tc := range []struct {
+    name string
+    call func(ctx context.Context) (proto.Message, error)
+    want proto.Message
+    code codes.Code
+  }{
+    {
+      name: "Sum",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.Sum(ctx, &calculatorpb.SumRequest{FirstNumber: 3, SecondNumber: -10})
+      },
+      want: &calculatorpb.SumResponse{SumResult: -7},
+    },
+    {
+      name: "SquareRootUnary",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.SquareRootUnary(ctx, &calculatorpb.SquareRootRequest{Number: 16})
+      },
+      want: &calculatorpb.SquareRootResponse{Number: 16, Result: &calculatorpb.SquareRootResponse_NumberRoot{NumberRoot: 4}},
+    },
+    {
+      name: "SquareRootUnary/negative",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.SquareRootUnary(ctx, &calculatorpb.SquareRootRequest{Number: -4})
+      },
+      code: codes.InvalidArgument,
+    },
+    {
+      name: "SquareRootUnary/complex",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.SquareRootUnary(ctx, &calculatorpb.SquareRootRequest{Number: -4, AllowComplex: true})
+      },
+      want: &calculatorpb.SquareRootResponse{Number: -4, Result: &calculatorpb.SquareRootResponse_ComplexRoot{ComplexRoot: complexNumber(0, 2)}},
+    },
+    {
+      name: "ComplexAdd",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.ComplexAdd(ctx, &calculatorpb.ComplexAddRequest{First: complexNumber(1, 2), Second: complexNumber(3, -4)})
+      },
+      want: &calculatorpb.ComplexAddResponse{Result: complexNumber(4, -2)},
+    },
+    {
+      name: "ComplexMultiply",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.ComplexMultiply(ctx, &calculatorpb.ComplexMultiplyRequest{First: complexNumber(1, 2), Second: complexNumber(3, 4)})
+      },
+      want: &calculatorpb.ComplexMultiplyResponse{Result: complexNumber(-5, 10)},
+    },
+    {
+      name: "ComplexDivide",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.ComplexDivide(ctx, &calculatorpb.ComplexDivideRequest{Dividend: complexNumber(-5, 10), Divisor: complexNumber(1, 2)})
+      },
+      want: &calculatorpb.ComplexDivideResponse{Result: complexNumber(3, 4)},
+    },
+    {
+      name: "ComplexDivide/zero",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.ComplexDivide(ctx, &calculatorpb.ComplexDivideRequest{Dividend: complexNumber(1, 1)})
+      },
+      code: codes.InvalidArgument,
+    },
+    {
+      name: "ComplexModulus",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.ComplexModulus(ctx, &calculatorpb.ComplexModulusRequest{Number: complexNumber(3, 4)})
+      },
+      want: &calculatorpb.ComplexModulusResponse{Modulus: 5},
+    },
+    {
+      name: "ComplexArgument",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.ComplexArgument(ctx, &calculatorpb.ComplexArgumentRequest{Number: complexNumber(0, -1)})
+      },
+      want: &calculatorpb.ComplexArgumentResponse{Argument: -math.Pi / 2},
+    },
+    {
+      name: "MatrixAdd",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.MatrixAdd(ctx, &calculatorpb.MatrixAddRequest{First: matrix([]float64{1, 2}), Second: matrix([]float64{3, 4})})
+      },
+      want: &calculatorpb.MatrixAddResponse{Result: matrix([]float64{4, 6})},
+    },
+    {
+      name: "MatrixAdd/mismatched",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.MatrixAdd(ctx, &calculatorpb.MatrixAddRequest{First: matrix([]float64{1, 2}), Second: matrix([]float64{3})})
+      },
+      code: codes.InvalidArgument,
+    },
+    {
+      name: "MatrixMultiply",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.MatrixMultiply(ctx, &calculatorpb.MatrixMultiplyRequest{
+          First:  matrix([]float64{1, 2}, []float64{3, 4}),
+          Second: matrix([]float64{5}, []float64{6}),
+        })
+      },
+      want: &calculatorpb.MatrixMultiplyResponse{Result: matrix([]float64{17}, []float64{39})},
+    },
+    {
+      name: "MatrixMultiply/mismatched",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.MatrixMultiply(ctx, &calculatorpb.MatrixMultiplyRequest{First: matrix([]float64{1, 2}), Second: matrix([]float64{1, 2})})
+      },
+      code: codes.InvalidArgument,
+    },
+    {
+      name: "MatrixTranspose",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.MatrixTranspose(ctx, &calculatorpb.MatrixTransposeRequest{Matrix: matrix([]float64{1, 2, 3})})
+      },
+      want: &calculatorpb.MatrixTransposeResponse{Result: matrix([]float64{1}, []float64{2}, []float64{3})},
+    },
+    {
+      name: "MatrixTranspose/ragged",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.MatrixTranspose(ctx, &calculatorpb.MatrixTransposeRequest{Matrix: matrix([]float64{1, 2}, []float64{3})})
+      },
+      code: codes.InvalidArgument,
+    },
+    {
+      name: "MatrixDeterminant",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.MatrixDeterminant(ctx, &calculatorpb.MatrixDeterminantRequest{Matrix: matrix([]float64{0, 2}, []float64{3, 0})})
+      },
+      want: &calculatorpb.MatrixDeterminantResponse{Determinant: -6},
+    },
+    {
+      name: "MatrixDeterminant/empty",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.MatrixDeterminant(ctx, &calculatorpb.MatrixDeterminantRequest{})
+      },
+      code: codes.InvalidArgument,
+    },
+    {
+      name: "MatrixInverse",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.MatrixInverse(ctx, &calculatorpb.MatrixInverseRequest{Matrix: matrix([]float64{2, 0}, []float64{0, 4})})
+      },
+      want: &calculatorpb.MatrixInverseResponse{Result: matrix([]float64{0.5, 0}, []float64{0, 0.25})},
+    },
+    {
+      name: "MatrixInverse/singular",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.MatrixInverse(ctx, &calculatorpb.MatrixInverseRequest{Matrix: matrix([]float64{1, 2}, []float64{2, 4})})
+      },
+      code: codes.InvalidArgument,
+    },
+    {
+      name: "SolveLinearSystem",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.SolveLinearSystem(ctx, &calculatorpb.SolveLinearSystemRequest{
+          Coefficients: matrix([]float64{2, 0}, []float64{0, 4}),
+          Constants:    &calculatorpb.Vector{Values: []float64{2, 8}},
+        })
+      },
+      want: &calculatorpb.SolveLinearSystemResponse{Solution: &calculatorpb.Vector{Values: []float64{1, 2}}},
+    },
+    {
+      name: "SolveLinearSystem/mismatched",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.SolveLinearSystem(ctx, &calculatorpb.SolveLinearSystemRequest{
+          Coefficients: matrix([]float64{2, 0}, []float64{0, 4}),
+          Constants:    &calculatorpb.Vector{Values: []float64{2}},
+        })
+      },
+      code: codes.InvalidArgument,
+    },
+    {
+      name: "Evaluate/unknown session",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.Evaluate(ctx, &calculatorpb.EvaluateRequest{SessionId: "nope", Expression: "1"})
+      },
+      code: codes.NotFound,
+    },
+    {
+      name: "CloseSession/unknown session",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.CloseSession(ctx, &calculatorpb.CloseSessionRequest{SessionId: "nope"})
+      },
+      code: codes.NotFound,
+    },
+    {
+      name: "ListHistory/disabled",
+      call: func(ctx context.Context) (proto.Message, error) {
+        return c.ListHistory(ctx, &calculatorpb.ListHistoryRequest{})
+      },
+      code: codes.Unimplemented,
+    },
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+      defer cancel()
+      got, err := tc.call(ctx)
+      if status.Code(err) != tc.code {
+        t.Fatalf("%v() = %v, want code %v", tc.name, err, tc.code)
+      }
+      if tc.want != nil && !proto.Equal(got, tc.want) {
+        t.Fatalf("%v() = %v, want %v", tc.name, got, tc.want)
+      }
+    })
+  }
+}
+
+func TestPrimeNumberDecomposition(t *testing.T) {
+  c := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{}).Client
+
+  for _, tc := range []struct {
+    number int64
+    want   []int64
+  }{
+    {number: 120, want: []int64{2, 2, 2, 3, 5}},
+    {number: 97, want: []int64{97}},
+    {number: 1},
+    {number: -8},
+  } {
+    // Twice, the second answer coming from the cache.
+    for i := 0; i < 2; i++ {
+      stream, err := c.PrimeNumberDecomposition(context.Background(), &calculatorpb.PrimeNumberDecompositionRequest{Number: tc.number})
+      if err != nil {
+        t.Fatal(err)
+      }
+      var got []int64
+      for {
+        res, err := stream.Recv()
+        if err == io.EOF {
+          break
+        }
+        if err != nil {
+          t.Fatalf("PrimeNumberDecomposition(%v) = %v", tc.number, err)
+        }
+        got = append(got, res.GetPrimeFactor())
+      }
+      if !equalInts(got, tc.want) {
+        t.Errorf("PrimeNumberDecomposition(%v) = %v, want %v", tc.number, got, tc.want)
+      }
+    }
+  }
+}
+
+func TestPrimeNumberDecompositionDeadline(t *testing.T) {
+  c := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{}).Client
+
+  // A large prime takes far longer than the deadline to decompose.
+  ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+  defer cancel()
+  stream, err := c.PrimeNumberDecomposition(ctx, &calculatorpb.PrimeNumberDecompositionRequest{Number: 9223372036854775783})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if _, err := stream.Recv(); status.Code(err) != codes.DeadlineExceeded {
+    t.Fatalf("PrimeNumberDecomposition() = %v, want DeadlineExceeded", err)
+  }
+}
+
+func TestComputeAverage(t *testing.T) {
+  c := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{}).Client
+
+  for _, tc := range []struct {
+    numbers []int32
+    want    float64
+  }{
+    {numbers: []int32{1, 2, 3, 4}, want: 2.5},
+    {numbers: []int32{-3}, want: -3},
+    {numbers: nil, want: math.NaN()},
+  } {
+    stream, err := c.ComputeAverage(context.Background())
+    if err != nil {
+      t.Fatal(err)
+    }
+    for _, n := range tc.numbers {
+      if err := stream.Send(&calculatorpb.ComputeAverageRequest{Number: n}); err != nil {
+        t.Fatal(err)
+      }
+    }
+    res, err := stream.CloseAndRecv()
+    if err != nil {
+      t.Fatalf("ComputeAverage(%v) = %v", tc.numbers, err)
+    }
+    if got := res.GetAverage(); got != tc.want && !(math.IsNaN(got) && math.IsNaN(tc.want)) {
+      t.Errorf("ComputeAverage(%v) = %v, want %v", tc.numbers, got, tc.want)
+    }
+  }
+}
+
+func TestFindMaximum(t *testing.T) {
+  c := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{}).Client
+
+  stream, err := c.FindMaximum(context.Background())
+  if err != nil {
+    t.Fatal(err)
+  }
+  // Only a new maximum is answered.
+  for _, step := range []struct {
+    number int32
+    want   int32 // 0 when no answer is expected.
+  }{
+    {number: 1, want: 1},
+    {number: 5, want: 5},
+    {number: 3},
+    {number: -2},
+    {number: 20, want: 20},
+  } {
+    if err := stream.Send(&calculatorpb.FindMaximumRequest{Number: step.number}); err != nil {
+      t.Fatal(err)
+    }
+    if step.want == 0 {
+      continue
+    }
+    res, err := stream.Recv()
+    if err != nil || res.GetMaximum() != step.want {
+      t.Fatalf("FindMaximum() after %v = %v, %v, want %v", step.number, res, err, step.want)
+    }
+  }
+  if err := stream.CloseSend(); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := stream.Recv(); err != io.EOF {
+    t.Fatalf("FindMaximum() after CloseSend = %v, want io.EOF", err)
+  }
+}
+
+func TestSquareRoot(t *testing.T) {
+  c := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{}).Client
+
+  stream, err := c.SquareRoot(context.Background())
+  if err != nil {
+    t.Fatal(err)
+  }
+  // An invalid number is answered in the stream, which goes on.
+  for _, tc := range []struct {
+    req  *calculatorpb.SquareRootRequest
+    want *calculatorpb.SquareRootResponse
+  }{
+    {
+      req:  &calculatorpb.SquareRootRequest{Number: 9},
+      want: &calculatorpb.SquareRootResponse{Number: 9, Result: &calculatorpb.SquareRootResponse_NumberRoot{NumberRoot: 3}},
+    },
+    {
+      req: &calculatorpb.SquareRootRequest{Number: -1},
+      want: &calculatorpb.SquareRootResponse{Number: -1, Result: &calculatorpb.SquareRootResponse_Error{Error: &calculatorpb.SquareRootError{
+        Code:    int32(codes.InvalidArgument),
+        Message: "Received a negative number: -1!",
+      }}},
+    },
+    {
+      req:  &calculatorpb.SquareRootRequest{Number: -9, AllowComplex: true},
+      want: &calculatorpb.SquareRootResponse{Number: -9, Result: &calculatorpb.SquareRootResponse_ComplexRoot{ComplexRoot: complexNumber(0, 3)}},
+    },
+  } {
+    if err := stream.Send(tc.req); err != nil {
+      t.Fatal(err)
+    }
+    res, err := stream.Recv()
+    if err != nil || !proto.Equal(res, tc.want) {
+      t.Fatalf("SquareRoot(%v) = %v, %v, want %v", tc.req, res, err, tc.want)
+    }
+  }
+  stream.CloseSend()
+  if _, err := stream.Recv(); err != io.EOF {
+    t.Fatalf("SquareRoot() after CloseSend = %v, want io.EOF", err)
+  }
+}
+
+func TestComplexRoots(t *testing.T) {
+  c := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{}).Client
+
+  for _, tc := range []struct {
+    degree int32
+    count  int
+    code   codes.Code
+  }{
+    {degree: 4, count: 4},
+    {degree: 1, count: 1},
+    {degree: 0, code: codes.InvalidArgument},
+    {degree: 1001, code: codes.InvalidArgument},
+  } {
+    stream, err := c.ComplexRoots(context.Background(), &calculatorpb.ComplexRootsRequest{Number: complexNumber(16, 0), Degree: tc.degree})
+    if err != nil {
+      t.Fatal(err)
+    }
+    count := 0
+    for {
+      res, err := stream.Recv()
+      if err == io.EOF {
+        break
+      }
+      if err != nil {
+        if status.Code(err) != tc.code {
+          t.Fatalf("ComplexRoots(%v) = %v, want code %v", tc.degree, err, tc.code)
+        }
+        break
+      }
+      // Every root raised to the degree gives back 16.
+      root := complex(res.GetRoot().GetReal(), res.GetRoot().GetImaginary())
+      modulus := math.Pow(math.Hypot(real(root), imag(root)), float64(tc.degree))
+      if math.Abs(modulus-16) > 1e-9 {
+        t.Errorf("ComplexRoots(%v) root %v has a modulus of %v to the degree, want 16", tc.degree, root, modulus)
+      }
+      count++
+    }
+    if count != tc.count {
+      t.Errorf("ComplexRoots(%v) sent %v roots, want %v", tc.degree, count, tc.count)
+    }
+  }
+}
+
+func TestMatrixDeterminantRows(t *testing.T) {
+  c := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{}).Client
+
+  for _, tc := range []struct {
+    name string
+    rows [][]float64
+    want float64
+    code codes.Code
+  }{
+    {name: "square", rows: [][]float64{{2, 0}, {0, 3}}, want: 6},
+    {name: "singular", rows: [][]float64{{1, 2}, {2, 4}}, want: 0},
+    {name: "empty", code: codes.InvalidArgument},
+    {name: "empty row", rows: [][]float64{{}}, code: codes.InvalidArgument},
+    {name: "ragged", rows: [][]float64{{1, 2}, {3}}, code: codes.InvalidArgument},
+    {name: "not square", rows: [][]float64{{1, 2}}, code: codes.InvalidArgument},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      stream, err := c.MatrixDeterminantRows(context.Background())
+      if err != nil {
+        t.Fatal(err)
+      }
+      for _, row := range tc.rows {
+        if err := stream.Send(&calculatorpb.MatrixDeterminantRowsRequest{Row: &calculatorpb.Vector{Values: row}}); err != nil {
+          break // the error comes with CloseAndRecv.
+        }
+      }
+      res, err := stream.CloseAndRecv()
+      if status.Code(err) != tc.code {
+        t.Fatalf("MatrixDeterminantRows() = %v, want code %v", err, tc.code)
+      }
+      if err == nil && res.GetDeterminant() != tc.want {
+        t.Fatalf("MatrixDeterminantRows() = %v, want %v", res.GetDeterminant(), tc.want)
+      }
+    })
+  }
+}
+
+func TestSessions(t *testing.T) {
+  c := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{}).Client
+  ctx := context.Background()
+
+  created, err := c.CreateSession(ctx, &calculatorpb.CreateSessionRequest{})
+  if err != nil {
+    t.Fatal(err)
+  }
+  id := created.GetSessionId()
+  for _, tc := range []struct {
+    expression string
+    want       float64
+    index      int32
+    code       codes.Code
+  }{
+    {expression: "x = 3 * (2 + 1)", want: 9, index: 1},
+    {expression: "ans / 3", want: 3, index: 2},
+    {expression: "$1 + x", want: 18, index: 3},
+    {expression: "y", code: codes.NotFound},
+    {expression: "1 +", code: codes.InvalidArgument},
+    {expression: "ans = 1", code: codes.InvalidArgument},
+  } {
+    res, err := c.Evaluate(ctx, &calculatorpb.EvaluateRequest{SessionId: id, Expression: tc.expression})
+    if status.Code(err) != tc.code {
+      t.Fatalf("Evaluate(%q) = %v, want code %v", tc.expression, err, tc.code)
+    }
+    if err == nil && (res.GetResult() != tc.want || res.GetIndex() != tc.index) {
+      t.Fatalf("Evaluate(%q) = %v, want $%v = %v", tc.expression, res, tc.index, tc.want)
+    }
+  }
+
+  if _, err := c.CloseSession(ctx, &calculatorpb.CloseSessionRequest{SessionId: id}); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := c.Evaluate(ctx, &calculatorpb.EvaluateRequest{SessionId: id, Expression: "x"}); status.Code(err) != codes.NotFound {
+    t.Fatalf("Evaluate() on a closed session = %v, want NotFound", err)
+  }
+}
+
+func TestSessionStream(t *testing.T) {
+  c := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{}).Client
+
+  stream, err := c.Session(context.Background())
+  if err != nil {
+    t.Fatal(err)
+  }
+  // The errors of the expressions are answered in the stream, which goes on.
+  id := ""
+  for _, tc := range []struct {
+    expression string
+    want       float64
+    code       codes.Code
+  }{
+    {expression: "x = 2", want: 2},
+    {expression: "x ^ 10", want: 1024},
+    {expression: "1 / 0", code: codes.InvalidArgument},
+    {expression: "ans - 24", want: 1000},
+  } {
+    if err := stream.Send(&calculatorpb.SessionRequest{Expression: tc.expression}); err != nil {
+      t.Fatal(err)
+    }
+    res, err := stream.Recv()
+    if err != nil {
+      t.Fatalf("Session(%q) = %v", tc.expression, err)
+    }
+    if id == "" {
+      id = res.GetSessionId()
+    }
+    if res.GetSessionId() != id || res.GetExpression() != tc.expression {
+      t.Fatalf("Session(%q) answered %q of session %v, want session %v", tc.expression, res.GetExpression(), res.GetSessionId(), id)
+    }
+    if code := codes.Code(res.GetError().GetCode()); code != tc.code {
+      t.Fatalf("Session(%q) = %v, want code %v", tc.expression, res.GetError(), tc.code)
+    }
+    if tc.code == codes.OK && res.GetEvaluation().GetResult() != tc.want {
+      t.Fatalf("Session(%q) = %v, want %v", tc.expression, res.GetEvaluation().GetResult(), tc.want)
+    }
+  }
+  stream.CloseSend()
+  if _, err := stream.Recv(); err != io.EOF {
+    t.Fatalf("Session() after CloseSend = %v, want io.EOF", err)
+  }
+
+  // Joining a session that is gone ends the stream.
+  stream, err = c.Session(context.Background())
+  if err != nil {
+    t.Fatal(err)
+  }
+  stream.Send(&calculatorpb.SessionRequest{SessionId: "nope", Expression: "1"})
+  if _, err := stream.Recv(); status.Code(err) != codes.NotFound {
+    t.Fatalf("Session() of an unknown session = %v, want NotFound", err)
+  }
+}
+
+func TestListHistory(t *testing.T) {
+  cfg := grpctest.CalculatorConfig()
+  cfg.History.DB = filepath.Join(t.TempDir(), "history.db")
+  c := grpctest.NewCalculator(t, cfg, grpctest.Options{}).Client
+  ctx := context.Background()
+
+  for i := int32(0); i < 3; i++ {
+    if _, err := c.Sum(ctx, &calculatorpb.SumRequest{FirstNumber: i, SecondNumber: 1}); err != nil {
+      t.Fatal(err)
+    }
+  }
+  c.SquareRootUnary(ctx, &calculatorpb.SquareRootRequest{Number: -1})
+
+  page, err := c.ListHistory(ctx, &calculatorpb.ListHistoryRequest{Method: "Sum", PageSize: 2})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(page.GetEntries()) != 2 || page.GetNextPageToken() == "" {
+    t.Fatalf("ListHistory() first page = %v, want 2 entries and a next page", page)
+  }
+  next, err := c.ListHistory(ctx, &calculatorpb.ListHistoryRequest{Method: "Sum", PageSize: 2, PageToken: page.GetNextPageToken()})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(next.GetEntries()) != 1 || next.GetNextPageToken() != "" {
+    t.Fatalf("ListHistory() last page = %v, want 1 entry", next)
+  }
+
+  failed, err := c.ListHistory(ctx, &calculatorpb.ListHistoryRequest{Method: "/calculator.CalculatorService/SquareRootUnary"})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(failed.GetEntries()) != 1 || codes.Code(failed.GetEntries()[0].GetCode()) != codes.InvalidArgument {
+    t.Fatalf("ListHistory() of SquareRootUnary = %v, want 1 InvalidArgument entry", failed)
+  }
+
+  if _, err := c.ListHistory(ctx, &calculatorpb.ListHistoryRequest{PageToken: "!"}); status.Code(err) != codes.InvalidArgument {
+    t.Fatalf("ListHistory() with an invalid token = %v, want InvalidArgument", err)
+  }
+}
+
+func TestCalculatorDisabledMethods(t *testing.T) {
+  calc := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{})
+  cfg := grpctest.CalculatorConfig()
+  cfg.DisabledMethods = "/calculator.CalculatorService/Sum,/calculator.CalculatorService/FindMaximum"
+  if err := calc.Service.Apply(cfg); err != nil {
+    t.Fatal(err)
+  }
+
+  if _, err := calc.Client.Sum(context.Background(), &calculatorpb.SumRequest{}); status.Code(err) != codes.Unimplemented {
+    t.Fatalf("Sum() disabled = %v, want Unimplemented", err)
+  }
+  stream, err := calc.Client.FindMaximum(context.Background())
+  if err != nil {
+    t.Fatal(err)
+  }
+  if _, err := stream.Recv(); status.Code(err) != codes.Unimplemented {
+    t.Fatalf("FindMaximum() disabled = %v, want Unimplemented", err)
+  }
+}
+
+func equalInts(a, b []int64) bool {
+  if len(a) != len(b) {
+    return false
+  }
+  for i := range a {
+    if a[i] != b[i] {
+      return false
+    }
+  }
+  return true
+}
+
+func TestSessionExpiry(t *testing.T) {
+  clk := clock.NewFake(time.Now())
+  cfg := grpctest.CalculatorConfig()
+  cfg.SessionTTL = time.Minute
+  c := grpctest.NewCalculator(t, cfg, grpctest.Options{Clock: clk}).Client
+  ctx := context.Background()
+
+  created, err := c.CreateSession(ctx, &calculatorpb.CreateSessionRequest{})
+  if err != nil {
+    t.Fatal(err)
+  }
+  id := created.GetSessionId()
+
+  // Using the session keeps it alive.
+  for i := 0; i < 3; i++ {
+    clk.Advance(50 * time.Second)
+    if _, err := c.Evaluate(ctx, &calculatorpb.EvaluateRequest{SessionId: id, Expression: "1"}); err != nil {
+      t.Fatalf("Evaluate() after %v = %v", 50*time.Second*time.Duration(i+1), err)
+    }
+  }
+  clk.Advance(time.Minute + time.Second)
+  if _, err := c.Evaluate(ctx, &calculatorpb.EvaluateRequest{SessionId: id, Expression: "1"}); status.Code(err) != codes.NotFound {
+    t.Fatalf("Evaluate() on an expired session = %v, want NotFound", err)
+  }
+}
+
+func TestMaxSessions(t *testing.T) {
+  clk := clock.NewFake(time.Now())
+  cfg := grpctest.CalculatorConfig()
+  cfg.SessionTTL = time.Minute
+  cfg.MaxSessions = 2
+  c := grpctest.NewCalculator(t, cfg, grpctest.Options{Clock: clk}).Client
+  ctx := context.Background()
+
+  create := func() (string, error) {
+    res, err := c.CreateSession(ctx, &calculatorpb.CreateSessionRequest{})
+    return res.GetSessionId(), err
+  }
+  first, err := create()
+  if err != nil {
+    t.Fatal(err)
+  }
+  if _, err := create(); err != nil {
+    t.Fatal(err)
+  }
+
+  // Past the limit, neither CreateSession nor a Session stream opens one.
+  if _, err := create(); status.Code(err) != codes.ResourceExhausted {
+    t.Fatalf("CreateSession() over the limit = %v, want ResourceExhausted", err)
+  }
+  stream, err := c.Session(ctx)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if err := stream.Send(&calculatorpb.SessionRequest{Expression: "1"}); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := stream.Recv(); status.Code(err) != codes.ResourceExhausted {
+    t.Fatalf("Session() over the limit = %v, want ResourceExhausted", err)
+  }
+
+  // Closing a session makes room, and so do the expired ones.
+  if _, err := c.CloseSession(ctx, &calculatorpb.CloseSessionRequest{SessionId: first}); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := create(); err != nil {
+    t.Fatalf("CreateSession() after CloseSession() = %v", err)
+  }
+  clk.Advance(time.Minute + time.Second)
+  for i := 0; i < 2; i++ {
+    if _, err := create(); err != nil {
+      t.Fatalf("CreateSession() %v after the others expired = %v", i, err)
+    }
+  }
+}
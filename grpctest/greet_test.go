@@ -0,0 +1,232 @@
+package grpctest_test
+
+import (
+  "context"
+  "io"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/clock"
+  "github.com/_dev/grpc-go-example/greet/greetpb"
+  "github.com/_dev/grpc-go-example/grpctest"
+
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+)
+
+func greeting(firstName string) *greetpb.Greeting {
+  return &greetpb.Greeting{FirstName: firstName, LastName: "Maarek"}
+}
+
+func TestGreet(t *testing.T) {
+  g := grpctest.NewGreet(t, grpctest.GreetConfig(), grpctest.Options{})
+
+  res, err := g.Client.Greet(context.Background(), &greetpb.GreetRequest{Greeting: greeting("Stephane")})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if want := "Hello Stephane!"; res.GetResult() != want {
+    t.Fatalf("Greet() = %q, want %q", res.GetResult(), want)
+  }
+}
+
+func TestGreetManyTimes(t *testing.T) {
+  clk := clock.NewFake(time.Now())
+  cfg := grpctest.GreetConfig()
+  cfg.GreetManyTimes.Count = 3
+  cfg.GreetManyTimes.Interval = time.Second
+  g := grpctest.NewGreet(t, cfg, grpctest.Options{Clock: clk})
+
+  stream, err := g.Client.GreetManyTimes(context.Background(), &greetpb.GreetManyTimesRequest{Greeting: greeting("Stephane")})
+  if err != nil {
+    t.Fatal(err)
+  }
+  for _, want := range []string{"Hello Stephane number 0!", "Hello Stephane number 1!", "Hello Stephane number 2!"} {
+    res, err := stream.Recv()
+    if err != nil || res.GetResult() != want {
+      t.Fatalf("GreetManyTimes() = %v, %v, want %q", res, err, want)
+    }
+    // The next greeting waits for the interval to pass.
+    clk.BlockUntil(1)
+    clk.Advance(time.Second)
+  }
+  if _, err := stream.Recv(); err != io.EOF {
+    t.Fatalf("GreetManyTimes() after the last greeting = %v, want io.EOF", err)
+  }
+}
+
+func TestGreetManyTimesCanceled(t *testing.T) {
+  clk := clock.NewFake(time.Now())
+  cfg := grpctest.GreetConfig()
+  cfg.GreetManyTimes.Interval = time.Second
+  g := grpctest.NewGreet(t, cfg, grpctest.Options{Clock: clk})
+
+  ctx, cancel := context.WithCancel(context.Background())
+  defer cancel()
+  stream, err := g.Client.GreetManyTimes(ctx, &greetpb.GreetManyTimesRequest{Greeting: greeting("Stephane")})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if _, err := stream.Recv(); err != nil {
+    t.Fatal(err)
+  }
+  clk.BlockUntil(1)
+  cancel()
+  if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+    t.Fatalf("GreetManyTimes() canceled = %v, want Canceled", err)
+  }
+  // The server stops waiting between the greetings.
+  deadline := time.Now().Add(5 * time.Second)
+  for clk.Waiters() != 0 {
+    if time.Now().After(deadline) {
+      t.Fatal("GreetManyTimes() still waits on the clock after the cancellation")
+    }
+    time.Sleep(time.Millisecond)
+  }
+}
+
+func TestLongGreet(t *testing.T) {
+  g := grpctest.NewGreet(t, grpctest.GreetConfig(), grpctest.Options{})
+
+  for _, tc := range []struct {
+    names []string
+    want  string
+  }{
+    {names: []string{"Stephane", "John", "Lucy"}, want: "Hello Stephane! Hello John! Hello Lucy! "},
+    {names: nil, want: ""},
+  } {
+    stream, err := g.Client.LongGreet(context.Background())
+    if err != nil {
+      t.Fatal(err)
+    }
+    for _, name := range tc.names {
+      if err := stream.Send(&greetpb.LongGreetRequest{Greeting: greeting(name)}); err != nil {
+        t.Fatal(err)
+      }
+    }
+    res, err := stream.CloseAndRecv()
+    if err != nil || res.GetResult() != tc.want {
+      t.Fatalf("LongGreet(%v) = %v, %v, want %q", tc.names, res, err, tc.want)
+    }
+  }
+}
+
+func TestGreetEveryone(t *testing.T) {
+  g := grpctest.NewGreet(t, grpctest.GreetConfig(), grpctest.Options{})
+
+  stream, err := g.Client.GreetEveryone(context.Background())
+  if err != nil {
+    t.Fatal(err)
+  }
+  // Each answer greets everyone so far.
+  for _, tc := range []struct {
+    name string
+    want string
+  }{
+    {name: "Stephane", want: "Hello Stephane! "},
+    {name: "John", want: "Hello Stephane! Hello John! "},
+  } {
+    if err := stream.Send(&greetpb.GreetEveryoneRequest{Greeting: greeting(tc.name)}); err != nil {
+      t.Fatal(err)
+    }
+    res, err := stream.Recv()
+    if err != nil || res.GetResult() != tc.want {
+      t.Fatalf("GreetEveryone(%v) = %v, %v, want %q", tc.name, res, err, tc.want)
+    }
+  }
+  stream.CloseSend()
+  if _, err := stream.Recv(); err != io.EOF {
+    t.Fatalf("GreetEveryone() after CloseSend = %v, want io.EOF", err)
+  }
+}
+
+func TestGreetWithDeadline(t *testing.T) {
+  cfg := grpctest.GreetConfig()
+  cfg.GreetWithDeadline.StepTime = time.Second
+
+  for _, tc := range []struct {
+    name string
+    // run drives the clock while the call works, from its own goroutine.
+    run  func(clk *clock.Fake, cancel context.CancelFunc)
+    want string
+    code codes.Code
+  }{
+    {
+      name: "in time",
+      run: func(clk *clock.Fake, cancel context.CancelFunc) {
+        for i := 0; i < cfg.GreetWithDeadline.Steps; i++ {
+          clk.BlockUntil(1)
+          clk.Advance(time.Second)
+        }
+      },
+      want: "Hello Stephane",
+    },
+    {
+      name: "deadline exceeded",
+      run:  func(clk *clock.Fake, cancel context.CancelFunc) {},
+      code: codes.DeadlineExceeded,
+    },
+    {
+      name: "canceled",
+      run: func(clk *clock.Fake, cancel context.CancelFunc) {
+        clk.BlockUntil(1)
+        clk.Advance(time.Second)
+        clk.BlockUntil(1)
+        cancel()
+      },
+      code: codes.Canceled,
+    },
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      clk := clock.NewFake(time.Now())
+      g := grpctest.NewGreet(t, cfg, grpctest.Options{Clock: clk})
+
+      // The fake clock never reaches the deadline, which is on the real one.
+      timeout := 5 * time.Second
+      if tc.code == codes.DeadlineExceeded {
+        timeout = 50 * time.Millisecond
+      }
+      ctx, cancel := context.WithTimeout(context.Background(), timeout)
+      defer cancel()
+      go tc.run(clk, cancel)
+      res, err := g.Client.GreetWithDeadline(ctx, &greetpb.GreetWithDeadlineRequest{Greeting: greeting("Stephane")})
+      if status.Code(err) != tc.code {
+        t.Fatalf("GreetWithDeadline() = %v, want code %v", err, tc.code)
+      }
+      if err == nil && res.GetResult() != tc.want {
+        t.Fatalf("GreetWithDeadline() = %q, want %q", res.GetResult(), tc.want)
+      }
+    })
+  }
+}
+
+func TestGreetDisabledMethods(t *testing.T) {
+  g := grpctest.NewGreet(t, grpctest.GreetConfig(), grpctest.Options{})
+  cfg := grpctest.GreetConfig()
+  cfg.DisabledMethods = "/greet.GreetService/Greet,/greet.GreetService/LongGreet"
+  if err := g.Service.Apply(cfg); err != nil {
+    t.Fatal(err)
+  }
+
+  if _, err := g.Client.Greet(context.Background(), &greetpb.GreetRequest{Greeting: greeting("Stephane")}); status.Code(err) != codes.Unimplemented {
+    t.Fatalf("Greet() disabled = %v, want Unimplemented", err)
+  }
+  stream, err := g.Client.LongGreet(context.Background())
+  if err != nil {
+    t.Fatal(err)
+  }
+  if _, err := stream.CloseAndRecv(); status.Code(err) != codes.Unimplemented {
+    t.Fatalf("LongGreet() disabled = %v, want Unimplemented", err)
+  }
+
+  // The other methods still answer, and the disabled ones again once enabled.
+  if _, err := g.Client.GreetWithDeadline(context.Background(), &greetpb.GreetWithDeadlineRequest{Greeting: greeting("Stephane")}); err != nil {
+    t.Fatalf("GreetWithDeadline() = %v", err)
+  }
+  if err := g.Service.Apply(grpctest.GreetConfig()); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := g.Client.Greet(context.Background(), &greetpb.GreetRequest{Greeting: greeting("Stephane")}); err != nil {
+    t.Fatalf("Greet() enabled again = %v", err)
+  }
+}
@@ -0,0 +1,197 @@
+// Package grpctest starts the greet and calculator services in-process, over an in-memory
+// bufconn listener, and returns clients connected to them, for the tests:
+//
+//	g := grpctest.NewGreet(t, grpctest.GreetConfig(), grpctest.Options{})
+//	res, err := g.Client.Greet(ctx, &greetpb.GreetRequest{...})
+//
+// The servers are stopped when the test ends.
+package grpctest
+
+import (
+  "context"
+  "net"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/calculator/calculatorservice"
+  "github.com/_dev/grpc-go-example/clock"
+  "github.com/_dev/grpc-go-example/greet/greetpb"
+  "github.com/_dev/grpc-go-example/greet/greetservice"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/connectivity"
+  "google.golang.org/grpc/test/bufconn"
+)
+
+// BufferSize is the size of the in-memory buffers of the connections.
+const BufferSize = 1 << 20
+
+// ReadyTimeout bounds the wait for the connection to the server to be ready.
+const ReadyTimeout = 5 * time.Second
+
+// Options customize a server and the connection to it.
+type Options struct {
+  // UnaryInterceptors and StreamInterceptors are installed on the server after the
+  // ones of the service.
+  UnaryInterceptors  []grpc.UnaryServerInterceptor
+  StreamInterceptors []grpc.StreamServerInterceptor
+
+  ServerOptions []grpc.ServerOption
+  DialOptions   []grpc.DialOption
+
+  // Clock paces and times the service, the real clock when nil. A clock.Fake lets the
+  // test advance the time by hand.
+  Clock clock.Clock
+}
+
+// Harness is a gRPC server listening in memory and a connection to it.
+type Harness struct {
+  Server *grpc.Server
+  Conn   *grpc.ClientConn
+
+  listener *bufconn.Listener
+}
+
+// Greet is the GreetService started in memory.
+type Greet struct {
+  *Harness
+  Service *greetservice.Server
+  Client  greetpb.GreetServiceClient
+}
+
+// Calculator is the CalculatorService started in memory.
+type Calculator struct {
+  *Harness
+  Service *calculatorservice.Server
+  Client  calculatorpb.CalculatorServiceClient
+}
+
+// GreetConfig is the default configuration of the greet server without its pauses, so
+// that GreetManyTimes and GreetWithDeadline answer at once.
+func GreetConfig() greetservice.Config {
+  cfg := greetservice.DefaultConfig()
+  cfg.GreetManyTimes.Interval = 0
+  cfg.GreetWithDeadline.StepTime = 0
+  return cfg
+}
+
+// CalculatorConfig is the default configuration of the calculator server without its
+// history, which would otherwise be written in the working directory. Set History.DB to
+// a file of t.TempDir() to test it.
+func CalculatorConfig() calculatorservice.Config {
+  cfg := calculatorservice.DefaultConfig()
+  cfg.History.DB = ""
+  return cfg
+}
+
+// NewGreet starts the GreetService with cfg, failing t when it cannot.
+func NewGreet(t testing.TB, cfg greetservice.Config, o Options) *Greet {
+  t.Helper()
+  srv, err := greetservice.NewServer(cfg, o.Clock)
+  if err != nil {
+    t.Fatalf("Failed to create the GreetService: %v", err)
+  }
+  h := start(t, srv.UnaryInterceptors(), srv.StreamInterceptors(), o, func(s *grpc.Server) {
+    greetpb.RegisterGreetServiceServer(s, srv)
+  })
+  return &Greet{
+    Harness: h,
+    Service: srv,
+    Client:  greetpb.NewGreetServiceClient(h.Conn),
+  }
+}
+
+// NewCalculator starts the CalculatorService with cfg, failing t when it cannot.
+func NewCalculator(t testing.TB, cfg calculatorservice.Config, o Options) *Calculator {
+  t.Helper()
+  srv, err := calculatorservice.NewServer(cfg, o.Clock)
+  if err != nil {
+    t.Fatalf("Failed to create the CalculatorService: %v", err)
+  }
+  t.Cleanup(func() { srv.Close() })
+  h := start(t, srv.UnaryInterceptors(), srv.StreamInterceptors(), o, func(s *grpc.Server) {
+    calculatorpb.RegisterCalculatorServiceServer(s, srv)
+  })
+  return &Calculator{
+    Harness: h,
+    Service: srv,
+    Client:  calculatorpb.NewCalculatorServiceClient(h.Conn),
+  }
+}
+
+// start serves in memory what register registers, behind the interceptors of the
+// service then the ones of o, and connects to it.
+func start(t testing.TB, unary []grpc.UnaryServerInterceptor, stream []grpc.StreamServerInterceptor, o Options, register func(*grpc.Server)) *Harness {
+  t.Helper()
+  h := &Harness{
+    listener: bufconn.Listen(BufferSize),
+  }
+  serverOpts := append([]grpc.ServerOption{
+    grpc.ChainUnaryInterceptor(append(unary, o.UnaryInterceptors...)...),
+    grpc.ChainStreamInterceptor(append(stream, o.StreamInterceptors...)...),
+  }, o.ServerOptions...)
+  h.Server = grpc.NewServer(serverOpts...)
+  register(h.Server)
+  go h.Server.Serve(h.listener)
+  t.Cleanup(h.Close)
+
+  var err error
+  h.Conn, err = grpc.Dial("bufnet", h.dialOptions(o.DialOptions)...)
+  if err != nil {
+    t.Fatalf("Failed to connect: %v", err)
+  }
+  if err := h.waitReady(); err != nil {
+    t.Fatalf("Failed to connect: %v", err)
+  }
+  return h
+}
+
+// waitReady waits for the connection to be ready, so that the first call of a test does
+// not also measure the connection.
+func (h *Harness) waitReady() error {
+  ctx, cancel := context.WithTimeout(context.Background(), ReadyTimeout)
+  defer cancel()
+  h.Conn.Connect()
+  for {
+    state := h.Conn.GetState()
+    if state == connectivity.Ready {
+      return nil
+    }
+    if !h.Conn.WaitForStateChange(ctx, state) {
+      return ctx.Err()
+    }
+  }
+}
+
+// Dial opens another connection to the server, closed when the test ends.
+func (h *Harness) Dial(t testing.TB, opts ...grpc.DialOption) *grpc.ClientConn {
+  t.Helper()
+  cc, err := grpc.Dial("bufnet", h.dialOptions(opts)...)
+  if err != nil {
+    t.Fatalf("Failed to connect: %v", err)
+  }
+  t.Cleanup(func() { cc.Close() })
+  return cc
+}
+
+// ContextDialer connects through the listener in memory whatever the address dialed, for
+// the clients dialing on their own.
+func (h *Harness) ContextDialer() grpc.DialOption {
+  return grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+    return h.listener.DialContext(ctx)
+  })
+}
+
+// dialOptions connect through the listener in memory, then apply opts.
+func (h *Harness) dialOptions(opts []grpc.DialOption) []grpc.DialOption {
+  return append([]grpc.DialOption{h.ContextDialer(), grpc.WithInsecure()}, opts...)
+}
+
+// Close closes the connection and stops the server. It is called when the test ends.
+func (h *Harness) Close() {
+  if h.Conn != nil {
+    h.Conn.Close()
+  }
+  h.Server.Stop()
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+  "strings"
+
+  "github.com/_dev/grpc-go-example/admin/adminpb"
+)
+
+var adminCommands = map[string]action{
+  "reload": adminReload,
+}
+
+func adminReload(e *env, args []string) error {
+  args, err := e.parse("admin reload", args, nil)
+  if err != nil {
+    return err
+  }
+  // The AdminService listens apart from the services, on an address of each server.
+  switch {
+  case len(args) == 1 && defaultAdminAddrs[args[0]] != "":
+    e.defaultAddr = defaultAdminAddrs[args[0]]
+  case len(args) == 0 && e.opts.addr != "":
+  default:
+    return usagef("admin reload takes the server to reload, greet or calc, unless -addr is given")
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := adminpb.NewAdminServiceClient(cc).Reload(ctx, &adminpb.ReloadRequest{})
+  if err != nil {
+    return err
+  }
+  text := res.GetConfig()
+  if len(res.GetPending()) > 0 {
+    text += "# pending a restart: " + strings.Join(res.GetPending(), ", ")
+  }
+  return e.print(res, strings.TrimSuffix(text, "\n"))
+}
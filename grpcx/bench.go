@@ -0,0 +1,364 @@
+package main
+
+import (
+  "context"
+  "encoding/json"
+  "flag"
+  "fmt"
+  "io"
+  "math"
+  "sort"
+  "strings"
+  "sync"
+  "sync/atomic"
+  "time"
+
+  _ "github.com/_dev/grpc-go-example/admin/adminpb"
+  _ "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  _ "github.com/_dev/grpc-go-example/greet/greetpb"
+
+  "golang.org/x/time/rate"
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/status"
+  "google.golang.org/protobuf/encoding/protojson"
+  "google.golang.org/protobuf/proto"
+  "google.golang.org/protobuf/reflect/protoreflect"
+  "google.golang.org/protobuf/reflect/protoregistry"
+  "google.golang.org/protobuf/types/dynamicpb"
+)
+
+var benchCommands = map[string]action{
+  "run": bench,
+}
+
+// benchOptions are the flags of grpcx bench.
+type benchOptions struct {
+  concurrency int
+  conns       int
+  rate        float64
+  duration    time.Duration
+  calls       int64
+  data        string
+  size        int
+  messages    int
+}
+
+// benchStats gathers the outcome of the calls of a worker, then of all of them.
+type benchStats struct {
+  latencies []time.Duration
+  codes     map[codes.Code]int
+  sent      int64
+  received  int64
+}
+
+func (s *benchStats) merge(o *benchStats) {
+  s.latencies = append(s.latencies, o.latencies...)
+  for code, n := range o.codes {
+    s.codes[code] += n
+  }
+  s.sent += o.sent
+  s.received += o.received
+}
+
+// benchReport is printed at the end of grpcx bench, the latencies in milliseconds.
+type benchReport struct {
+  Method           string         `json:"method"`
+  Concurrency      int            `json:"concurrency"`
+  Rate             float64        `json:"rate,omitempty"`
+  RequestBytes     int            `json:"request_bytes"`
+  Seconds          float64        `json:"seconds"`
+  Calls            int            `json:"calls"`
+  Errors           int            `json:"errors"`
+  CallsPerSecond   float64        `json:"calls_per_second"`
+  MessagesSent     int64          `json:"messages_sent"`
+  MessagesReceived int64          `json:"messages_received"`
+  Codes            map[string]int `json:"codes"`
+  Latency          struct {
+    Min  float64 `json:"min"`
+    Mean float64 `json:"mean"`
+    P50  float64 `json:"p50"`
+    P90  float64 `json:"p90"`
+    P99  float64 `json:"p99"`
+    Max  float64 `json:"max"`
+  } `json:"latency_ms"`
+}
+
+// bench calls a method over and over, then reports the throughput, the status codes and
+// the latencies of the calls. A stream counts as one call, from its opening to its end.
+func bench(e *env, args []string) error {
+  o := benchOptions{concurrency: 10, conns: 1, duration: 10 * time.Second, messages: 10}
+  args, err := e.parse("bench", args, func(fs *flag.FlagSet) {
+    fs.IntVar(&o.concurrency, "c", o.concurrency, "calls in flight at once")
+    fs.IntVar(&o.conns, "conns", o.conns, "connections the calls are spread over")
+    fs.Float64Var(&o.rate, "rate", o.rate, "most calls started per second, 0 for no limit")
+    fs.DurationVar(&o.duration, "d", o.duration, "how long to keep calling")
+    fs.Int64Var(&o.calls, "n", o.calls, "stop after this many calls, 0 for no limit")
+    fs.StringVar(&o.data, "data", o.data, "request as JSON, as in '{\"greeting\": {\"first_name\": \"Ana\"}}'")
+    fs.IntVar(&o.size, "size", o.size, "fill the first string or bytes field of the request with this many bytes")
+    fs.IntVar(&o.messages, "messages", o.messages, "messages sent by each call of a client or bidirectional stream")
+  })
+  if err != nil {
+    return err
+  }
+  if len(args) != 1 {
+    return usagef("bench takes a method, as in calculator.CalculatorService/Sum or just Sum")
+  }
+  if o.concurrency < 1 || o.conns < 1 || o.messages < 1 || o.duration <= 0 {
+    return usagef("-c, -conns, -messages and -d must be positive")
+  }
+  md, err := findMethod(args[0])
+  if err != nil {
+    return err
+  }
+  req := dynamicpb.NewMessage(md.Input())
+  if o.data != "" {
+    if err := protojson.Unmarshal([]byte(o.data), req); err != nil {
+      return usagef("invalid -data for %v: %v", md.Input().FullName(), err)
+    }
+  }
+  if o.size > 0 && !pad(req, o.size) {
+    return usagef("-size: %v has no string or bytes field", md.Input().FullName())
+  }
+
+  conns := make([]*grpc.ClientConn, o.conns)
+  for i := range conns {
+    if conns[i], err = e.dial(); err != nil {
+      return err
+    }
+    defer conns[i].Close()
+  }
+
+  // Calling from each worker until the time or the calls run out...
+  ctx, cancel := context.WithTimeout(context.Background(), o.duration)
+  defer cancel()
+  limiter := rate.NewLimiter(rate.Inf, 1)
+  if o.rate > 0 {
+    limiter = rate.NewLimiter(rate.Limit(o.rate), 1)
+  }
+  method := fmt.Sprintf("/%v/%v", md.Parent().FullName(), md.Name())
+  var started int64
+  var mu sync.Mutex
+  var wg sync.WaitGroup
+  total := &benchStats{codes: map[codes.Code]int{}}
+  start := time.Now()
+  for w := 0; w < o.concurrency; w++ {
+    wg.Add(1)
+    go func(cc *grpc.ClientConn) {
+      defer wg.Done()
+      stats := &benchStats{codes: map[codes.Code]int{}}
+      for ctx.Err() == nil {
+        if limiter.Wait(ctx) != nil {
+          break // the time runs out before the next call.
+        }
+        if o.calls > 0 && atomic.AddInt64(&started, 1) > o.calls {
+          break
+        }
+        callStart := time.Now()
+        err := e.call(cc, method, md, req, o.messages, stats)
+        stats.latencies = append(stats.latencies, time.Since(callStart))
+        stats.codes[status.Code(err)]++
+      }
+      mu.Lock()
+      total.merge(stats)
+      mu.Unlock()
+    }(conns[w%len(conns)])
+  }
+  wg.Wait()
+
+  r := report(total, time.Since(start))
+  r.Method = method
+  r.Concurrency = o.concurrency
+  r.Rate = o.rate
+  r.RequestBytes = proto.Size(req)
+  if e.opts.output == "json" {
+    b, err := json.Marshal(r)
+    if err != nil {
+      return err
+    }
+    _, err = fmt.Fprintln(e.out, string(b))
+    return err
+  }
+  return printReport(e.out, r)
+}
+
+// call makes one call of any shape, sending messages requests on the client streams.
+func (e *env) call(cc *grpc.ClientConn, method string, md protoreflect.MethodDescriptor, req proto.Message, messages int, stats *benchStats) error {
+  ctx, cancel := e.context()
+  defer cancel()
+
+  if !md.IsStreamingClient() && !md.IsStreamingServer() {
+    stats.sent++
+    err := cc.Invoke(ctx, method, req, dynamicpb.NewMessage(md.Output()))
+    if err == nil {
+      stats.received++
+    }
+    return err
+  }
+
+  desc := &grpc.StreamDesc{ClientStreams: md.IsStreamingClient(), ServerStreams: md.IsStreamingServer()}
+  stream, err := cc.NewStream(ctx, desc, method)
+  if err != nil {
+    return err
+  }
+  if !md.IsStreamingClient() {
+    messages = 1
+  }
+  // The sends are counted once the sender is done, a server may answer before it is.
+  var sent int64
+  done := make(chan struct{})
+  go func() {
+    defer close(done)
+    for i := 0; i < messages; i++ {
+      if stream.SendMsg(req) != nil {
+        return // the error is told by RecvMsg.
+      }
+      sent++
+    }
+    stream.CloseSend()
+  }()
+  defer func() {
+    cancel()
+    <-done
+    stats.sent += sent
+  }()
+  for {
+    err := stream.RecvMsg(dynamicpb.NewMessage(md.Output()))
+    if err == io.EOF {
+      return nil
+    }
+    if err != nil {
+      return err
+    }
+    stats.received++
+    if !md.IsStreamingServer() {
+      return nil
+    }
+  }
+}
+
+// findMethod looks a method up among the services known to grpcx, by its full name or
+// its name alone when no other service has a method of that name.
+func findMethod(name string) (protoreflect.MethodDescriptor, error) {
+  full := strings.Replace(strings.TrimPrefix(name, "/"), "/", ".", 1)
+  var found []protoreflect.MethodDescriptor
+  protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+    for i := 0; i < fd.Services().Len(); i++ {
+      methods := fd.Services().Get(i).Methods()
+      for j := 0; j < methods.Len(); j++ {
+        md := methods.Get(j)
+        if string(md.FullName()) == full || string(md.Name()) == full {
+          found = append(found, md)
+        }
+      }
+    }
+    return true
+  })
+  switch len(found) {
+  case 0:
+    return nil, usagef("unknown method %q", name)
+  case 1:
+    return found[0], nil
+  default:
+    var names []string
+    for _, md := range found {
+      names = append(names, string(md.FullName()))
+    }
+    return nil, usagef("ambiguous method %q, expecting one of %v", name, strings.Join(names, ", "))
+  }
+}
+
+// pad fills the first string or bytes field of m, looking into its message fields, with
+// size bytes. It returns false when there is no such field.
+func pad(m protoreflect.Message, size int) bool {
+  fields := m.Descriptor().Fields()
+  for i := 0; i < fields.Len(); i++ {
+    f := fields.Get(i)
+    if f.IsList() || f.IsMap() {
+      continue
+    }
+    switch f.Kind() {
+    case protoreflect.StringKind:
+      m.Set(f, protoreflect.ValueOfString(strings.Repeat("x", size)))
+      return true
+    case protoreflect.BytesKind:
+      m.Set(f, protoreflect.ValueOfBytes(make([]byte, size)))
+      return true
+    case protoreflect.MessageKind:
+      had := m.Has(f)
+      if pad(m.Mutable(f).Message(), size) {
+        return true
+      }
+      if !had {
+        m.Clear(f)
+      }
+    }
+  }
+  return false
+}
+
+func report(s *benchStats, elapsed time.Duration) *benchReport {
+  r := &benchReport{
+    Seconds:          elapsed.Seconds(),
+    Calls:            len(s.latencies),
+    CallsPerSecond:   float64(len(s.latencies)) / elapsed.Seconds(),
+    MessagesSent:     s.sent,
+    MessagesReceived: s.received,
+    Codes:            map[string]int{},
+  }
+  for code, n := range s.codes {
+    r.Codes[code.String()] = n
+    if code != codes.OK {
+      r.Errors += n
+    }
+  }
+  if len(s.latencies) == 0 {
+    return r
+  }
+
+  sort.Slice(s.latencies, func(i, j int) bool { return s.latencies[i] < s.latencies[j] })
+  ms := func(d time.Duration) float64 {
+    return float64(d) / float64(time.Millisecond)
+  }
+  percentile := func(p float64) float64 {
+    i := int(math.Ceil(p*float64(len(s.latencies)))) - 1
+    if i < 0 {
+      i = 0
+    }
+    return ms(s.latencies[i])
+  }
+  var sum time.Duration
+  for _, l := range s.latencies {
+    sum += l
+  }
+  r.Latency.Min = ms(s.latencies[0])
+  r.Latency.Mean = ms(sum / time.Duration(len(s.latencies)))
+  r.Latency.P50 = percentile(0.5)
+  r.Latency.P90 = percentile(0.9)
+  r.Latency.P99 = percentile(0.99)
+  r.Latency.Max = ms(s.latencies[len(s.latencies)-1])
+  return r
+}
+
+func printReport(w io.Writer, r *benchReport) error {
+  limit := "no rate limit"
+  if r.Rate > 0 {
+    limit = fmt.Sprintf("at most %g calls/s", r.Rate)
+  }
+  fmt.Fprintf(w, "Method:    %v, requests of %d bytes\n", r.Method, r.RequestBytes)
+  fmt.Fprintf(w, "Load:      %d calls in flight, %v\n", r.Concurrency, limit)
+  fmt.Fprintf(w, "Calls:     %d in %.2fs, %.1f calls/s, %d errors\n", r.Calls, r.Seconds, r.CallsPerSecond, r.Errors)
+  fmt.Fprintf(w, "Messages:  %d sent, %d received\n", r.MessagesSent, r.MessagesReceived)
+  fmt.Fprintf(w, "Latency:   min %.2fms, mean %.2fms, p50 %.2fms, p90 %.2fms, p99 %.2fms, max %.2fms\n",
+    r.Latency.Min, r.Latency.Mean, r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.Max)
+  fmt.Fprintln(w, "Status codes:")
+  var names []string
+  for name := range r.Codes {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+  for _, name := range names {
+    fmt.Fprintf(w, "  %-18v %d\n", name, r.Codes[name])
+  }
+  return nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+  "bytes"
+  "encoding/json"
+  "errors"
+  "math/rand"
+  "strings"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/greet/greetpb"
+  "github.com/_dev/grpc-go-example/grpctest"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/protobuf/proto"
+)
+
+func TestReport(t *testing.T) {
+  // The latencies of 1 to 100ms, in any order.
+  s := &benchStats{codes: map[codes.Code]int{codes.OK: 97, codes.Unavailable: 2, codes.DeadlineExceeded: 1}, sent: 100, received: 97}
+  for _, i := range rand.Perm(100) {
+    s.latencies = append(s.latencies, time.Duration(i+1)*time.Millisecond)
+  }
+  r := report(s, 2*time.Second)
+
+  if r.Calls != 100 || r.CallsPerSecond != 50 || r.Errors != 3 || r.MessagesSent != 100 || r.MessagesReceived != 97 {
+    t.Errorf("report() = %v calls, %v calls/s, %v errors, %v sent, %v received, want 100, 50, 3, 100 and 97",
+      r.Calls, r.CallsPerSecond, r.Errors, r.MessagesSent, r.MessagesReceived)
+  }
+  if want := map[string]int{"OK": 97, "Unavailable": 2, "DeadlineExceeded": 1}; len(r.Codes) != len(want) || r.Codes["OK"] != 97 || r.Codes["Unavailable"] != 2 || r.Codes["DeadlineExceeded"] != 1 {
+    t.Errorf("report() codes = %v, want %v", r.Codes, want)
+  }
+  l := r.Latency
+  if l.Min != 1 || l.Mean != 50.5 || l.P50 != 50 || l.P90 != 90 || l.P99 != 99 || l.Max != 100 {
+    t.Errorf("report() latencies = %+v, want min 1, mean 50.5, p50 50, p90 90, p99 99 and max 100", l)
+  }
+
+  // A single call makes every percentile, no call none.
+  r = report(&benchStats{latencies: []time.Duration{3 * time.Millisecond}, codes: map[codes.Code]int{codes.OK: 1}}, time.Second)
+  if l := r.Latency; l.Min != 3 || l.P50 != 3 || l.P99 != 3 || l.Max != 3 {
+    t.Errorf("report() of a call = %+v, want 3 everywhere", l)
+  }
+  r = report(&benchStats{codes: map[codes.Code]int{}}, time.Second)
+  if r.Calls != 0 || r.Latency.Max != 0 {
+    t.Errorf("report() without calls = %+v, want nothing", r)
+  }
+}
+
+func TestPrintReport(t *testing.T) {
+  s := &benchStats{codes: map[codes.Code]int{codes.Unavailable: 1, codes.OK: 3}, sent: 4, received: 3}
+  for i := 1; i <= 4; i++ {
+    s.latencies = append(s.latencies, time.Duration(i)*time.Millisecond)
+  }
+  r := report(s, time.Second)
+  r.Method, r.Concurrency, r.Rate, r.RequestBytes = "/calculator.CalculatorService/Sum", 2, 10, 4
+
+  var b bytes.Buffer
+  if err := printReport(&b, r); err != nil {
+    t.Fatal(err)
+  }
+  want := `Method:    /calculator.CalculatorService/Sum, requests of 4 bytes
+Load:      2 calls in flight, at most 10 calls/s
+Calls:     4 in 1.00s, 4.0 calls/s, 1 errors
+Messages:  4 sent, 3 received
+Latency:   min 1.00ms, mean 2.50ms, p50 2.00ms, p90 4.00ms, p99 4.00ms, max 4.00ms
+Status codes:
+  OK                 3
+  Unavailable        1
+`
+  if b.String() != want {
+    t.Errorf("printReport() =\n%v\nwant\n%v", b.String(), want)
+  }
+}
+
+func TestFindMethod(t *testing.T) {
+  for _, name := range []string{"Sum", "calculator.CalculatorService/Sum", "/calculator.CalculatorService/Sum", "calculator.CalculatorService.Sum"} {
+    md, err := findMethod(name)
+    if err != nil || md.FullName() != "calculator.CalculatorService.Sum" {
+      t.Errorf("findMethod(%q) = %v, %v, want calculator.CalculatorService.Sum", name, md, err)
+    }
+  }
+  var usage usageError
+  if _, err := findMethod("Subtract"); !errors.As(err, &usage) {
+    t.Errorf("findMethod() of an unknown method = %v, want a usage error", err)
+  }
+}
+
+func TestPad(t *testing.T) {
+  req := &greetpb.GreetRequest{}
+  if !pad(req.ProtoReflect(), 5) || req.GetGreeting().GetFirstName() != "xxxxx" {
+    t.Errorf("pad() = %v, want the first name filled", req)
+  }
+  // Without a string or bytes field, the message is left as it was.
+  sum := &calculatorpb.SumRequest{FirstNumber: 3}
+  if pad(sum.ProtoReflect(), 5) || !proto.Equal(sum, &calculatorpb.SumRequest{FirstNumber: 3}) {
+    t.Errorf("pad() of %v = true, want false", sum)
+  }
+}
+
+// benchRun runs grpcx bench against the calculator in memory, returning its report.
+func benchRun(t *testing.T, args ...string) *benchReport {
+  t.Helper()
+  calc := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{})
+  var out bytes.Buffer
+  e := &env{
+    opts:        options{output: "json", addr: "bufnet"},
+    out:         &out,
+    dialOptions: []grpc.DialOption{calc.ContextDialer()},
+  }
+  if err := bench(e, args); err != nil {
+    t.Fatalf("bench %v = %v", strings.Join(args, " "), err)
+  }
+  r := &benchReport{}
+  if err := json.Unmarshal(out.Bytes(), r); err != nil {
+    t.Fatalf("bench %v printed %q: %v", strings.Join(args, " "), out.String(), err)
+  }
+  return r
+}
+
+func TestBench(t *testing.T) {
+  for _, tc := range []struct {
+    name     string
+    args     []string
+    calls    int
+    sent     int64
+    received int64
+  }{
+    {name: "unary", args: []string{"-n", "20", "-c", "4", "-data", `{"firstNumber": 3, "secondNumber": 4}`, "Sum"}, calls: 20, sent: 20, received: 20},
+    {name: "server stream", args: []string{"-n", "5", "-data", `{"number": 12}`, "PrimeNumberDecomposition"}, calls: 5, sent: 5, received: 15},
+    {name: "client stream", args: []string{"-n", "5", "-c", "2", "-messages", "3", "-data", `{"number": 2}`, "ComputeAverage"}, calls: 5, sent: 15, received: 5},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      r := benchRun(t, tc.args...)
+      if r.Calls != tc.calls || r.Errors != 0 || r.Codes["OK"] != tc.calls {
+        t.Fatalf("bench = %v calls, %v errors, codes %v, want %v calls OK", r.Calls, r.Errors, r.Codes, tc.calls)
+      }
+      if r.MessagesSent != tc.sent || r.MessagesReceived != tc.received {
+        t.Fatalf("bench = %v messages sent and %v received, want %v and %v", r.MessagesSent, r.MessagesReceived, tc.sent, tc.received)
+      }
+      if r.Latency.Min <= 0 || r.Latency.Min > r.Latency.P50 || r.Latency.P50 > r.Latency.Max {
+        t.Fatalf("bench latencies = %+v, want them in order", r.Latency)
+      }
+    })
+  }
+
+  // The requests are filled up to -size.
+  r := benchRun(t, "-n", "1", "-size", "100", "Greet")
+  if want := proto.Size(&greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: strings.Repeat("x", 100)}}); r.RequestBytes != want {
+    t.Fatalf("bench -size 100 sent requests of %v bytes, want %v", r.RequestBytes, want)
+  }
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+  "flag"
+  "fmt"
+  "io"
+  "strconv"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+
+  "google.golang.org/grpc/codes"
+  "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var calcCommands = map[string]action{
+  "sum":     calcSum,
+  "factor":  calcFactor,
+  "avg":     calcAverage,
+  "max":     calcMaximum,
+  "sqrt":    calcSquareRoot,
+  "history": calcHistory,
+
+  "cadd":   complexAdd,
+  "cmul":   complexMultiply,
+  "cdiv":   complexDivide,
+  "cmod":   complexModulus,
+  "carg":   complexArgument,
+  "croots": complexRoots,
+
+  "madd":      matrixAdd,
+  "mmul":      matrixMultiply,
+  "transpose": matrixTranspose,
+  "det":       matrixDeterminant,
+  "inverse":   matrixInverse,
+  "solve":     solveLinearSystem,
+
+  "open":  sessionOpen,
+  "close": sessionClose,
+  "eval":  sessionEval,
+  "repl":  sessionRepl,
+}
+
+// int32s reads the numbers of the arguments, or of stdin when there is none.
+func int32s(e *env, args []string) ([]int32, error) {
+  words, err := e.words(args)
+  if err != nil {
+    return nil, err
+  }
+  if len(words) == 0 {
+    return nil, usagef("no number given")
+  }
+  numbers := make([]int32, len(words))
+  for i, w := range words {
+    n, err := strconv.ParseInt(w, 10, 32)
+    if err != nil {
+      return nil, usagef("invalid number %q", w)
+    }
+    numbers[i] = int32(n)
+  }
+  return numbers, nil
+}
+
+func calcSum(e *env, args []string) error {
+  args, err := e.parse("calc sum", args, nil)
+  if err != nil {
+    return err
+  }
+  if len(args) != 2 {
+    return usagef("calc sum takes 2 numbers")
+  }
+  numbers, err := int32s(e, args)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).Sum(ctx, &calculatorpb.SumRequest{
+    FirstNumber:  numbers[0],
+    SecondNumber: numbers[1],
+  })
+  if err != nil {
+    return err
+  }
+  return e.print(res, strconv.Itoa(int(res.GetSumResult())))
+}
+
+func calcFactor(e *env, args []string) error {
+  args, err := e.parse("calc factor", args, nil)
+  if err != nil {
+    return err
+  }
+  if len(args) != 1 {
+    return usagef("calc factor takes 1 number")
+  }
+  number, err := strconv.ParseInt(args[0], 10, 64)
+  if err != nil {
+    return usagef("invalid number %q", args[0])
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.streamContext()
+  defer cancel()
+
+  stream, err := calculatorpb.NewCalculatorServiceClient(cc).PrimeNumberDecomposition(ctx, &calculatorpb.PrimeNumberDecompositionRequest{Number: number})
+  if err != nil {
+    return err
+  }
+  for {
+    res, err := stream.Recv()
+    if err == io.EOF {
+      return nil
+    }
+    if err != nil {
+      return err
+    }
+    if err := e.print(res, strconv.FormatInt(res.GetPrimeFactor(), 10)); err != nil {
+      return err
+    }
+  }
+}
+
+func calcAverage(e *env, args []string) error {
+  args, err := e.parse("calc avg", args, nil)
+  if err != nil {
+    return err
+  }
+  numbers, err := int32s(e, args)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  stream, err := calculatorpb.NewCalculatorServiceClient(cc).ComputeAverage(ctx)
+  if err != nil {
+    return err
+  }
+  for _, n := range numbers {
+    if err := stream.Send(&calculatorpb.ComputeAverageRequest{Number: n}); err != nil {
+      break // the error is told by CloseAndRecv.
+    }
+  }
+  res, err := stream.CloseAndRecv()
+  if err != nil {
+    return err
+  }
+  return e.print(res, strconv.FormatFloat(res.GetAverage(), 'g', -1, 64))
+}
+
+func calcMaximum(e *env, args []string) error {
+  args, err := e.parse("calc max", args, nil)
+  if err != nil {
+    return err
+  }
+  numbers, err := int32s(e, args)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  stream, err := calculatorpb.NewCalculatorServiceClient(cc).FindMaximum(ctx)
+  if err != nil {
+    return err
+  }
+  go func() {
+    for _, n := range numbers {
+      if err := stream.Send(&calculatorpb.FindMaximumRequest{Number: n}); err != nil {
+        return // the error is told by Recv.
+      }
+    }
+    stream.CloseSend()
+  }()
+  for {
+    res, err := stream.Recv()
+    if err == io.EOF {
+      return nil
+    }
+    if err != nil {
+      return err
+    }
+    if err := e.print(res, strconv.Itoa(int(res.GetMaximum()))); err != nil {
+      return err
+    }
+  }
+}
+
+func calcSquareRoot(e *env, args []string) error {
+  var allowComplex, unary bool
+  args, err := e.parse("calc sqrt", args, func(fs *flag.FlagSet) {
+    fs.BoolVar(&allowComplex, "complex", false, "answer negative numbers with a complex root")
+    fs.BoolVar(&unary, "unary", false, "call SquareRootUnary, failing on a negative number without -complex")
+  })
+  if err != nil {
+    return err
+  }
+  numbers, err := int32s(e, args)
+  if err != nil {
+    return err
+  }
+  if unary && len(numbers) != 1 {
+    return usagef("calc sqrt -unary takes 1 number")
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  if unary {
+    res, err := calculatorpb.NewCalculatorServiceClient(cc).SquareRootUnary(ctx, &calculatorpb.SquareRootRequest{Number: numbers[0], AllowComplex: allowComplex})
+    if err != nil {
+      return err
+    }
+    return e.print(res, rootText(res))
+  }
+  stream, err := calculatorpb.NewCalculatorServiceClient(cc).SquareRoot(ctx)
+  if err != nil {
+    return err
+  }
+  go func() {
+    for _, n := range numbers {
+      if err := stream.Send(&calculatorpb.SquareRootRequest{Number: n, AllowComplex: allowComplex}); err != nil {
+        return // the error is told by Recv.
+      }
+    }
+    stream.CloseSend()
+  }()
+
+  // The errors of single numbers are printed, the last one making the exit code.
+  var failed error
+  for {
+    res, err := stream.Recv()
+    if err == io.EOF {
+      return failed
+    }
+    if err != nil {
+      return err
+    }
+    if res.GetError() != nil {
+      failed = statusError{codes.Code(res.GetError().GetCode()), res.GetError().GetMessage()}
+    }
+    if err := e.print(res, rootText(res)); err != nil {
+      return err
+    }
+  }
+}
+
+// rootText returns the text of a square root, or of its error.
+func rootText(res *calculatorpb.SquareRootResponse) string {
+  switch {
+  case res.GetError() != nil:
+    return fmt.Sprintf("%v: %v", res.GetNumber(), res.GetError().GetMessage())
+  case res.GetComplexRoot() != nil:
+    return fmt.Sprintf("%v: %vi", res.GetNumber(), res.GetComplexRoot().GetImaginary())
+  default:
+    return fmt.Sprintf("%v: %v", res.GetNumber(), res.GetNumberRoot())
+  }
+}
+
+func calcHistory(e *env, args []string) error {
+  var method string
+  var since time.Duration
+  var limit int
+  var all bool
+  args, err := e.parse("calc history", args, func(fs *flag.FlagSet) {
+    fs.StringVar(&method, "method", "", "only the calls of this method")
+    fs.DurationVar(&since, "since", 0, "only the calls of this last duration")
+    fs.IntVar(&limit, "limit", 50, "most entries listed")
+    fs.BoolVar(&all, "all", false, "the calls of every client, listed on the admin listener")
+  })
+  if err != nil {
+    return err
+  }
+  if len(args) > 0 {
+    return usagef("calc history takes no argument")
+  }
+  if all {
+    e.defaultAddr = defaultAdminAddrs["calc"]
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  req := &calculatorpb.ListHistoryRequest{Method: method, PageSize: int32(limit)}
+  if since > 0 {
+    req.StartTime = timestamppb.New(time.Now().Add(-since))
+  }
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).ListHistory(ctx, req)
+  if err != nil {
+    return err
+  }
+  for _, entry := range res.GetEntries() {
+    text := fmt.Sprintf("%v %v %v %v -> %v %v", entry.GetTimestamp().AsTime().Format(time.RFC3339), entry.GetCaller(), entry.GetMethod(), entry.GetInputs(), codes.Code(entry.GetCode()), entry.GetResult())
+    if err := e.print(entry, text); err != nil {
+      return err
+    }
+  }
+  return nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+  "flag"
+  "io"
+  "regexp"
+  "strconv"
+  "strings"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+)
+
+// unitImaginary matches the imaginary unit written without its 1, as in "1+i" or "-i".
+var unitImaginary = regexp.MustCompile(`(^|[+-])i$`)
+
+// parseComplex reads a complex number given as "3+4i", "-2i", "1+i" or "5".
+func parseComplex(s string) (complex128, error) {
+  return strconv.ParseComplex(unitImaginary.ReplaceAllString(s, "${1}1i"), 128)
+}
+
+// complexes reads n complex numbers.
+func complexes(e *env, name string, args []string, n int, setup func(fs *flag.FlagSet)) ([]*calculatorpb.Complex, error) {
+  args, err := e.parse(name, args, setup)
+  if err != nil {
+    return nil, err
+  }
+  if len(args) != n {
+    return nil, usagef("%v takes %v complex numbers, such as 3+4i", name, n)
+  }
+  numbers := make([]*calculatorpb.Complex, n)
+  for i, arg := range args {
+    c, err := parseComplex(arg)
+    if err != nil {
+      return nil, usagef("invalid complex number %q", arg)
+    }
+    numbers[i] = &calculatorpb.Complex{Real: real(c), Imaginary: imag(c)}
+  }
+  return numbers, nil
+}
+
+func formatComplex(c *calculatorpb.Complex) string {
+  s := strconv.FormatComplex(complex(c.GetReal(), c.GetImaginary()), 'g', -1, 128)
+  return strings.Trim(s, "()")
+}
+
+func formatFloat(f float64) string {
+  return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func complexAdd(e *env, args []string) error {
+  numbers, err := complexes(e, "calc cadd", args, 2, nil)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).ComplexAdd(ctx, &calculatorpb.ComplexAddRequest{First: numbers[0], Second: numbers[1]})
+  if err != nil {
+    return err
+  }
+  return e.print(res, formatComplex(res.GetResult()))
+}
+
+func complexMultiply(e *env, args []string) error {
+  numbers, err := complexes(e, "calc cmul", args, 2, nil)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).ComplexMultiply(ctx, &calculatorpb.ComplexMultiplyRequest{First: numbers[0], Second: numbers[1]})
+  if err != nil {
+    return err
+  }
+  return e.print(res, formatComplex(res.GetResult()))
+}
+
+func complexDivide(e *env, args []string) error {
+  numbers, err := complexes(e, "calc cdiv", args, 2, nil)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).ComplexDivide(ctx, &calculatorpb.ComplexDivideRequest{Dividend: numbers[0], Divisor: numbers[1]})
+  if err != nil {
+    return err
+  }
+  return e.print(res, formatComplex(res.GetResult()))
+}
+
+func complexModulus(e *env, args []string) error {
+  numbers, err := complexes(e, "calc cmod", args, 1, nil)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).ComplexModulus(ctx, &calculatorpb.ComplexModulusRequest{Number: numbers[0]})
+  if err != nil {
+    return err
+  }
+  return e.print(res, formatFloat(res.GetModulus()))
+}
+
+func complexArgument(e *env, args []string) error {
+  numbers, err := complexes(e, "calc carg", args, 1, nil)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).ComplexArgument(ctx, &calculatorpb.ComplexArgumentRequest{Number: numbers[0]})
+  if err != nil {
+    return err
+  }
+  return e.print(res, formatFloat(res.GetArgument()))
+}
+
+func complexRoots(e *env, args []string) error {
+  var degree int
+  numbers, err := complexes(e, "calc croots", args, 1, func(fs *flag.FlagSet) {
+    fs.IntVar(&degree, "n", 2, "degree of the roots")
+  })
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.streamContext()
+  defer cancel()
+
+  stream, err := calculatorpb.NewCalculatorServiceClient(cc).ComplexRoots(ctx, &calculatorpb.ComplexRootsRequest{Number: numbers[0], Degree: int32(degree)})
+  if err != nil {
+    return err
+  }
+  for {
+    res, err := stream.Recv()
+    if err == io.EOF {
+      return nil
+    }
+    if err != nil {
+      return err
+    }
+    if err := e.print(res, formatComplex(res.GetRoot())); err != nil {
+      return err
+    }
+  }
+}
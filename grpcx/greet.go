@@ -0,0 +1,210 @@
+package main
+
+import (
+  "flag"
+  "io"
+  "strings"
+
+  "github.com/_dev/grpc-go-example/greet/greetpb"
+)
+
+var greetCommands = map[string]action{
+  "unary":    greetUnary,
+  "many":     greetMany,
+  "long":     greetLong,
+  "everyone": greetEveryone,
+  "deadline": greetDeadline,
+}
+
+// greetingFlags reads a single greeting from --first and --last, or from the arguments.
+func greetingFlags(e *env, name string, args []string) (*greetpb.Greeting, error) {
+  greeting := &greetpb.Greeting{}
+  args, err := e.parse(name, args, func(fs *flag.FlagSet) {
+    fs.StringVar(&greeting.FirstName, "first", "", "first name")
+    fs.StringVar(&greeting.LastName, "last", "", "last name")
+  })
+  if err != nil {
+    return nil, err
+  }
+  if len(args) > 0 {
+    greeting = toGreeting(strings.Join(args, " "))
+  }
+  if greeting.GetFirstName() == "" {
+    return nil, usagef("no name given, use --first and --last")
+  }
+  return greeting, nil
+}
+
+// greetings reads a greeting per argument, or per line of stdin when there is none.
+func greetings(e *env, name string, args []string) ([]*greetpb.Greeting, error) {
+  args, err := e.parse(name, args, nil)
+  if err != nil {
+    return nil, err
+  }
+  if len(args) == 0 {
+    b, err := io.ReadAll(e.in)
+    if err != nil {
+      return nil, err
+    }
+    args = strings.Split(string(b), "\n")
+  }
+  var greetings []*greetpb.Greeting
+  for _, arg := range args {
+    if arg = strings.TrimSpace(arg); arg != "" {
+      greetings = append(greetings, toGreeting(arg))
+    }
+  }
+  if len(greetings) == 0 {
+    return nil, usagef("no name given")
+  }
+  return greetings, nil
+}
+
+// toGreeting splits "First Last" into a greeting.
+func toGreeting(name string) *greetpb.Greeting {
+  fields := strings.SplitN(strings.TrimSpace(name), " ", 2)
+  greeting := &greetpb.Greeting{FirstName: fields[0]}
+  if len(fields) == 2 {
+    greeting.LastName = strings.TrimSpace(fields[1])
+  }
+  return greeting
+}
+
+func greetUnary(e *env, args []string) error {
+  greeting, err := greetingFlags(e, "greet unary", args)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := greetpb.NewGreetServiceClient(cc).Greet(ctx, &greetpb.GreetRequest{Greeting: greeting})
+  if err != nil {
+    return err
+  }
+  return e.print(res, res.GetResult())
+}
+
+func greetMany(e *env, args []string) error {
+  greeting, err := greetingFlags(e, "greet many", args)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.streamContext()
+  defer cancel()
+
+  stream, err := greetpb.NewGreetServiceClient(cc).GreetManyTimes(ctx, &greetpb.GreetManyTimesRequest{Greeting: greeting})
+  if err != nil {
+    return err
+  }
+  for {
+    res, err := stream.Recv()
+    if err == io.EOF {
+      return nil
+    }
+    if err != nil {
+      return err
+    }
+    if err := e.print(res, res.GetResult()); err != nil {
+      return err
+    }
+  }
+}
+
+func greetLong(e *env, args []string) error {
+  greetings, err := greetings(e, "greet long", args)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  stream, err := greetpb.NewGreetServiceClient(cc).LongGreet(ctx)
+  if err != nil {
+    return err
+  }
+  for _, greeting := range greetings {
+    if err := stream.Send(&greetpb.LongGreetRequest{Greeting: greeting}); err != nil {
+      break // the error is told by CloseAndRecv.
+    }
+  }
+  res, err := stream.CloseAndRecv()
+  if err != nil {
+    return err
+  }
+  return e.print(res, res.GetResult())
+}
+
+func greetEveryone(e *env, args []string) error {
+  greetings, err := greetings(e, "greet everyone", args)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  stream, err := greetpb.NewGreetServiceClient(cc).GreetEveryone(ctx)
+  if err != nil {
+    return err
+  }
+  go func() {
+    for _, greeting := range greetings {
+      if err := stream.Send(&greetpb.GreetEveryoneRequest{Greeting: greeting}); err != nil {
+        return // the error is told by Recv.
+      }
+    }
+    stream.CloseSend()
+  }()
+  for {
+    res, err := stream.Recv()
+    if err == io.EOF {
+      return nil
+    }
+    if err != nil {
+      return err
+    }
+    if err := e.print(res, res.GetResult()); err != nil {
+      return err
+    }
+  }
+}
+
+func greetDeadline(e *env, args []string) error {
+  greeting, err := greetingFlags(e, "greet deadline", args)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := greetpb.NewGreetServiceClient(cc).GreetWithDeadline(ctx, &greetpb.GreetWithDeadlineRequest{Greeting: greeting})
+  if err != nil {
+    return err
+  }
+  return e.print(res, res.GetResult())
+}
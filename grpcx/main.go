@@ -0,0 +1,404 @@
+// Grpcx calls the greet and calculator services from the command line, and puts them
+// under load.
+//
+// Usage:
+//
+//	grpcx [flags] greet [unary|many|long|everyone|deadline] [flags] [names...]
+//	grpcx [flags] calc sum|factor|avg|max|sqrt|history [flags] [numbers...]
+//	grpcx [flags] calc cadd|cmul|cdiv|cmod|carg|croots [flags] complex...
+//	grpcx [flags] calc madd|mmul|transpose|det|inverse|solve [flags] [matrices...]
+//	grpcx [flags] calc open|close|eval|repl [flags] [id|expressions...]
+//	grpcx [flags] admin reload greet|calc
+//	grpcx [flags] bench [flags] method
+//
+// The flags common to every command may also follow the command, before or after its
+// arguments, up to a "--". Names are given as "First" or "First Last", numbers are read
+// from stdin when none is given. Complex numbers are given as "3+4i", matrices row by
+// row as "1 2; 3 4", or on the lines of stdin for a single matrix, and expressions one
+// per argument or per line of stdin.
+//
+// The calls have a deadline of 10s unless -timeout is given, but for the streams of
+// responses, such as "greet many" or "calc repl", which have none by default.
+//
+// Bench calls any method of the services, as in "bench -c 50 -d 30s Sum", with the
+// request given as JSON by -data, and reports the throughput, the status codes and the
+// latency percentiles of the calls.
+//
+// Grpcx exits with 0 on success, 1 on local failures, 2 on usage errors, and 64 plus the
+// gRPC status code when a call fails, as in 64+5=69 for NotFound.
+package main
+
+import (
+  "bufio"
+  "context"
+  "crypto/tls"
+  "errors"
+  "flag"
+  "fmt"
+  "io"
+  "os"
+  "strings"
+  "time"
+
+  "github.com/_dev/grpc-go-example/client"
+  "github.com/_dev/grpc-go-example/compression"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/credentials"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/status"
+  "google.golang.org/protobuf/encoding/protojson"
+  "google.golang.org/protobuf/proto"
+  "google.golang.org/protobuf/protoadapt"
+)
+
+// defaultAddr is where the servers listen by default for the calls to the services.
+const defaultAddr = "localhost:50051"
+
+// defaultAdminAddrs are where the servers serve the AdminService by default, by the
+// command of their service.
+var defaultAdminAddrs = map[string]string{
+  "calc":  "localhost:50061",
+  "greet": "localhost:50062",
+}
+
+// defaultTimeout is the deadline of the calls when -timeout is not given, but for the
+// streams of responses, which may last as long as the server sends.
+const defaultTimeout = 10 * time.Second
+
+// statusExitBase is added to the gRPC status code of a failed call to make the exit code.
+const statusExitBase = 64
+
+// usageError is a command line that cannot be run.
+type usageError struct {
+  msg string
+}
+
+func (e usageError) Error() string {
+  return e.msg
+}
+
+func usagef(format string, v ...interface{}) error {
+  return usageError{fmt.Sprintf(format, v...)}
+}
+
+// statusError is a failure told inside a response, exiting as the failed call would.
+type statusError struct {
+  code codes.Code
+  msg  string
+}
+
+func (e statusError) Error() string {
+  return e.msg
+}
+
+// options are the flags common to every command.
+type options struct {
+  addr       string
+  useTLS     bool
+  caFile     string
+  serverName string
+  timeout    timeout
+  metadata   headers
+  output     string
+  compressor string
+}
+
+func (o *options) register(fs *flag.FlagSet) {
+  fs.StringVar(&o.addr, "addr", o.addr, "server address, addresses separated by commas, or a target such as dns:///host:port; "+defaultAddr+" by default, "+defaultAdminAddrs["calc"]+" and "+defaultAdminAddrs["greet"]+" for the admin of calc and greet")
+  fs.BoolVar(&o.useTLS, "tls", o.useTLS, "connect with TLS")
+  fs.StringVar(&o.caFile, "ca-file", o.caFile, "PEM file of the certificate authorities trusted with -tls, the system ones by default")
+  fs.StringVar(&o.serverName, "server-name", o.serverName, "name expected in the certificate of the server with -tls")
+  fs.Var(&o.timeout, "timeout", "deadline of the call, 0 for none; "+defaultTimeout.String()+" by default, none for the streams of responses")
+  fs.Var(&o.metadata, "H", "metadata sent with the call as \"key: value\", repeatable")
+  fs.StringVar(&o.output, "o", o.output, "output format: text or json")
+  fs.StringVar(&o.compressor, "compressor", o.compressor, "compressor of the calls: gzip, zstd or identity, none when empty")
+}
+
+// timeout is the -timeout flag, telling an unset flag apart from 0.
+type timeout struct {
+  d   time.Duration
+  set bool
+}
+
+func (t *timeout) String() string {
+  if !t.set {
+    return ""
+  }
+  return t.d.String()
+}
+
+func (t *timeout) Set(s string) error {
+  d, err := time.ParseDuration(s)
+  if err != nil {
+    return err
+  }
+  if d < 0 {
+    return fmt.Errorf("negative timeout %v", d)
+  }
+  t.d, t.set = d, true
+  return nil
+}
+
+// headers collects the -H flags.
+type headers []string
+
+func (h *headers) String() string {
+  return strings.Join(*h, ", ")
+}
+
+func (h *headers) Set(s string) error {
+  if !strings.Contains(s, ":") {
+    return fmt.Errorf("expecting \"key: value\", got %q", s)
+  }
+  *h = append(*h, s)
+  return nil
+}
+
+// env is what the commands run with.
+type env struct {
+  opts options
+  out  io.Writer
+  in   io.Reader
+
+  // defaultAddr is dialed when -addr is not given.
+  defaultAddr string
+
+  // dialOptions are added to those of the connections, as by the tests connecting in memory.
+  dialOptions []grpc.DialOption
+}
+
+// action runs a command with its arguments, following the command name.
+type action func(e *env, args []string) error
+
+var commands = map[string]map[string]action{
+  "greet": greetCommands,
+  "calc":  calcCommands,
+  "admin": adminCommands,
+  "bench": benchCommands,
+}
+
+// defaults holds the subcommand run when none is given.
+var defaults = map[string]string{
+  "greet": "unary",
+  "bench": "run",
+}
+
+func main() {
+  err := run(os.Args[1:], os.Stdin, os.Stdout)
+  if err != nil {
+    fmt.Fprintf(os.Stderr, "grpcx: %v\n", err)
+  }
+  os.Exit(exitCode(err))
+}
+
+func run(args []string, in io.Reader, out io.Writer) error {
+  e := &env{
+    opts:        options{output: "text"},
+    in:          in,
+    out:         out,
+    defaultAddr: defaultAddr,
+  }
+  fs := flag.NewFlagSet("grpcx", flag.ContinueOnError)
+  e.opts.register(fs)
+  fs.Usage = func() {
+    fmt.Fprintln(fs.Output(), "Usage: grpcx [flags] greet|calc|admin|bench [command] [flags] [args...]")
+    fs.PrintDefaults()
+  }
+  if err := fs.Parse(args); err != nil {
+    return usageError{err.Error()}
+  }
+  args = fs.Args()
+  if len(args) == 0 {
+    fs.Usage()
+    return usagef("no command given")
+  }
+
+  subcommands, ok := commands[args[0]]
+  if !ok {
+    return usagef("unknown command %q, expecting greet, calc, admin or bench", args[0])
+  }
+  name, rest := defaults[args[0]], args[1:]
+  if len(rest) > 0 && !strings.HasPrefix(rest[0], "-") {
+    if _, ok := subcommands[rest[0]]; ok || name == "" {
+      name, rest = rest[0], rest[1:]
+    }
+  }
+  act, ok := subcommands[name]
+  if !ok {
+    return usagef("unknown %v command %q, expecting one of %v", args[0], name, names(subcommands))
+  }
+  return act(e, rest)
+}
+
+func names(m map[string]action) string {
+  var names []string
+  for name := range m {
+    names = append(names, name)
+  }
+  return strings.Join(names, ", ")
+}
+
+// parse parses the flags of a command, with the common ones, returning its arguments.
+func (e *env) parse(name string, args []string, setup func(fs *flag.FlagSet)) ([]string, error) {
+  fs := flag.NewFlagSet("grpcx "+name, flag.ContinueOnError)
+  e.opts.register(fs)
+  if setup != nil {
+    setup(fs)
+  }
+  // Flags may follow the arguments, which may be negative numbers or "-".
+  var positional, rest []string
+  for i, arg := range args {
+    if arg == "--" {
+      args, rest = args[:i], args[i+1:]
+      break
+    }
+  }
+  for len(args) > 0 {
+    if args[0] == "-" || !strings.HasPrefix(args[0], "-") || startsWithNumber(args[0]) {
+      positional = append(positional, args[0])
+      args = args[1:]
+      continue
+    }
+    // The flags run up to the next negative number, which the flag package would take for
+    // a flag; a flag given a negative value is written as -flag=value.
+    end := 1
+    for end < len(args) && !(strings.HasPrefix(args[end], "-") && startsWithNumber(args[end])) {
+      end++
+    }
+    if err := fs.Parse(args[:end]); err != nil {
+      return nil, usageError{err.Error()}
+    }
+    if len(fs.Args()) == end {
+      return nil, usagef("cannot parse the flag %q", args[0])
+    }
+    args = append(append([]string(nil), fs.Args()...), args[end:]...)
+  }
+  if e.opts.output != "text" && e.opts.output != "json" {
+    return nil, usagef("unknown output format %q, expecting text or json", e.opts.output)
+  }
+  return append(positional, rest...), nil
+}
+
+// startsWithNumber tells the arguments starting with a number, as the negative numbers,
+// complex numbers and matrices do, apart from the flags.
+func startsWithNumber(s string) bool {
+  fields := strings.FieldsFunc(s, func(r rune) bool { return r == ' ' || r == ',' || r == ';' })
+  if len(fields) == 0 {
+    return false
+  }
+  _, err := parseComplex(fields[0])
+  return err == nil
+}
+
+func (e *env) dial() (*grpc.ClientConn, error) {
+  addr := e.opts.addr
+  if addr == "" {
+    addr = e.defaultAddr
+  }
+  target, opts, err := client.Target(addr)
+  if err != nil {
+    return nil, usageError{err.Error()}
+  }
+  if e.opts.compressor != "" {
+    compressors, err := compression.ParseMethods(compression.DefaultMethod + "=" + e.opts.compressor)
+    if err != nil {
+      return nil, usageError{err.Error()}
+    }
+    opts = append(opts, compression.WithCompression(compression.Policy{Methods: compressors})...)
+  }
+  creds := grpc.WithInsecure()
+  if e.opts.useTLS {
+    var tc credentials.TransportCredentials
+    if e.opts.caFile != "" {
+      tc, err = credentials.NewClientTLSFromFile(e.opts.caFile, e.opts.serverName)
+      if err != nil {
+        return nil, err
+      }
+    } else {
+      tc = credentials.NewTLS(&tls.Config{ServerName: e.opts.serverName})
+    }
+    creds = grpc.WithTransportCredentials(tc)
+  }
+  return grpc.Dial(target, append(append(opts, creds), e.dialOptions...)...)
+}
+
+// context returns the context of a call, with its deadline and metadata.
+func (e *env) context() (context.Context, context.CancelFunc) {
+  return e.contextWithin(defaultTimeout)
+}
+
+// streamContext returns the context of a stream of responses, without deadline unless
+// -timeout is given.
+func (e *env) streamContext() (context.Context, context.CancelFunc) {
+  return e.contextWithin(0)
+}
+
+func (e *env) contextWithin(d time.Duration) (context.Context, context.CancelFunc) {
+  if e.opts.timeout.set {
+    d = e.opts.timeout.d
+  }
+  ctx, cancel := context.Background(), context.CancelFunc(func() {})
+  if d > 0 {
+    ctx, cancel = context.WithTimeout(ctx, d)
+  }
+  for _, h := range e.opts.metadata {
+    kv := strings.SplitN(h, ":", 2)
+    ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(strings.TrimSpace(kv[0])), strings.TrimSpace(kv[1]))
+  }
+  return ctx, cancel
+}
+
+// print writes a response, as text or as a line of JSON.
+func (e *env) print(m interface{}, text string) error {
+  if e.opts.output == "text" {
+    _, err := fmt.Fprintln(e.out, text)
+    return err
+  }
+  var msg proto.Message
+  switch m := m.(type) {
+  case proto.Message:
+    msg = m
+  case protoadapt.MessageV1:
+    msg = protoadapt.MessageV2Of(m)
+  }
+  b, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+  if err != nil {
+    return err
+  }
+  _, err = fmt.Fprintln(e.out, string(b))
+  return err
+}
+
+// words returns args, or the words read from stdin when there are none.
+func (e *env) words(args []string) ([]string, error) {
+  if len(args) > 0 {
+    return args, nil
+  }
+  var words []string
+  scanner := bufio.NewScanner(e.in)
+  scanner.Split(bufio.ScanWords)
+  for scanner.Scan() {
+    words = append(words, scanner.Text())
+  }
+  return words, scanner.Err()
+}
+
+func exitCode(err error) int {
+  if err == nil {
+    return 0
+  }
+  var usage usageError
+  if errors.As(err, &usage) {
+    return 2
+  }
+  var failed statusError
+  if errors.As(err, &failed) {
+    return statusExitBase + int(failed.code)
+  }
+  if s, ok := status.FromError(err); ok {
+    return statusExitBase + int(s.Code())
+  }
+  return 1
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+  "errors"
+  "flag"
+  "io"
+  "net"
+  "reflect"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorservice"
+  "github.com/_dev/grpc-go-example/greet/greetservice"
+)
+
+func TestParse(t *testing.T) {
+  for _, tc := range []struct {
+    name    string
+    args    []string
+    want    []string
+    timeout time.Duration
+    complex bool
+    err     bool
+  }{
+    {name: "positional", args: []string{"3", "4"}, want: []string{"3", "4"}},
+    {name: "flags first", args: []string{"-timeout", "2s", "3", "4"}, want: []string{"3", "4"}, timeout: 2 * time.Second},
+    {name: "flags last", args: []string{"3", "4", "-timeout=2s"}, want: []string{"3", "4"}, timeout: 2 * time.Second},
+    {name: "flags between", args: []string{"3", "-complex", "4"}, want: []string{"3", "4"}, complex: true},
+    {name: "negative numbers", args: []string{"-3", "-complex", "-4", "-1-i"}, want: []string{"-3", "-4", "-1-i"}, complex: true},
+    {name: "matrix", args: []string{"-1 2; 3 4", "-timeout", "1s"}, want: []string{"-1 2; 3 4"}, timeout: time.Second},
+    {name: "double dash", args: []string{"3", "--", "-complex", "-x"}, want: []string{"3", "-complex", "-x"}},
+    {name: "dash", args: []string{"3", "-"}, want: []string{"3", "-"}},
+    {name: "dash after flag", args: []string{"-complex", "-", "4"}, want: []string{"-", "4"}, complex: true},
+    {name: "unknown flag", args: []string{"3", "-nope"}, err: true},
+    {name: "missing value", args: []string{"3", "-timeout"}, err: true},
+    {name: "negative timeout", args: []string{"-timeout=-1s"}, err: true},
+    {name: "bad output", args: []string{"-o", "xml"}, err: true},
+  } {
+    t.Run(tc.name, func(t *testing.T) {
+      e := &env{opts: options{output: "text"}}
+      var complex bool
+      got, err := e.parse("test", tc.args, func(fs *flag.FlagSet) {
+        fs.SetOutput(io.Discard)
+        fs.BoolVar(&complex, "complex", false, "")
+      })
+      if tc.err {
+        var usage usageError
+        if !errors.As(err, &usage) {
+          t.Fatalf("parse(%q) = %q, %v, want a usage error", tc.args, got, err)
+        }
+        return
+      }
+      if err != nil {
+        t.Fatalf("parse(%q) = %v", tc.args, err)
+      }
+      if !reflect.DeepEqual(got, tc.want) {
+        t.Errorf("parse(%q) = %q, want %q", tc.args, got, tc.want)
+      }
+      if e.opts.timeout.d != tc.timeout || complex != tc.complex {
+        t.Errorf("parse(%q) set -timeout %v and -complex %v, want %v and %v", tc.args, e.opts.timeout.d, complex, tc.timeout, tc.complex)
+      }
+    })
+  }
+}
+
+func TestStartsWithNumber(t *testing.T) {
+  for s, want := range map[string]bool{
+    "-3": true, "-1.5e3": true, "-1-i": true, "-i": true, "-2 1; 3 4": true,
+    "-": false, "-x": false, "-timeout": false, "-H=a: -1": false, "": false,
+  } {
+    if got := startsWithNumber(s); got != want {
+      t.Errorf("startsWithNumber(%q) = %v, want %v", s, got, want)
+    }
+  }
+}
+
+func TestDefaultAdminAddrs(t *testing.T) {
+  // The admin of each server is dialed on the port it listens on by default.
+  for command, addr := range map[string]string{
+    "calc":  calculatorservice.DefaultConfig().AdminAddr,
+    "greet": greetservice.DefaultConfig().AdminAddr,
+  } {
+    _, port, _ := net.SplitHostPort(addr)
+    if _, got, _ := net.SplitHostPort(defaultAdminAddrs[command]); got != port {
+      t.Errorf("Port of the admin of %v = %v, want %v", command, got, port)
+    }
+  }
+  if defaultAdminAddrs["calc"] == defaultAdminAddrs["greet"] {
+    t.Errorf("The admins of calc and greet share %v", defaultAdminAddrs["calc"])
+  }
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+  "bufio"
+  "flag"
+  "io"
+  "strconv"
+  "strings"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+)
+
+// parseVector reads the values of a row, separated by spaces or commas.
+func parseVector(s string) (*calculatorpb.Vector, error) {
+  v := &calculatorpb.Vector{}
+  for _, field := range strings.Fields(strings.ReplaceAll(s, ",", " ")) {
+    f, err := strconv.ParseFloat(field, 64)
+    if err != nil {
+      return nil, usagef("invalid number %q", field)
+    }
+    v.Values = append(v.Values, f)
+  }
+  return v, nil
+}
+
+// parseMatrix reads a matrix given row by row, as in "1 2; 3 4", the rows separated by
+// semicolons or lines.
+func parseMatrix(s string) (*calculatorpb.Matrix, error) {
+  m := &calculatorpb.Matrix{}
+  for _, row := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == '\n' }) {
+    if strings.TrimSpace(row) == "" {
+      continue
+    }
+    v, err := parseVector(row)
+    if err != nil {
+      return nil, err
+    }
+    m.Rows = append(m.Rows, v)
+  }
+  if len(m.Rows) == 0 {
+    return nil, usagef("no matrix given, such as \"1 2; 3 4\"")
+  }
+  return m, nil
+}
+
+// matrices reads the matrices of the arguments, or a single one from stdin when there is
+// none.
+func matrices(e *env, name string, args []string, n int) ([]*calculatorpb.Matrix, error) {
+  args, err := e.parse(name, args, nil)
+  if err != nil {
+    return nil, err
+  }
+  return matrixArgs(e, name, args, n)
+}
+
+// matrixArgs reads the matrices of the parsed arguments, as matrices does.
+func matrixArgs(e *env, name string, args []string, n int) ([]*calculatorpb.Matrix, error) {
+  if len(args) == 0 && n == 1 {
+    b, err := io.ReadAll(e.in)
+    if err != nil {
+      return nil, err
+    }
+    args = []string{string(b)}
+  }
+  if len(args) != n {
+    return nil, usagef("%v takes %v matrices, such as \"1 2; 3 4\"", name, n)
+  }
+  matrices := make([]*calculatorpb.Matrix, n)
+  for i, arg := range args {
+    var err error
+    if matrices[i], err = parseMatrix(arg); err != nil {
+      return nil, err
+    }
+  }
+  return matrices, nil
+}
+
+func formatVector(v *calculatorpb.Vector) string {
+  values := make([]string, len(v.GetValues()))
+  for i, f := range v.GetValues() {
+    values[i] = formatFloat(f)
+  }
+  return strings.Join(values, " ")
+}
+
+func formatMatrix(m *calculatorpb.Matrix) string {
+  rows := make([]string, len(m.GetRows()))
+  for i, row := range m.GetRows() {
+    rows[i] = formatVector(row)
+  }
+  return strings.Join(rows, "\n")
+}
+
+func matrixAdd(e *env, args []string) error {
+  m, err := matrices(e, "calc madd", args, 2)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).MatrixAdd(ctx, &calculatorpb.MatrixAddRequest{First: m[0], Second: m[1]})
+  if err != nil {
+    return err
+  }
+  return e.print(res, formatMatrix(res.GetResult()))
+}
+
+func matrixMultiply(e *env, args []string) error {
+  m, err := matrices(e, "calc mmul", args, 2)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).MatrixMultiply(ctx, &calculatorpb.MatrixMultiplyRequest{First: m[0], Second: m[1]})
+  if err != nil {
+    return err
+  }
+  return e.print(res, formatMatrix(res.GetResult()))
+}
+
+func matrixTranspose(e *env, args []string) error {
+  m, err := matrices(e, "calc transpose", args, 1)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).MatrixTranspose(ctx, &calculatorpb.MatrixTransposeRequest{Matrix: m[0]})
+  if err != nil {
+    return err
+  }
+  return e.print(res, formatMatrix(res.GetResult()))
+}
+
+func matrixDeterminant(e *env, args []string) error {
+  var rows bool
+  args, err := e.parse("calc det", args, func(fs *flag.FlagSet) {
+    fs.BoolVar(&rows, "rows", false, "call MatrixDeterminantRows, sending the rows read from stdin as they come")
+  })
+  if err != nil {
+    return err
+  }
+  if rows {
+    if len(args) > 0 {
+      return usagef("calc det -rows reads the rows from stdin, a row per line")
+    }
+    return determinantRows(e)
+  }
+  m, err := matrixArgs(e, "calc det", args, 1)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).MatrixDeterminant(ctx, &calculatorpb.MatrixDeterminantRequest{Matrix: m[0]})
+  if err != nil {
+    return err
+  }
+  return e.print(res, formatFloat(res.GetDeterminant()))
+}
+
+// determinantRows streams the rows of stdin, a row per line, without deadline unless
+// -timeout is given as they may take long to come.
+func determinantRows(e *env) error {
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.streamContext()
+  defer cancel()
+
+  stream, err := calculatorpb.NewCalculatorServiceClient(cc).MatrixDeterminantRows(ctx)
+  if err != nil {
+    return err
+  }
+  scanner := bufio.NewScanner(e.in)
+  for scanner.Scan() {
+    if strings.TrimSpace(scanner.Text()) == "" {
+      continue
+    }
+    row, err := parseVector(scanner.Text())
+    if err != nil {
+      return err
+    }
+    if err := stream.Send(&calculatorpb.MatrixDeterminantRowsRequest{Row: row}); err != nil {
+      break // the error is told by CloseAndRecv.
+    }
+  }
+  if err := scanner.Err(); err != nil {
+    return err
+  }
+  res, err := stream.CloseAndRecv()
+  if err != nil {
+    return err
+  }
+  return e.print(res, formatFloat(res.GetDeterminant()))
+}
+
+func matrixInverse(e *env, args []string) error {
+  m, err := matrices(e, "calc inverse", args, 1)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).MatrixInverse(ctx, &calculatorpb.MatrixInverseRequest{Matrix: m[0]})
+  if err != nil {
+    return err
+  }
+  return e.print(res, formatMatrix(res.GetResult()))
+}
+
+func solveLinearSystem(e *env, args []string) error {
+  args, err := e.parse("calc solve", args, nil)
+  if err != nil {
+    return err
+  }
+  if len(args) != 2 {
+    return usagef("calc solve takes the coefficients and the constants, such as \"2 1; 1 3\" \"3 5\"")
+  }
+  coefficients, err := parseMatrix(args[0])
+  if err != nil {
+    return err
+  }
+  constants, err := parseVector(args[1])
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).SolveLinearSystem(ctx, &calculatorpb.SolveLinearSystemRequest{Coefficients: coefficients, Constants: constants})
+  if err != nil {
+    return err
+  }
+  return e.print(res, formatVector(res.GetSolution()))
+}
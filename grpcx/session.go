@@ -0,0 +1,195 @@
+package main
+
+import (
+  "bufio"
+  "flag"
+  "fmt"
+  "io"
+  "strings"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+
+  "google.golang.org/grpc/codes"
+)
+
+// sessionOpen creates a session and prints its id, for the -session flag of eval and repl.
+func sessionOpen(e *env, args []string) error {
+  args, err := e.parse("calc open", args, nil)
+  if err != nil {
+    return err
+  }
+  if len(args) > 0 {
+    return usagef("calc open takes no argument")
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).CreateSession(ctx, &calculatorpb.CreateSessionRequest{})
+  if err != nil {
+    return err
+  }
+  return e.print(res, res.GetSessionId())
+}
+
+func sessionClose(e *env, args []string) error {
+  args, err := e.parse("calc close", args, nil)
+  if err != nil {
+    return err
+  }
+  if len(args) != 1 {
+    return usagef("calc close takes 1 session id")
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.context()
+  defer cancel()
+
+  res, err := calculatorpb.NewCalculatorServiceClient(cc).CloseSession(ctx, &calculatorpb.CloseSessionRequest{SessionId: args[0]})
+  if err != nil {
+    return err
+  }
+  return e.print(res, "closed "+args[0])
+}
+
+// expressions reads an expression per argument, or per line of stdin when there is none.
+func expressions(e *env, args []string) ([]string, error) {
+  if len(args) == 0 {
+    b, err := io.ReadAll(e.in)
+    if err != nil {
+      return nil, err
+    }
+    args = strings.Split(string(b), "\n")
+  }
+  var expressions []string
+  for _, arg := range args {
+    if arg = strings.TrimSpace(arg); arg != "" {
+      expressions = append(expressions, arg)
+    }
+  }
+  if len(expressions) == 0 {
+    return nil, usagef("no expression given")
+  }
+  return expressions, nil
+}
+
+func evaluationText(res *calculatorpb.EvaluateResponse) string {
+  return fmt.Sprintf("$%v = %v", res.GetIndex(), formatFloat(res.GetResult()))
+}
+
+// sessionEval evaluates the expressions one call each, stopping at the first failure.
+func sessionEval(e *env, args []string) error {
+  var id string
+  args, err := e.parse("calc eval", args, func(fs *flag.FlagSet) {
+    fs.StringVar(&id, "session", "", "id of the session, as printed by calc open")
+  })
+  if err != nil {
+    return err
+  }
+  if id == "" {
+    return usagef("calc eval takes -session, open one with calc open or use calc repl")
+  }
+  exprs, err := expressions(e, args)
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  c := calculatorpb.NewCalculatorServiceClient(cc)
+
+  for _, expr := range exprs {
+    ctx, cancel := e.context()
+    res, err := c.Evaluate(ctx, &calculatorpb.EvaluateRequest{SessionId: id, Expression: expr})
+    cancel()
+    if err != nil {
+      return err
+    }
+    if err := e.print(res, evaluationText(res)); err != nil {
+      return err
+    }
+  }
+  return nil
+}
+
+// sessionRepl sends the expressions of the arguments, or the lines of stdin as they are
+// typed, on a Session stream, in a new session unless -session is given.
+func sessionRepl(e *env, args []string) error {
+  var id string
+  args, err := e.parse("calc repl", args, func(fs *flag.FlagSet) {
+    fs.StringVar(&id, "session", "", "id of the session, a new one when empty")
+  })
+  if err != nil {
+    return err
+  }
+  cc, err := e.dial()
+  if err != nil {
+    return err
+  }
+  defer cc.Close()
+  ctx, cancel := e.streamContext()
+  defer cancel()
+
+  stream, err := calculatorpb.NewCalculatorServiceClient(cc).Session(ctx)
+  if err != nil {
+    return err
+  }
+  go func() {
+    send := func(expr string) error {
+      if expr = strings.TrimSpace(expr); expr == "" {
+        return nil
+      }
+      return stream.Send(&calculatorpb.SessionRequest{SessionId: id, Expression: expr})
+    }
+    if len(args) > 0 {
+      for _, arg := range args {
+        if err := send(arg); err != nil {
+          return // the error is told by Recv.
+        }
+      }
+    } else {
+      scanner := bufio.NewScanner(e.in)
+      for scanner.Scan() {
+        if err := send(scanner.Text()); err != nil {
+          return
+        }
+      }
+    }
+    stream.CloseSend()
+  }()
+
+  // The errors of single expressions are printed, the last one making the exit code.
+  var failed error
+  session := ""
+  for {
+    res, err := stream.Recv()
+    if err == io.EOF {
+      return failed
+    }
+    if err != nil {
+      return err
+    }
+    if session == "" && e.opts.output == "text" {
+      fmt.Fprintf(e.out, "# session %v\n", res.GetSessionId())
+    }
+    session = res.GetSessionId()
+
+    text := evaluationText(res.GetEvaluation())
+    if sessionErr := res.GetError(); sessionErr != nil {
+      text = fmt.Sprintf("%v: %v", res.GetExpression(), sessionErr.GetMessage())
+      failed = statusError{codes.Code(sessionErr.GetCode()), sessionErr.GetMessage()}
+    }
+    if err := e.print(res, text); err != nil {
+      return err
+    }
+  }
+}
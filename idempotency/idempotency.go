@@ -0,0 +1,170 @@
+// Package idempotency replays the stored response of a unary call when the same
+// idempotency key is sent again, so that retried calls are only executed once.
+package idempotency
+
+import (
+  "context"
+  "crypto/sha256"
+  "strings"
+  "sync"
+  "time"
+
+  "github.com/_dev/grpc-go-example/cache"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/status"
+  "google.golang.org/protobuf/proto"
+  "google.golang.org/protobuf/protoadapt"
+)
+
+const (
+  // Header is the metadata key carrying the idempotency key of a call.
+  Header = "idempotency-key"
+
+  // ReplayedHeader is set in the response header of a replayed call.
+  ReplayedHeader = "idempotency-replayed"
+)
+
+// Store keeps the outcome of the calls made with an idempotency key for a window of time.
+// The keys are scoped by caller and method: a client cannot replay the responses of
+// another one, whatever the keys it sends.
+type Store struct {
+  methods map[string]bool
+  caller  func(ctx context.Context) string
+
+  mu       sync.Mutex
+  results  *cache.Cache        // completed calls, by scoped key.
+  inflight map[string]*pending // calls still running, by scoped key.
+}
+
+type record struct {
+  digest [sha256.Size]byte
+  res    interface{}
+  err    error
+}
+
+type pending struct {
+  record
+  done chan struct{}
+}
+
+// NewStore creates a store keeping at most maxEntries outcomes for window each,
+// for the given full method names only. The caller function identifies the client of a
+// call, such as ratelimit.DefaultKey; it should not trust what the client sends.
+func NewStore(window time.Duration, maxEntries int, caller func(ctx context.Context) string, methods ...string) *Store {
+  s := &Store{
+    methods:  make(map[string]bool),
+    caller:   caller,
+    results:  cache.New(maxEntries, window, nil),
+    inflight: make(map[string]*pending),
+  }
+  for _, method := range methods {
+    s.methods[method] = true
+  }
+  return s
+}
+
+// UnaryInterceptor runs the first call of each idempotency key and replays its outcome to
+// the repeats of the same caller and method. A repeat arriving while the first call still
+// runs waits for it. Reusing a key for another request fails with FailedPrecondition.
+func (s *Store) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  key := idempotencyKey(ctx)
+  msg, ok := messageV2(req)
+  if !s.methods[info.FullMethod] || key == "" || !ok {
+    return handler(ctx, req)
+  }
+  scoped := s.scope(ctx, info.FullMethod, key)
+  b, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+  if err != nil {
+    return handler(ctx, req)
+  }
+  digest := sha256.Sum256(b)
+
+  var p *pending
+  for p == nil {
+    s.mu.Lock()
+    if v, ok := s.results.Get(scoped); ok {
+      s.mu.Unlock()
+      rec := v.(*record)
+      if rec.digest != digest {
+        return nil, reused(key)
+      }
+      grpc.SetHeader(ctx, metadata.Pairs(ReplayedHeader, "true"))
+      return rec.res, rec.err
+    }
+    if running, ok := s.inflight[scoped]; ok {
+      s.mu.Unlock()
+      if running.digest != digest {
+        return nil, reused(key)
+      }
+      select {
+      case <-running.done:
+      case <-ctx.Done():
+        return nil, status.FromContextError(ctx.Err()).Err()
+      }
+      continue
+    }
+    p = &pending{
+      record: record{digest: digest},
+      done:   make(chan struct{}),
+    }
+    s.inflight[scoped] = p
+    s.mu.Unlock()
+  }
+
+  res, err := handler(ctx, req)
+
+  s.mu.Lock()
+  delete(s.inflight, scoped)
+  if !retryable(err) {
+    s.results.Add(scoped, &record{digest: digest, res: res, err: err})
+  }
+  s.mu.Unlock()
+  close(p.done)
+  return res, err
+}
+
+// messageV2 also accepts the messages generated by the older protoc-gen-go.
+func messageV2(req interface{}) (proto.Message, bool) {
+  switch m := req.(type) {
+  case proto.Message:
+    return m, true
+  case protoadapt.MessageV1:
+    return protoadapt.MessageV2Of(m), true
+  }
+  return nil, false
+}
+
+// scope returns the key under which the outcome of a call is stored, the parts separated
+// by NUL bytes, which neither the method names nor the metadata values hold.
+func (s *Store) scope(ctx context.Context, method, key string) string {
+  caller := ""
+  if s.caller != nil {
+    caller = s.caller(ctx)
+  }
+  return strings.Join([]string{caller, method, key}, "\x00")
+}
+
+func idempotencyKey(ctx context.Context) string {
+  md, _ := metadata.FromIncomingContext(ctx)
+  if values := md.Get(Header); len(values) > 0 {
+    return values[0]
+  }
+  return ""
+}
+
+func reused(key string) error {
+  return status.Errorf(codes.FailedPrecondition, "Idempotency key %v was already used for another request!", key)
+}
+
+// retryable reports whether err may go away on a retry, in which case the
+// outcome is not stored and the retry runs the call again.
+func retryable(err error) bool {
+  switch status.Code(err) {
+  case codes.Canceled, codes.DeadlineExceeded, codes.Unavailable, codes.ResourceExhausted, codes.Aborted, codes.Internal, codes.Unknown:
+    return true
+  }
+  return false
+}
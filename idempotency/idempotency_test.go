@@ -0,0 +1,96 @@
+package idempotency
+
+import (
+  "context"
+  "net"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/greet/greetpb"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/peer"
+  "google.golang.org/grpc/status"
+)
+
+const (
+  greet    = "/greet.GreetService/Greet"
+  deadline = "/greet.GreetService/GreetWithDeadline"
+)
+
+// from returns the context of a call from addr with the idempotency key.
+func from(addr, key string) context.Context {
+  ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 1234}})
+  return metadata.NewIncomingContext(ctx, metadata.Pairs(Header, key))
+}
+
+func byAddr(ctx context.Context) string {
+  p, _ := peer.FromContext(ctx)
+  return p.Addr.String()
+}
+
+// counter answers each call with the number of calls it got.
+type counter struct {
+  calls int
+}
+
+func (c *counter) handler(ctx context.Context, req interface{}) (interface{}, error) {
+  c.calls++
+  return &greetpb.GreetResponse{Result: req.(*greetpb.GreetRequest).GetGreeting().GetFirstName()}, nil
+}
+
+func call(s *Store, c *counter, ctx context.Context, method, name string) (string, error) {
+  req := &greetpb.GreetRequest{Greeting: &greetpb.Greeting{FirstName: name}}
+  res, err := s.UnaryInterceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, c.handler)
+  if err != nil {
+    return "", err
+  }
+  return res.(*greetpb.GreetResponse).GetResult(), nil
+}
+
+func TestReplay(t *testing.T) {
+  s := NewStore(time.Minute, 10, byAddr, greet, deadline)
+  c := &counter{}
+  ctx := from("10.0.0.1", "key")
+
+  for i := 0; i < 2; i++ {
+    if got, err := call(s, c, ctx, greet, "Felipe"); err != nil || got != "Felipe" {
+      t.Fatalf("Greet() %v = %q, %v, want Felipe", i, got, err)
+    }
+  }
+  if c.calls != 1 {
+    t.Fatalf("Handler got %v calls, want 1", c.calls)
+  }
+  if _, err := call(s, c, ctx, greet, "Stephane"); status.Code(err) != codes.FailedPrecondition {
+    t.Fatalf("Greet() of another request with the key = %v, want FailedPrecondition", err)
+  }
+}
+
+func TestScope(t *testing.T) {
+  s := NewStore(time.Minute, 10, byAddr, greet, deadline)
+  c := &counter{}
+  if _, err := call(s, c, from("10.0.0.1", "key"), greet, "Felipe"); err != nil {
+    t.Fatal(err)
+  }
+
+  // Another client sending the same key, or the same client calling another method with
+  // it, runs its own call rather than getting the first response.
+  for _, tc := range []struct {
+    name   string
+    ctx    context.Context
+    method string
+  }{
+    {name: "caller", ctx: from("10.0.0.2", "key"), method: greet},
+    {name: "method", ctx: from("10.0.0.1", "key"), method: deadline},
+  } {
+    calls := c.calls
+    if got, err := call(s, c, tc.ctx, tc.method, "Stephane"); err != nil || got != "Stephane" {
+      t.Errorf("%v: call = %q, %v, want Stephane", tc.name, got, err)
+    }
+    if c.calls != calls+1 {
+      t.Errorf("%v: handler was not called", tc.name)
+    }
+  }
+}
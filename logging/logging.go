@@ -0,0 +1,64 @@
+// Package logging gates the logs of the servers by a level, which may change while they run.
+package logging
+
+import (
+  "fmt"
+  "log"
+  "strings"
+  "sync/atomic"
+)
+
+// Level is the least severity logged.
+type Level int32
+
+const (
+  // Debug logs everything, down to the details of each message.
+  Debug Level = iota
+  // Info logs each call.
+  Info
+  // Error logs the failures only.
+  Error
+)
+
+var levelNames = map[string]Level{
+  "debug": Debug,
+  "info":  Info,
+  "error": Error,
+}
+
+var level = int32(Info)
+
+// ParseLevel reads a level from its name: debug, info or error.
+func ParseLevel(name string) (Level, error) {
+  l, ok := levelNames[strings.ToLower(name)]
+  if !ok {
+    return 0, fmt.Errorf("unknown log level %q, expecting debug, info or error", name)
+  }
+  return l, nil
+}
+
+// SetLevel changes the least severity logged.
+func SetLevel(l Level) {
+  atomic.StoreInt32(&level, int32(l))
+}
+
+// Debugf logs the details.
+func Debugf(format string, v ...interface{}) {
+  logf(Debug, format, v...)
+}
+
+// Infof logs the calls.
+func Infof(format string, v ...interface{}) {
+  logf(Info, format, v...)
+}
+
+// Errorf logs the failures.
+func Errorf(format string, v ...interface{}) {
+  logf(Error, format, v...)
+}
+
+func logf(l Level, format string, v ...interface{}) {
+  if int32(l) >= atomic.LoadInt32(&level) {
+    log.Output(3, fmt.Sprintf(format, v...))
+  }
+}
@@ -0,0 +1,87 @@
+package ratelimit_test
+
+import (
+  "context"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/calculator/calculatorpb"
+  "github.com/_dev/grpc-go-example/clock"
+  "github.com/_dev/grpc-go-example/grpctest"
+  "github.com/_dev/grpc-go-example/ratelimit"
+
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/status"
+)
+
+func newLimitedCalculator(t *testing.T, rules map[string]ratelimit.Rule) (calculatorpb.CalculatorServiceClient, *clock.Fake) {
+  clk := clock.NewFake(time.Now())
+  limiter := ratelimit.NewLimiter(ratelimit.DefaultKey, rules, clk)
+  t.Cleanup(limiter.Close)
+  calc := grpctest.NewCalculator(t, grpctest.CalculatorConfig(), grpctest.Options{
+    UnaryInterceptors:  []grpc.UnaryServerInterceptor{limiter.UnaryInterceptor},
+    StreamInterceptors: []grpc.StreamServerInterceptor{limiter.StreamInterceptor},
+  })
+  return calc.Client, clk
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+  c, clk := newLimitedCalculator(t, map[string]ratelimit.Rule{
+    "/calculator.CalculatorService/Sum": {Rate: 0.5, Burst: 1},
+  })
+  ctx := context.Background()
+
+  if _, err := c.Sum(ctx, &calculatorpb.SumRequest{}); err != nil {
+    t.Fatalf("Sum() within the limit = %v", err)
+  }
+  // The limited call tells when to retry, in the trailers honored by the retries.
+  var trailer metadata.MD
+  _, err := c.Sum(ctx, &calculatorpb.SumRequest{}, grpc.Trailer(&trailer))
+  if status.Code(err) != codes.ResourceExhausted {
+    t.Fatalf("Sum() over the limit = %v, want ResourceExhausted", err)
+  }
+  for key, want := range map[string]string{ratelimit.RetryAfterHeader: "2", ratelimit.PushbackHeader: "2000"} {
+    if got := trailer.Get(key); len(got) != 1 || got[0] != want {
+      t.Errorf("Trailer %v = %v, want %v", key, got, want)
+    }
+  }
+  if _, err := c.SquareRootUnary(ctx, &calculatorpb.SquareRootRequest{Number: 4}); err != nil {
+    t.Fatalf("SquareRootUnary() without a rule = %v", err)
+  }
+
+  clk.Advance(2 * time.Second)
+  if _, err := c.Sum(ctx, &calculatorpb.SumRequest{}); err != nil {
+    t.Fatalf("Sum() once the token refilled = %v", err)
+  }
+}
+
+func TestStreamInterceptor(t *testing.T) {
+  c, clk := newLimitedCalculator(t, map[string]ratelimit.Rule{
+    ratelimit.DefaultMethod: {Rate: 1, Burst: 1},
+  })
+
+  findMaximum := func() error {
+    stream, err := c.FindMaximum(context.Background())
+    if err != nil {
+      return err
+    }
+    if err := stream.Send(&calculatorpb.FindMaximumRequest{Number: 1}); err != nil {
+      _, err = stream.Recv()
+      return err
+    }
+    _, err = stream.Recv()
+    return err
+  }
+  if err := findMaximum(); err != nil {
+    t.Fatalf("FindMaximum() within the limit = %v", err)
+  }
+  if err := findMaximum(); status.Code(err) != codes.ResourceExhausted {
+    t.Fatalf("FindMaximum() over the limit = %v, want ResourceExhausted", err)
+  }
+  clk.Advance(time.Second)
+  if err := findMaximum(); err != nil {
+    t.Fatalf("FindMaximum() once the token refilled = %v", err)
+  }
+}
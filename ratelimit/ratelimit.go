@@ -0,0 +1,294 @@
+// Package ratelimit limits the calls of each client with token buckets configured per method.
+package ratelimit
+
+import (
+  "container/list"
+  "context"
+  "fmt"
+  "math"
+  "net"
+  "strconv"
+  "strings"
+  "sync"
+  "time"
+
+  "github.com/_dev/grpc-go-example/clock"
+
+  "golang.org/x/time/rate"
+  "google.golang.org/grpc"
+  "google.golang.org/grpc/codes"
+  "google.golang.org/grpc/credentials"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/peer"
+  "google.golang.org/grpc/status"
+)
+
+const (
+  // APIKeyHeader is the metadata key carrying the API key of a client.
+  APIKeyHeader = "x-api-key"
+
+  // RetryAfterHeader is the trailer telling, in seconds, when a limited call may be retried.
+  RetryAfterHeader = "retry-after"
+
+  // PushbackHeader is the trailer honored by the gRPC client retries, in milliseconds.
+  PushbackHeader = "grpc-retry-pushback-ms"
+
+  // DefaultMethod is the rule name applying to the methods without a rule of their own.
+  DefaultMethod = "*"
+
+  // idleTimeout is how long the bucket of a client is kept without calls.
+  idleTimeout = 10 * time.Minute
+)
+
+// maxBuckets bounds the buckets kept, the least recently used one is dropped to make room
+// for a new client.
+var maxBuckets = 100000
+
+// Rule allows Rate calls per second on average, with bursts of up to Burst calls.
+type Rule struct {
+  Rate  float64
+  Burst int
+}
+
+// KeyFunc identifies the client of a call.
+type KeyFunc func(ctx context.Context) string
+
+// ByPeerIP identifies clients by their IP address.
+func ByPeerIP(ctx context.Context) string {
+  p, ok := peer.FromContext(ctx)
+  if !ok {
+    return ""
+  }
+  host, _, err := net.SplitHostPort(p.Addr.String())
+  if err != nil {
+    return p.Addr.String()
+  }
+  return host
+}
+
+// ByAPIKey identifies clients by the API key they send. The key is not checked, a client
+// changing it at will escapes its limits: use it only behind a proxy authenticating the
+// keys.
+func ByAPIKey(ctx context.Context) string {
+  md, _ := metadata.FromIncomingContext(ctx)
+  if values := md.Get(APIKeyHeader); len(values) > 0 {
+    return values[0]
+  }
+  return ""
+}
+
+// ByPrincipal identifies clients by the common name of their TLS certificate.
+func ByPrincipal(ctx context.Context) string {
+  p, ok := peer.FromContext(ctx)
+  if !ok {
+    return ""
+  }
+  info, ok := p.AuthInfo.(credentials.TLSInfo)
+  if !ok || len(info.State.PeerCertificates) == 0 {
+    return ""
+  }
+  return info.State.PeerCertificates[0].Subject.CommonName
+}
+
+// KeyFuncs names the ways of identifying clients.
+var KeyFuncs = map[string]KeyFunc{
+  "default":   DefaultKey,
+  "ip":        ByPeerIP,
+  "api-key":   ByAPIKey,
+  "principal": ByPrincipal,
+}
+
+// DefaultKey identifies clients by principal when they authenticated with a certificate,
+// else by IP address. The API keys sent by the clients are not trusted.
+func DefaultKey(ctx context.Context) string {
+  if principal := ByPrincipal(ctx); principal != "" {
+    return "principal:" + principal
+  }
+  return "ip:" + ByPeerIP(ctx)
+}
+
+// ParseRules reads rules written as "method=rate:burst", separated by commas, as in
+// "/greet.GreetService/GreetManyTimes=0.5:2,*=20:40".
+func ParseRules(s string) (map[string]Rule, error) {
+  rules := make(map[string]Rule)
+  for _, part := range strings.Split(s, ",") {
+    part = strings.TrimSpace(part)
+    if part == "" {
+      continue
+    }
+    method, limit := part, ""
+    if i := strings.LastIndex(part, "="); i >= 0 {
+      method, limit = part[:i], part[i+1:]
+    }
+    fields := strings.Split(limit, ":")
+    if method == "" || len(fields) != 2 {
+      return nil, fmt.Errorf("invalid rate limit rule %q, expected method=rate:burst", part)
+    }
+    r, err := strconv.ParseFloat(fields[0], 64)
+    if err != nil || r <= 0 {
+      return nil, fmt.Errorf("invalid rate in rule %q", part)
+    }
+    burst, err := strconv.Atoi(fields[1])
+    if err != nil || burst < 1 {
+      return nil, fmt.Errorf("invalid burst in rule %q", part)
+    }
+    rules[method] = Rule{Rate: r, Burst: burst}
+  }
+  return rules, nil
+}
+
+// Limiter keeps a token bucket per client and method.
+type Limiter struct {
+  mu      sync.Mutex
+  key     KeyFunc
+  rules   map[string]Rule
+  buckets map[string]*list.Element // of *bucket, by method and client.
+  order   *list.List               // front is the most recently used.
+  clock   clock.Clock
+  done    chan struct{} // closed to stop the janitor.
+}
+
+type bucket struct {
+  id       string
+  method   string
+  limiter  *rate.Limiter
+  lastUsed time.Time
+}
+
+// NewLimiter creates a limiter applying rules by full method name, the DefaultMethod rule
+// applies to the other methods, which are not limited when there is none. The buckets
+// refill on clk, the real clock when nil. Close stops it.
+func NewLimiter(key KeyFunc, rules map[string]Rule, clk clock.Clock) *Limiter {
+  l := &Limiter{
+    key:     key,
+    rules:   rules,
+    buckets: make(map[string]*list.Element),
+    order:   list.New(),
+    clock:   clock.Or(clk),
+    done:    make(chan struct{}),
+  }
+  go l.janitor()
+  return l
+}
+
+// Close stops dropping the idle buckets.
+func (l *Limiter) Close() {
+  close(l.done)
+}
+
+// SetRules replaces the rules of the limiter. The buckets of the methods whose rule changed
+// are dropped, the others keep the tokens taken so far.
+func (l *Limiter) SetRules(rules map[string]Rule) {
+  l.mu.Lock()
+  defer l.mu.Unlock()
+  old := l.rules
+  l.rules = rules
+  for elem := l.order.Front(); elem != nil; {
+    next := elem.Next()
+    method := elem.Value.(*bucket).method
+    before, _ := ruleOf(old, method)
+    if after, ok := ruleOf(rules, method); !ok || after != before {
+      l.remove(elem)
+    }
+    elem = next
+  }
+}
+
+// Allow takes a token for a call of method, when there is none it returns how long to wait.
+func (l *Limiter) Allow(ctx context.Context, method string) (bool, time.Duration) {
+  l.mu.Lock()
+  rule, ok := ruleOf(l.rules, method)
+  if !ok {
+    l.mu.Unlock()
+    return true, 0
+  }
+
+  id := method + "|" + l.key(ctx)
+  now := l.clock.Now()
+  var b *bucket
+  if elem, ok := l.buckets[id]; ok {
+    b = elem.Value.(*bucket)
+    l.order.MoveToFront(elem)
+  } else {
+    b = &bucket{id: id, method: method, limiter: rate.NewLimiter(rate.Limit(rule.Rate), rule.Burst)}
+    l.buckets[id] = l.order.PushFront(b)
+    for l.order.Len() > maxBuckets {
+      l.remove(l.order.Back())
+    }
+  }
+  b.lastUsed = now
+  l.mu.Unlock()
+
+  reservation := b.limiter.ReserveN(now, 1)
+  if !reservation.OK() {
+    return false, idleTimeout
+  }
+  delay := reservation.DelayFrom(now)
+  if delay == 0 {
+    return true, 0
+  }
+  reservation.CancelAt(now)
+  return false, delay
+}
+
+// UnaryInterceptor rejects the calls over the limit with ResourceExhausted.
+func (l *Limiter) UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+  if ok, wait := l.Allow(ctx, info.FullMethod); !ok {
+    grpc.SetTrailer(ctx, retryAfter(wait))
+    return nil, exhausted(info.FullMethod, wait)
+  }
+  return handler(ctx, req)
+}
+
+// StreamInterceptor rejects the streams opened over the limit with ResourceExhausted.
+func (l *Limiter) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+  if ok, wait := l.Allow(ss.Context(), info.FullMethod); !ok {
+    ss.SetTrailer(retryAfter(wait))
+    return exhausted(info.FullMethod, wait)
+  }
+  return handler(srv, ss)
+}
+
+// janitor drops the buckets left idle, the least recently used being at the back.
+func (l *Limiter) janitor() {
+  ticker := l.clock.NewTicker(idleTimeout)
+  defer ticker.Stop()
+  for {
+    select {
+    case <-ticker.C():
+    case <-l.done:
+      return
+    }
+    l.mu.Lock()
+    for elem := l.order.Back(); elem != nil && l.clock.Since(elem.Value.(*bucket).lastUsed) > idleTimeout; elem = l.order.Back() {
+      l.remove(elem)
+    }
+    l.mu.Unlock()
+  }
+}
+
+// ruleOf returns the rule of method, and whether it is limited.
+func ruleOf(rules map[string]Rule, method string) (Rule, bool) {
+  rule, ok := rules[method]
+  if !ok {
+    rule, ok = rules[DefaultMethod]
+  }
+  return rule, ok
+}
+
+// remove drops the bucket of elem. l.mu must be held.
+func (l *Limiter) remove(elem *list.Element) {
+  l.order.Remove(elem)
+  delete(l.buckets, elem.Value.(*bucket).id)
+}
+
+func retryAfter(wait time.Duration) metadata.MD {
+  return metadata.Pairs(
+    RetryAfterHeader, strconv.Itoa(int(math.Ceil(wait.Seconds()))),
+    PushbackHeader, strconv.FormatInt(int64(math.Ceil(float64(wait)/float64(time.Millisecond))), 10),
+  )
+}
+
+func exhausted(method string, wait time.Duration) error {
+  return status.Errorf(codes.ResourceExhausted, "Rate limit exceeded for %v, retry in %v!", method, wait.Round(time.Millisecond))
+}
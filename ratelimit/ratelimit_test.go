@@ -0,0 +1,191 @@
+package ratelimit
+
+import (
+  "context"
+  "crypto/tls"
+  "crypto/x509"
+  "crypto/x509/pkix"
+  "net"
+  "testing"
+  "time"
+
+  "github.com/_dev/grpc-go-example/clock"
+
+  "google.golang.org/grpc/credentials"
+  "google.golang.org/grpc/metadata"
+  "google.golang.org/grpc/peer"
+)
+
+const sum = "/calculator.CalculatorService/Sum"
+
+// from returns the context of a call from addr, with the API key and the certificate of
+// the given common name when not empty.
+func from(addr, apiKey, commonName string) context.Context {
+  p := &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 1234}}
+  if commonName != "" {
+    p.AuthInfo = credentials.TLSInfo{State: tls.ConnectionState{
+      PeerCertificates: []*x509.Certificate{{Subject: pkix.Name{CommonName: commonName}}},
+    }}
+  }
+  ctx := peer.NewContext(context.Background(), p)
+  if apiKey != "" {
+    ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(APIKeyHeader, apiKey))
+  }
+  return ctx
+}
+
+func newTestLimiter(t *testing.T, key KeyFunc, rules map[string]Rule) (*Limiter, *clock.Fake) {
+  clk := clock.NewFake(time.Now())
+  l := NewLimiter(key, rules, clk)
+  t.Cleanup(l.Close)
+  return l, clk
+}
+
+func (l *Limiter) len() int {
+  l.mu.Lock()
+  defer l.mu.Unlock()
+  return l.order.Len()
+}
+
+func TestDefaultKey(t *testing.T) {
+  for _, tc := range []struct {
+    ctx  context.Context
+    want string
+  }{
+    {ctx: from("10.0.0.1", "", ""), want: "ip:10.0.0.1"},
+    {ctx: from("10.0.0.1", "secret", ""), want: "ip:10.0.0.1"},
+    {ctx: from("10.0.0.1", "secret", "alice"), want: "principal:alice"},
+  } {
+    if got := DefaultKey(tc.ctx); got != tc.want {
+      t.Errorf("DefaultKey() = %q, want %q", got, tc.want)
+    }
+  }
+}
+
+func TestAllow(t *testing.T) {
+  l, clk := newTestLimiter(t, DefaultKey, map[string]Rule{sum: {Rate: 1, Burst: 2}})
+  ctx := from("10.0.0.1", "", "")
+
+  for i := 0; i < 2; i++ {
+    if ok, _ := l.Allow(ctx, sum); !ok {
+      t.Fatalf("Allow() %v within the burst = false", i)
+    }
+  }
+  if ok, wait := l.Allow(ctx, sum); ok || wait != time.Second {
+    t.Fatalf("Allow() over the burst = %v, %v, want false, 1s", ok, wait)
+  }
+  // Another client has a bucket of its own, the other methods are not limited.
+  if ok, _ := l.Allow(from("10.0.0.2", "", ""), sum); !ok {
+    t.Fatal("Allow() of another client = false")
+  }
+  if ok, _ := l.Allow(ctx, "/calculator.CalculatorService/FindMaximum"); !ok {
+    t.Fatal("Allow() of a method without a rule = false")
+  }
+
+  clk.Advance(time.Second)
+  if ok, _ := l.Allow(ctx, sum); !ok {
+    t.Fatal("Allow() once a token refilled = false")
+  }
+}
+
+func TestAPIKeyNotTrusted(t *testing.T) {
+  l, _ := newTestLimiter(t, DefaultKey, map[string]Rule{DefaultMethod: {Rate: 1, Burst: 1}})
+
+  // A client sending a new key on each call still drains its own bucket.
+  if ok, _ := l.Allow(from("10.0.0.1", "first", ""), sum); !ok {
+    t.Fatal("Allow() of the first call = false")
+  }
+  if ok, _ := l.Allow(from("10.0.0.1", "second", ""), sum); ok {
+    t.Fatal("Allow() with another API key = true, want the limit of the address")
+  }
+}
+
+func TestMaxBuckets(t *testing.T) {
+  defer func(n int) { maxBuckets = n }(maxBuckets)
+  maxBuckets = 2
+  l, _ := newTestLimiter(t, ByPeerIP, map[string]Rule{sum: {Rate: 1, Burst: 1}})
+
+  for _, addr := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.1", "10.0.0.3"} {
+    l.Allow(from(addr, "", ""), sum)
+  }
+  if got := l.len(); got != 2 {
+    t.Fatalf("Kept %v buckets, want 2", got)
+  }
+  // The least recently used client lost its bucket, the others kept theirs.
+  if ok, _ := l.Allow(from("10.0.0.2", "", ""), sum); !ok {
+    t.Fatal("Allow() of the evicted client = false, want a new bucket")
+  }
+  if ok, _ := l.Allow(from("10.0.0.3", "", ""), sum); ok {
+    t.Fatal("Allow() of a recent client = true, want its drained bucket")
+  }
+}
+
+func TestJanitor(t *testing.T) {
+  clk := clock.NewFake(time.Now())
+  // A token every 1000s, the buckets stay drained throughout.
+  l := NewLimiter(ByPeerIP, map[string]Rule{sum: {Rate: 0.001, Burst: 1}}, clk)
+  clk.BlockUntil(1) // the ticker of the janitor.
+  l.Allow(from("10.0.0.1", "", ""), sum)
+  clk.Advance(idleTimeout - time.Minute)
+  l.Allow(from("10.0.0.2", "", ""), sum)
+
+  // On the first tick, only the first bucket has been idle for longer than the timeout.
+  clk.Advance(time.Minute + time.Second)
+  deadline := time.Now().Add(5 * time.Second)
+  for l.len() != 1 {
+    if time.Now().After(deadline) {
+      t.Fatalf("Kept %v buckets, want 1", l.len())
+    }
+    time.Sleep(time.Millisecond)
+  }
+  if ok, _ := l.Allow(from("10.0.0.2", "", ""), sum); ok {
+    t.Fatal("Allow() of the client still active = true, want its drained bucket")
+  }
+
+  // Closing the limiter stops the janitor.
+  l.Close()
+  for clk.Waiters() != 0 {
+    if time.Now().After(deadline) {
+      t.Fatal("The janitor still waits on the clock after Close")
+    }
+    time.Sleep(time.Millisecond)
+  }
+}
+
+func TestSetRules(t *testing.T) {
+  const findMaximum = "/calculator.CalculatorService/FindMaximum"
+  l, _ := newTestLimiter(t, ByPeerIP, map[string]Rule{sum: {Rate: 1, Burst: 1}, DefaultMethod: {Rate: 1, Burst: 1}})
+  ctx := from("10.0.0.1", "", "")
+  l.Allow(ctx, sum)
+  l.Allow(ctx, findMaximum)
+
+  // Sum keeps its rule and its drained bucket, the other methods get a fresh one.
+  l.SetRules(map[string]Rule{sum: {Rate: 1, Burst: 1}, DefaultMethod: {Rate: 1, Burst: 2}})
+  if ok, _ := l.Allow(ctx, sum); ok {
+    t.Fatal("Allow() of a method whose rule did not change = true, want its drained bucket")
+  }
+  if ok, _ := l.Allow(ctx, findMaximum); !ok {
+    t.Fatal("Allow() of a method whose rule changed = false, want a new bucket")
+  }
+
+  // Sum falls under the default rule, which is not its own: it loses its bucket.
+  l.SetRules(map[string]Rule{DefaultMethod: {Rate: 1, Burst: 2}})
+  if got := l.len(); got != 1 {
+    t.Fatalf("Kept %v buckets, want 1", got)
+  }
+}
+
+func TestParseRules(t *testing.T) {
+  rules, err := ParseRules(" /greet.GreetService/GreetManyTimes=0.5:2, *=20:40 ,")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(rules) != 2 || rules["/greet.GreetService/GreetManyTimes"] != (Rule{0.5, 2}) || rules[DefaultMethod] != (Rule{20, 40}) {
+    t.Fatalf("ParseRules() = %v", rules)
+  }
+  for _, s := range []string{"*", "=1:1", "*=1", "*=0:1", "*=1:0", "*=x:1"} {
+    if _, err := ParseRules(s); err == nil {
+      t.Errorf("ParseRules(%q) did not fail", s)
+    }
+  }
+}